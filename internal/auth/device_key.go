@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// deviceKeyPrefix marks device keys so a leaked one is easy to recognise
+const deviceKeyPrefix = "hpd_"
+
+// DeviceKeyPrefixLength is how much of a key is kept in the clear to tell
+// keys apart
+const DeviceKeyPrefixLength = 12
+
+// NewDeviceKey returns a random API key for a device. Like refresh tokens,
+// only its hash is stored
+func NewDeviceKey() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return deviceKeyPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashDeviceKey returns the form a device key is stored and looked up in
+func HashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+// Package auth handles credentials for the people using the API
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is the shortest password accepted for an account
+const MinPasswordLength = 8
+
+// ErrInvalidCredentials is returned when a password does not match its hash
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// unknownUserHash is the hash of a random password nobody knows, at
+// bcrypt.DefaultCost. RejectPassword compares against it
+const unknownUserHash = "$2a$10$x3bAQv9xpQ7QaIAJTfrj7OzEw97erjscrBy8t/z9Sr46qX7qzZjSi"
+
+// HashPassword returns the bcrypt hash stored for a password
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword compares a password against the stored hash
+func CheckPassword(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrInvalidCredentials
+	}
+	return err
+}
+
+// RejectPassword returns ErrInvalidCredentials after comparing password
+// against a hash, as CheckPassword does. Checking a login for an unknown
+// username with it takes as long as a wrong password for a known one, so
+// the time taken doesn't tell which usernames exist
+func RejectPassword(password string) error {
+	bcrypt.CompareHashAndPassword([]byte(unknownUserHash), []byte(password))
+	return ErrInvalidCredentials
+}
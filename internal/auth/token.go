@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinSecretLength is the shortest signing secret accepted, matching the
+// output size of the HMAC-SHA256 it keys
+const MinSecretLength = 32
+
+// ErrInvalidToken is returned for tokens that are malformed, signed with
+// another key or expired
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// tokenHeader is the fixed JOSE header of every access token. Tokens are
+// only ever HS256, so anything else is rejected outright
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims are the facts an access token carries about its holder
+type Claims struct {
+	Subject   string `json:"sub"` // User ID
+	Username  string `json:"name"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// UserID returns the ID of the user the token was issued to
+func (c Claims) UserID() int64 {
+	id, _ := strconv.ParseInt(c.Subject, 10, 64)
+	return id
+}
+
+// Tokens issues and verifies access tokens, which are JWTs signed with
+// HMAC-SHA256
+type Tokens struct {
+	secret []byte
+	// AccessTTL is how long an access token is accepted for
+	AccessTTL time.Duration
+	// RefreshTTL is how long a refresh token can be exchanged for a new
+	// access token
+	RefreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewTokens returns a Tokens signing with secret
+func NewTokens(secret string, accessTTL, refreshTTL time.Duration) (*Tokens, error) {
+	if len(secret) < MinSecretLength {
+		return nil, fmt.Errorf("token secret must be at least %d characters", MinSecretLength)
+	}
+	if accessTTL <= 0 || refreshTTL <= 0 {
+		return nil, fmt.Errorf("token lifetimes must be positive")
+	}
+	return &Tokens{secret: []byte(secret), AccessTTL: accessTTL, RefreshTTL: refreshTTL, now: time.Now}, nil
+}
+
+// Issue signs an access token for a user. The role is fixed for the life
+// of the token, so changing it only takes effect once the user's sessions
+// are ended
+func (t *Tokens) Issue(userID int64, username, role string) (string, Claims, error) {
+	now := t.now()
+	claims := Claims{
+		Subject:   strconv.FormatInt(userID, 10),
+		Username:  username,
+		Role:      role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(t.AccessTTL).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + t.sign(unsigned), claims, nil
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func (t *Tokens) Verify(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return Claims{}, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(t.sign(parts[0]+"."+parts[1]))) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	if claims.UserID() == 0 || t.now().Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (t *Tokens) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewRefreshToken returns a random refresh token. Only its hash is stored,
+// so a leaked sessions table can't be replayed
+func NewRefreshToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashRefreshToken returns the form a refresh token is stored and looked up in
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
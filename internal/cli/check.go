@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"healing_photons/internal/database"
+)
+
+// orphanChecks count records whose parent row is missing. Tables created
+// before the migrations existed have no foreign keys to stop this, and lots
+// whose free-text seller was blank could not be linked to a seller
+var orphanChecks = []struct {
+	description string
+	query       string
+}{
+	{"humidifier records without a stock", `
+		SELECT COUNT(*) FROM humidifier h
+		LEFT JOIN stock s ON s.stock_id = h.stock_id
+		WHERE s.stock_id IS NULL`},
+	{"peeling machine records without a humidifier record", `
+		SELECT COUNT(*) FROM peeling_machine pm
+		LEFT JOIN humidifier h ON h.id = pm.humidifier_id
+		WHERE h.id IS NULL`},
+	{"color sort records without a peeling machine record", `
+		SELECT COUNT(*) FROM color_sort cs
+		LEFT JOIN peeling_machine pm ON pm.id = cs.peel_id
+		WHERE cs.peel_id IS NOT NULL AND pm.id IS NULL`},
+	{"machine grading records without a color sort record", `
+		SELECT COUNT(*) FROM machine_grading mg
+		LEFT JOIN color_sort cs ON cs.id = mg.color_sort_id
+		WHERE cs.id IS NULL`},
+	{"machine grading inputs without a stock", `
+		SELECT COUNT(*) FROM machine_grading_inputs mgi
+		LEFT JOIN stock s ON s.stock_id = mgi.stock_id
+		WHERE s.stock_id IS NULL`},
+	{"manual grading records without a stock", `
+		SELECT COUNT(*) FROM manual_grading mg
+		LEFT JOIN stock s ON s.stock_id = mg.stock_id
+		WHERE s.stock_id IS NULL`},
+	{"stock lots without a seller", `
+		SELECT COUNT(*) FROM stock
+		WHERE seller_id IS NULL`},
+}
+
+// runCheck reports pending migrations and orphaned records, failing when
+// it finds either
+func runCheck(ctx context.Context, e *env, args []string) error {
+	if err := parseFlags(newFlagSet(e, "check"), args); err != nil {
+		return err
+	}
+
+	problems := 0
+
+	statuses, err := database.Migrations(ctx, e.db)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			fmt.Fprintf(e.stdout, "pending migration %04d_%s\n", status.Version, status.Name)
+			problems++
+		}
+	}
+
+	for _, check := range orphanChecks {
+		var count int
+		if err := e.db.QueryRowContext(ctx, check.query).Scan(&count); err != nil {
+			return fmt.Errorf("%s: %v", check.description, err)
+		}
+		if count > 0 {
+			fmt.Fprintf(e.stdout, "%d %s\n", count, check.description)
+			problems++
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problem(s)", problems)
+	}
+	fmt.Fprintln(e.stdout, "ok")
+	return nil
+}
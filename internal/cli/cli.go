@@ -0,0 +1,135 @@
+// Package cli implements the administrative subcommands of the
+// healing_photons binary so supervisors don't need ad-hoc SQL
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"healing_photons/internal/config"
+	"healing_photons/internal/database"
+	"healing_photons/internal/models"
+	"healing_photons/internal/plant"
+	"healing_photons/internal/store"
+	"healing_photons/internal/store/audited"
+	"healing_photons/internal/store/mysql"
+	"io"
+	"os"
+	"os/user"
+)
+
+// errUsage is returned by a command when its arguments are wrong; the
+// message has already been printed
+var errUsage = errors.New("usage")
+
+// env carries what every command needs to talk to the database and the
+// terminal
+type env struct {
+	db       *sql.DB
+	stores   *store.Stores
+	calendar plant.Calendar
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = []command{
+	{"migrate", "migrate [up|down|status|baseline] [-steps n] [-version n]", "apply, roll back, list or adopt schema migrations", runMigrate},
+	{"seed", "seed", "load reference data that is missing", runSeed},
+	{"export", "export [-format csv|json] [-o file] [-from date] [-to date]", "export stock lots", runExport},
+	{"check", "check", "verify the schema and look for orphaned records", runCheck},
+	{"user", "user create|passwd|role -username name [-role role] | user list", "manage accounts; passwords are read from stdin", runUser},
+}
+
+// Run executes the command named by args[0] and returns the process exit code
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == args[0] {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load configuration: %v\n", err)
+		return 1
+	}
+	db, err := database.InitializeDB(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	// Changes made by a command are audited under the operating system
+	// account that ran it
+	ctx = store.WithActor(ctx, models.Actor{Type: models.ActorCLI, Name: osUsername()})
+	e := &env{
+		db:       db,
+		stores:   audited.NewStores(mysql.NewStores(db)),
+		calendar: plant.Calendar{Location: cfg.PlantLocation, DayStart: cfg.PlantDayStart},
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+	}
+	if err := cmd.run(ctx, e, args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+// osUsername returns the name of the account running the command
+func osUsername() string {
+	if current, err := user.Current(); err == nil {
+		return current.Username
+	}
+	return os.Getenv("USER")
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: healing_photons [command]")
+	fmt.Fprintln(w, "\nWithout a command the API server is started.\n\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(w, "  %-10s   healing_photons %s\n", "", cmd.usage)
+	}
+}
+
+// newFlagSet returns a flag set that reports errors through errUsage
+func newFlagSet(e *env, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	return flags
+}
+
+// parseFlags parses args and maps flag errors to errUsage
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
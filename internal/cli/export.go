@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/plant"
+	"healing_photons/internal/store"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// runExport writes the stock lots, optionally limited to a receipt date
+// range, as CSV or JSON
+func runExport(ctx context.Context, e *env, args []string) error {
+	flags := newFlagSet(e, "export")
+	format := flags.String("format", "csv", "output format, csv or json")
+	output := flags.String("o", "", "file to write instead of stdout")
+	from := flags.String("from", "", "first receipt day to include (YYYY-MM-DD)")
+	to := flags.String("to", "", "last receipt day to include (YYYY-MM-DD)")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(e.stderr, "unknown format %q\n", *format)
+		return errUsage
+	}
+
+	opts, err := dateRange(e.calendar, *from, *to)
+	if err != nil {
+		return err
+	}
+	opts.Sort = store.Sort{Field: "date"}
+	page, err := e.stores.Stocks.List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	stocks := page.Items
+
+	var w io.Writer = e.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stocks)
+	}
+	return writeStockCSV(w, stocks)
+}
+
+// dateRange returns options covering the plant working days from to to,
+// both inclusive
+func dateRange(calendar plant.Calendar, from, to string) (store.ListOptions, error) {
+	var opts store.ListOptions
+	var err error
+	if from != "" {
+		if opts.From, _, err = calendar.Day(from, time.Now()); err != nil {
+			return opts, fmt.Errorf("invalid -from date %q", from)
+		}
+	}
+	if to != "" {
+		if _, opts.To, err = calendar.Day(to, time.Now()); err != nil {
+			return opts, fmt.Errorf("invalid -to date %q", to)
+		}
+	}
+	return opts, nil
+}
+
+func writeStockCSV(w io.Writer, stocks []models.Stock) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"stock_id", "seller_id", "seller_name", "origin_country", "weight", "promised_outturn", "date", "status", "created_at", "updated_at"})
+	for _, stock := range stocks {
+		sellerID, promised := "", ""
+		if stock.SellerID != nil {
+			sellerID = strconv.FormatInt(*stock.SellerID, 10)
+		}
+		if stock.PromisedOutturn != nil {
+			promised = strconv.FormatFloat(*stock.PromisedOutturn, 'f', -1, 64)
+		}
+		writer.Write([]string{
+			stock.StockID,
+			sellerID,
+			stock.SellerName,
+			stock.OriginCountry,
+			strconv.FormatFloat(float64(stock.Weight), 'f', -1, 32),
+			promised,
+			stock.Date.Format(time.RFC3339),
+			string(stock.Status),
+			stock.CreatedAt.Format(time.RFC3339),
+			stock.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"healing_photons/internal/database"
+)
+
+func runMigrate(ctx context.Context, e *env, args []string) error {
+	action := "up"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		action, args = args[0], args[1:]
+	}
+
+	flags := newFlagSet(e, "migrate")
+	steps := flags.Int("steps", 1, "number of migrations to roll back")
+	version := flags.Int64("version", 1, "last migration an existing schema already matches, for baseline")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	switch action {
+	case "up":
+		applied, err := database.MigrateUp(ctx, e.db)
+		for _, version := range applied {
+			fmt.Fprintf(e.stdout, "applied %d\n", version)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(e.stdout, "schema is up to date")
+		}
+		return nil
+
+	case "down":
+		reverted, err := database.MigrateDown(ctx, e.db, *steps)
+		for _, version := range reverted {
+			fmt.Fprintf(e.stdout, "rolled back %d\n", version)
+		}
+		return err
+
+	case "baseline":
+		recorded, err := database.Baseline(ctx, e.db, *version)
+		for _, version := range recorded {
+			fmt.Fprintf(e.stdout, "marked %d as applied\n", version)
+		}
+		return err
+
+	case "status":
+		statuses, err := database.Migrations(ctx, e.db)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(e.stdout, "%04d  %-30s %s\n", status.Version, status.Name, applied)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(e.stderr, "unknown migrate action %q\n", action)
+	return errUsage
+}
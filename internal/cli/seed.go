@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strings"
+)
+
+// Reference data every plant needs before lots can be processed
+var (
+	seedWeightTypes = []string{"Whole", "Pieces", "Rejection"}
+
+	// Whole kernel counts per pound
+	seedSizeVariations = []int{180, 210, 240, 320, 450, 500}
+
+	seedPieces = []models.Pieces{
+		{PieceCode: "B", Description: validString("Butts")},
+		{PieceCode: "S", Description: validString("Splits")},
+		{PieceCode: "LWP", Description: validString("Large white pieces")},
+		{PieceCode: "SWP", Description: validString("Small white pieces")},
+		{PieceCode: "BB", Description: validString("Baby bits")},
+	}
+
+	// One category per field of models.GradeCategory
+	seedGradingCategories = []models.GradingCategory{
+		{CategoryCode: "WHOLE", Description: validString("Whole kernels")},
+		{CategoryCode: "A", Description: validString("K grade")},
+		{CategoryCode: "SW", Description: validString("LWP grade")},
+		{CategoryCode: "SSW", Description: validString("SWP grade")},
+		{CategoryCode: "TW", Description: validString("BB grade")},
+		{CategoryCode: "JB", Description: validString("BBNP grade")},
+		{CategoryCode: "KW", Description: validString("Husk weight")},
+	}
+
+	seedGraderMachineOutputs = []models.GraderMachineOutputs{
+		{ID: "WHOLE", Type: "Whole kernels"},
+		{ID: "PIECES", Type: "Pieces"},
+		{ID: "REJECT", Type: "Rejects"},
+	}
+)
+
+func validString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: true}
+}
+
+// runSeed inserts the reference rows that are missing and leaves existing
+// rows untouched, so it is safe to run repeatedly
+func runSeed(ctx context.Context, e *env, args []string) error {
+	if err := parseFlags(newFlagSet(e, "seed"), args); err != nil {
+		return err
+	}
+
+	steps := []struct {
+		table string
+		seed  func(context.Context, *env) (int, int, error)
+	}{
+		{"weight_types", seedWeightTypeRows},
+		{"size_variations", seedSizeVariationRows},
+		{"pieces", seedPieceRows},
+		{"grading_categories", seedGradingCategoryRows},
+		{"grader_machine_outputs", seedGraderMachineOutputRows},
+	}
+	for _, step := range steps {
+		added, present, err := step.seed(ctx, e)
+		if err != nil {
+			return fmt.Errorf("%s: %v", step.table, err)
+		}
+		fmt.Fprintf(e.stdout, "%-24s %d added, %d already present\n", step.table, added, present)
+	}
+	return nil
+}
+
+func seedWeightTypeRows(ctx context.Context, e *env) (int, int, error) {
+	existing, err := e.stores.WeightTypes.List(ctx, store.All())
+	if err != nil {
+		return 0, 0, err
+	}
+	have := make(map[string]bool)
+	for _, weightType := range existing.Items {
+		have[strings.ToLower(weightType.Type)] = true
+	}
+
+	added := 0
+	for _, name := range seedWeightTypes {
+		if have[strings.ToLower(name)] {
+			continue
+		}
+		if err := e.stores.WeightTypes.Create(ctx, &models.WeightTypes{Type: name}); err != nil {
+			return added, 0, err
+		}
+		added++
+	}
+	return added, len(seedWeightTypes) - added, nil
+}
+
+func seedSizeVariationRows(ctx context.Context, e *env) (int, int, error) {
+	existing, err := e.stores.SizeVariations.List(ctx, store.All())
+	if err != nil {
+		return 0, 0, err
+	}
+	have := make(map[int]bool)
+	for _, variation := range existing.Items {
+		have[variation.SizeValue] = true
+	}
+
+	added := 0
+	for _, size := range seedSizeVariations {
+		if have[size] {
+			continue
+		}
+		if err := e.stores.SizeVariations.Create(ctx, &models.SizeVariations{SizeValue: size}); err != nil {
+			return added, 0, err
+		}
+		added++
+	}
+	return added, len(seedSizeVariations) - added, nil
+}
+
+func seedPieceRows(ctx context.Context, e *env) (int, int, error) {
+	existing, err := e.stores.Pieces.List(ctx, store.All())
+	if err != nil {
+		return 0, 0, err
+	}
+	have := make(map[string]bool)
+	for _, piece := range existing.Items {
+		have[strings.ToUpper(piece.PieceCode)] = true
+	}
+
+	added := 0
+	for _, piece := range seedPieces {
+		if have[piece.PieceCode] {
+			continue
+		}
+		if err := e.stores.Pieces.Create(ctx, &piece); err != nil {
+			return added, 0, err
+		}
+		added++
+	}
+	return added, len(seedPieces) - added, nil
+}
+
+func seedGradingCategoryRows(ctx context.Context, e *env) (int, int, error) {
+	existing, err := e.stores.GradingCategories.List(ctx, store.All())
+	if err != nil {
+		return 0, 0, err
+	}
+	have := make(map[string]bool)
+	for _, category := range existing.Items {
+		have[strings.ToUpper(category.CategoryCode)] = true
+	}
+
+	added := 0
+	for _, category := range seedGradingCategories {
+		if have[category.CategoryCode] {
+			continue
+		}
+		if err := e.stores.GradingCategories.Create(ctx, &category); err != nil {
+			return added, 0, err
+		}
+		added++
+	}
+	return added, len(seedGradingCategories) - added, nil
+}
+
+func seedGraderMachineOutputRows(ctx context.Context, e *env) (int, int, error) {
+	existing, err := e.stores.GraderMachineOutputs.List(ctx, store.All())
+	if err != nil {
+		return 0, 0, err
+	}
+	have := make(map[string]bool)
+	for _, output := range existing.Items {
+		have[output.ID] = true
+	}
+
+	added := 0
+	for _, output := range seedGraderMachineOutputs {
+		if have[output.ID] {
+			continue
+		}
+		if err := e.stores.GraderMachineOutputs.Create(ctx, &output); err != nil {
+			return added, 0, err
+		}
+		added++
+	}
+	return added, len(seedGraderMachineOutputs) - added, nil
+}
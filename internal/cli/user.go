@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"healing_photons/internal/auth"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strings"
+)
+
+// runUser creates accounts, resets passwords, assigns roles and lists
+// users. Passwords are read from the first line of stdin so they never
+// appear in shell history
+func runUser(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(e.stderr, "user needs an action: create, passwd, role or list")
+		return errUsage
+	}
+	action, args := args[0], args[1:]
+
+	flags := newFlagSet(e, "user "+action)
+	username := flags.String("username", "", "account username")
+	role := flags.String("role", string(models.RoleOperator), "operator, grader, supervisor or admin")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if !models.Role(*role).Valid() {
+		fmt.Fprintf(e.stderr, "unknown role %q\n", *role)
+		return errUsage
+	}
+
+	switch action {
+	case "list":
+		users, err := e.stores.Users.List(ctx)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			fmt.Fprintf(e.stdout, "%d\t%s\t%s\t%s\n", user.ID, user.Username, user.Role, user.CreatedAt.Format("2006-01-02"))
+		}
+		return nil
+
+	case "create", "passwd":
+		if strings.TrimSpace(*username) == "" {
+			fmt.Fprintln(e.stderr, "-username is required")
+			return errUsage
+		}
+		hash, err := readPasswordHash(e)
+		if err != nil {
+			return err
+		}
+
+		if action == "create" {
+			user := models.User{Username: strings.TrimSpace(*username), PasswordHash: hash, Role: models.Role(*role)}
+			if err := e.stores.Users.Create(ctx, &user); err != nil {
+				return err
+			}
+			fmt.Fprintf(e.stdout, "created %s %s (id %d)\n", user.Role, user.Username, user.ID)
+			return nil
+		}
+
+		user, err := e.stores.Users.GetByUsername(ctx, strings.TrimSpace(*username))
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("no user named %q", *username)
+		}
+		if err != nil {
+			return err
+		}
+		user.PasswordHash = hash
+		if err := e.stores.Users.Update(ctx, user.ID, user); err != nil {
+			return err
+		}
+		// Whoever knew the old password must sign in again
+		if err := e.stores.Sessions.RevokeUser(ctx, user.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "password updated for %s, existing sessions ended\n", user.Username)
+		return nil
+
+	case "role":
+		if strings.TrimSpace(*username) == "" {
+			fmt.Fprintln(e.stderr, "-username is required")
+			return errUsage
+		}
+		user, err := e.stores.Users.GetByUsername(ctx, strings.TrimSpace(*username))
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("no user named %q", *username)
+		}
+		if err != nil {
+			return err
+		}
+		user.Role = models.Role(*role)
+		if err := e.stores.Users.Update(ctx, user.ID, user); err != nil {
+			return err
+		}
+		// Access tokens carry the role, so refreshing must not keep the old one
+		if err := e.stores.Sessions.RevokeUser(ctx, user.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "%s is now %s, existing sessions ended\n", user.Username, user.Role)
+		return nil
+	}
+
+	fmt.Fprintf(e.stderr, "unknown user action %q\n", action)
+	return errUsage
+}
+
+func readPasswordHash(e *env) (string, error) {
+	fmt.Fprint(e.stderr, "Password: ")
+	line, err := bufio.NewReader(e.stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read password: %v", err)
+	}
+	fmt.Fprintln(e.stderr)
+	return auth.HashPassword(strings.TrimRight(line, "\r\n"))
+}
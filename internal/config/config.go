@@ -2,7 +2,13 @@ package config
 
 import (
 	"fmt"
+	"healing_photons/internal/ids"
+	"healing_photons/internal/reports"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // the plant zone must resolve on hosts without zoneinfo
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +22,44 @@ type Config struct {
 	Port       string
 	CA         string
 	UseSSL     string
+
+	// AutoMigrate applies pending schema migrations on startup
+	AutoMigrate bool
+
+	// PlantLocation is the plant's time zone, used for working days and for
+	// the timestamps returned by the API
+	PlantLocation *time.Location
+	// PlantDayStart is the time of day the plant's working day begins
+	PlantDayStart time.Duration
+
+	// TokenSecret signs access tokens. The server refuses to start without
+	// one; admin commands don't need it
+	TokenSecret string
+	// AccessTokenTTL and RefreshTokenTTL bound how long a login lasts
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// IdempotencyTTL is how long the response to a request sent with an
+	// Idempotency-Key is kept for retries
+	IdempotencyTTL time.Duration
+	// IdempotencyLease is how long a request may hold its Idempotency-Key
+	// before a retry takes it over. It should be longer than any request
+	// takes to handle
+	IdempotencyLease time.Duration
+	// IdempotencySweep is how often keys older than IdempotencyTTL are
+	// deleted
+	IdempotencySweep time.Duration
+
+	// CORSOrigins lists the browser origins allowed to call the API. When
+	// empty any origin may call it, but without credentials
+	CORSOrigins []string
+
+	// IDScheme says how the server mints the IDs of stock lots and stage
+	// records, and whether clients may still choose their own
+	IDScheme ids.Scheme
+
+	// Plausibility says whether stage records that weigh more than the
+	// stage before them allows are refused or flagged as anomalies
+	Plausibility reports.Plausibility
 }
 
 // LoadConfig reads configuration from .env file and environment variables
@@ -38,6 +82,87 @@ func LoadConfig() (*Config, error) {
 		UseSSL:     os.Getenv("USE_SSL"),
 	}
 
+	if raw := os.Getenv("AUTO_MIGRATE"); raw != "" {
+		autoMigrate, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTO_MIGRATE value %q", raw)
+		}
+		cfg.AutoMigrate = autoMigrate
+	}
+
+	zone := os.Getenv("PLANT_TIMEZONE")
+	if zone == "" {
+		zone = "Asia/Kolkata"
+	}
+	location, err := time.LoadLocation(zone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PLANT_TIMEZONE %q: %w", zone, err)
+	}
+	cfg.PlantLocation = location
+
+	if raw := os.Getenv("PLANT_DAY_START"); raw != "" {
+		start, err := time.Parse("15:04", raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PLANT_DAY_START %q, expected HH:MM", raw)
+		}
+		cfg.PlantDayStart = time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	}
+
+	cfg.TokenSecret = os.Getenv("TOKEN_SECRET")
+	if cfg.AccessTokenTTL, err = durationEnv("ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshTokenTTL, err = durationEnv("REFRESH_TOKEN_TTL", 7*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.IdempotencyTTL, err = durationEnv("IDEMPOTENCY_TTL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.IdempotencyLease, err = durationEnv("IDEMPOTENCY_LEASE", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.IdempotencySweep, err = durationEnv("IDEMPOTENCY_SWEEP", time.Hour); err != nil {
+		return nil, err
+	}
+
+	for _, origin := range strings.Split(os.Getenv("CORS_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			cfg.CORSOrigins = append(cfg.CORSOrigins, origin)
+		}
+	}
+
+	cfg.IDScheme = ids.DefaultScheme()
+	for _, entity := range ids.Entities {
+		name := strings.ToUpper(entity) + "_ID_PATTERN"
+		if raw := os.Getenv(name); raw != "" {
+			pattern, err := ids.ParsePattern(entity, raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			cfg.IDScheme.Patterns[entity] = pattern
+		}
+	}
+	if raw := os.Getenv("CLIENT_IDS"); raw != "" {
+		clientIDs, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLIENT_IDS value %q", raw)
+		}
+		cfg.IDScheme.ClientIDs = clientIDs
+	}
+
+	cfg.Plausibility = reports.DefaultPlausibility()
+	if raw := os.Getenv("PLAUSIBILITY_CHECKS"); raw != "" {
+		if cfg.Plausibility.Mode, err = reports.ParsePlausibilityMode(raw); err != nil {
+			return nil, fmt.Errorf("invalid PLAUSIBILITY_CHECKS: %w", err)
+		}
+	}
+	if cfg.Plausibility.MoistureGain, err = percentEnv("MOISTURE_GAIN_PERCENT", cfg.Plausibility.MoistureGain); err != nil {
+		return nil, err
+	}
+	if cfg.Plausibility.Tolerance, err = percentEnv("WEIGHT_TOLERANCE_PERCENT", cfg.Plausibility.Tolerance); err != nil {
+		return nil, err
+	}
+
 	// Validate required configurations
 	if cfg.DBUsername == "" || cfg.DBPassword == "" ||
 		cfg.DBHost == "" || cfg.DBName == "" {
@@ -46,3 +171,31 @@ func LoadConfig() (*Config, error) {
 
 	return cfg, nil
 }
+
+// durationEnv reads a duration such as "15m" from the environment, falling
+// back to def when the variable is unset
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a duration such as 15m", name, raw)
+	}
+	return value, nil
+}
+
+// percentEnv reads a percentage such as "2.5" from the environment, falling
+// back to def when the variable is unset
+func percentEnv(name string, def float64) (float64, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q, expected a percentage such as 2.5", name, raw)
+	}
+	return value, nil
+}
@@ -0,0 +1,382 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationFiles holds the schema history. Files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock is the MySQL named lock held while migrations run so two
+// instances starting together don't apply the same version twice
+const migrationLock = "healing_photons_schema_migrations"
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// LoadMigrations returns the embedded migrations ordered by version
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %v", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		base, direction, ok := cutDirection(fileName)
+		if !ok {
+			return nil, fmt.Errorf("migration %s must end in .up.sql or .down.sql", fileName)
+		}
+
+		rawVersion, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>", fileName)
+		}
+		version, err := strconv.ParseInt(rawVersion, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s has an invalid version", fileName)
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join("migrations", fileName))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %v", fileName, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MigrateUp applies every migration that has not been applied yet and
+// returns the versions it ran
+func MigrateUp(ctx context.Context, db *sql.DB) ([]int64, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []int64
+	err = withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			untracked, err := hasTable(ctx, conn, "stock")
+			if err != nil {
+				return err
+			}
+			if untracked {
+				return fmt.Errorf("schema was created before migrations were tracked; run migrate baseline first")
+			}
+		}
+
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := execScript(ctx, conn, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %v", migration.Version, migration.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+				migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+			}
+			ran = append(ran, migration.Version)
+		}
+		return nil
+	})
+	return ran, err
+}
+
+// MigrateDown rolls back the most recently applied migrations, at most
+// steps of them, and returns the versions it reverted
+func MigrateDown(ctx context.Context, db *sql.DB, steps int) ([]int64, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive")
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var reverted []int64
+	err = withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for _, version := range versions {
+			if len(reverted) == steps {
+				break
+			}
+			migration, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("applied migration %d is not known to this binary", version)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+			}
+			if err := execScript(ctx, conn, migration.Down); err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %v", migration.Version, migration.Name, err)
+			}
+			if _, err := conn.ExecContext(ctx,
+				"DELETE FROM schema_migrations WHERE version = ?", version); err != nil {
+				return fmt.Errorf("failed to unrecord migration %d: %v", version, err)
+			}
+			reverted = append(reverted, version)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them. It adopts a database whose schema was created before migrations
+// were tracked, so that MigrateUp carries on from the version the schema
+// already matches instead of failing on tables that exist. It refuses a
+// database that already records migrations, or that has no stock table
+// and so nothing to adopt
+func Baseline(ctx context.Context, db *sql.DB, version int64) ([]int64, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	known := false
+	for _, migration := range migrations {
+		known = known || migration.Version == version
+	}
+	if !known {
+		return nil, fmt.Errorf("migration %d is not known to this binary", version)
+	}
+
+	var recorded []int64
+	err = withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) > 0 {
+			return fmt.Errorf("database already records %d applied migration(s); baseline only adopts an untracked schema", len(applied))
+		}
+
+		adoptable, err := hasTable(ctx, conn, "stock")
+		if err != nil {
+			return err
+		}
+		if !adoptable {
+			return fmt.Errorf("database has no stock table to adopt; run migrate up instead")
+		}
+
+		for _, migration := range migrations {
+			if migration.Version > version {
+				break
+			}
+			if _, err := conn.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, name) VALUES (?, ?)",
+				migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("failed to record migration %d: %v", migration.Version, err)
+			}
+			recorded = append(recorded, migration.Version)
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// Migrations reports every known migration and when it was applied
+func Migrations(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// withMigrationLock runs fn on a single connection holding the migration lock
+func withMigrationLock(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 60)", migrationLock).Scan(&acquired); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %v", err)
+	}
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("timed out waiting for migration lock")
+	}
+	defer conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", migrationLock)
+
+	return fn(conn)
+}
+
+// appliedVersions creates the bookkeeping table if needed and returns the
+// applied versions with their timestamps
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (version)
+		) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// hasTable reports whether the current database has the table name
+func hasTable(ctx context.Context, conn *sql.Conn, name string) (bool, error) {
+	var tables int
+	err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM information_schema.tables
+		WHERE table_schema = DATABASE() AND table_name = ?`, name).Scan(&tables)
+	return tables > 0, err
+}
+
+// execScript runs each statement of a migration script in turn. MySQL
+// commits DDL implicitly, so scripts are not wrapped in a transaction
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, statement := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements breaks a script on semicolons that end a line, dropping
+// blank lines and -- comments
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(current.String()), ";")
+			statements = append(statements, statement)
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// cutDirection splits "0001_name.up.sql" into "0001_name" and "up"
+func cutDirection(fileName string) (string, string, bool) {
+	if base, ok := strings.CutSuffix(fileName, ".up.sql"); ok {
+		return base, "up", true
+	}
+	if base, ok := strings.CutSuffix(fileName, ".down.sql"); ok {
+		return base, "down", true
+	}
+	return "", "", false
+}
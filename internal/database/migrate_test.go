@@ -0,0 +1,64 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, migration := range migrations {
+		if want := int64(i + 1); migration.Version != want {
+			t.Errorf("migration %d_%s, want version %d: versions must run without gaps", migration.Version, migration.Name, want)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		if len(splitStatements(migration.Up)) == 0 {
+			t.Errorf("migration %d_%s up script has no statements", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- A comment; with a semicolon
+CREATE TABLE a (
+    id INT -- trailing comment
+);
+
+UPDATE a SET id = 1;
+INSERT INTO a VALUES (2)`
+
+	want := []string{
+		"CREATE TABLE a (\n    id INT -- trailing comment\n)",
+		"UPDATE a SET id = 1",
+		"INSERT INTO a VALUES (2)",
+	}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements = %q, want %q", got, want)
+	}
+}
+
+func TestCutDirection(t *testing.T) {
+	tests := []struct {
+		file, base, direction string
+		ok                    bool
+	}{
+		{"0001_initial_schema.up.sql", "0001_initial_schema", "up", true},
+		{"0017_stock_date_plant_zone.down.sql", "0017_stock_date_plant_zone", "down", true},
+		{"0001_initial_schema.sql", "", "", false},
+	}
+	for _, tt := range tests {
+		base, direction, ok := cutDirection(tt.file)
+		if base != tt.base || direction != tt.direction || ok != tt.ok {
+			t.Errorf("cutDirection(%q) = %q, %q, %v", tt.file, base, direction, ok)
+		}
+	}
+}
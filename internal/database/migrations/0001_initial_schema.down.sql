@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS manual_grading;
+DROP TABLE IF EXISTS machine_grading_inputs;
+DROP TABLE IF EXISTS machine_grading;
+DROP TABLE IF EXISTS color_sort;
+DROP TABLE IF EXISTS peeling_machine;
+DROP TABLE IF EXISTS humidifier;
+DROP TABLE IF EXISTS stock;
+DROP TABLE IF EXISTS workforce;
+DROP TABLE IF EXISTS grader_machine_outputs;
+DROP TABLE IF EXISTS grading_categories;
+DROP TABLE IF EXISTS pieces;
+DROP TABLE IF EXISTS size_variations;
+DROP TABLE IF EXISTS weight_types;
@@ -0,0 +1,160 @@
+-- The schema as it stood before migrations were tracked. A database that
+-- already has these tables adopts them with "migrate baseline" instead of
+-- running this script, and picks up from 0002
+
+-- Reference tables
+
+CREATE TABLE weight_types (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    type VARCHAR(50) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_weight_types_type (type)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE size_variations (
+    size_id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    size_value INT NOT NULL,
+    PRIMARY KEY (size_id),
+    UNIQUE KEY uq_size_variations_value (size_value)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE pieces (
+    piece_id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    piece_code VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NULL,
+    PRIMARY KEY (piece_id),
+    UNIQUE KEY uq_pieces_code (piece_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE grading_categories (
+    category_id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    category_code VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NULL,
+    PRIMARY KEY (category_id),
+    UNIQUE KEY uq_grading_categories_code (category_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE grader_machine_outputs (
+    id VARCHAR(64) NOT NULL,
+    type VARCHAR(100) NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE workforce (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    aadhaar BIGINT UNSIGNED NOT NULL,
+    address VARCHAR(500) NOT NULL DEFAULT '',
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_workforce_aadhaar (aadhaar)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Raw nut lots
+
+CREATE TABLE stock (
+    stock_id VARCHAR(64) NOT NULL,
+    seller_name VARCHAR(255) NOT NULL,
+    origin_country VARCHAR(100) NOT NULL,
+    weight DECIMAL(12,3) NOT NULL,
+    date DATETIME NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (stock_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Processing stages, in plant order
+
+CREATE TABLE humidifier (
+    id VARCHAR(64) NOT NULL,
+    stock_id VARCHAR(64) NOT NULL,
+    weight DECIMAL(12,3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_humidifier_stock (stock_id, created_at),
+    CONSTRAINT fk_humidifier_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE peeling_machine (
+    id VARCHAR(64) NOT NULL,
+    humidifier_id VARCHAR(64) NOT NULL,
+    stock_id VARCHAR(64) NULL,
+    weight_type_id INT UNSIGNED NOT NULL,
+    weight DECIMAL(12,3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_peeling_machine_stock (stock_id, created_at),
+    CONSTRAINT fk_peeling_machine_humidifier FOREIGN KEY (humidifier_id) REFERENCES humidifier (id),
+    CONSTRAINT fk_peeling_machine_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id),
+    CONSTRAINT fk_peeling_machine_weight_type FOREIGN KEY (weight_type_id) REFERENCES weight_types (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE color_sort (
+    id VARCHAR(64) NOT NULL,
+    peel_id VARCHAR(64) NULL,
+    stock_id VARCHAR(64) NULL,
+    weight_type_id INT UNSIGNED NOT NULL,
+    accepted_weight DECIMAL(12,3) NOT NULL,
+    sort_counter INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_color_sort_stock_counter (stock_id, sort_counter, created_at),
+    CONSTRAINT fk_color_sort_peeling_machine FOREIGN KEY (peel_id) REFERENCES peeling_machine (id),
+    CONSTRAINT fk_color_sort_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id),
+    CONSTRAINT fk_color_sort_weight_type FOREIGN KEY (weight_type_id) REFERENCES weight_types (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE machine_grading (
+    id VARCHAR(64) NOT NULL,
+    color_sort_id VARCHAR(64) NOT NULL,
+    stock_id VARCHAR(64) NOT NULL,
+    size_variations_id INT UNSIGNED NULL,
+    pieces_id INT UNSIGNED NULL,
+    weight DECIMAL(12,3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_machine_grading_stock (stock_id, created_at),
+    CONSTRAINT fk_machine_grading_color_sort FOREIGN KEY (color_sort_id) REFERENCES color_sort (id),
+    CONSTRAINT fk_machine_grading_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id),
+    CONSTRAINT fk_machine_grading_size FOREIGN KEY (size_variations_id) REFERENCES size_variations (size_id),
+    CONSTRAINT fk_machine_grading_piece FOREIGN KEY (pieces_id) REFERENCES pieces (piece_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE machine_grading_inputs (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    stock_id VARCHAR(64) NOT NULL,
+    worker_id INT UNSIGNED NOT NULL,
+    size_variations_id INT UNSIGNED NULL,
+    weight DECIMAL(12,3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_machine_grading_inputs_stock (stock_id, created_at),
+    CONSTRAINT fk_machine_grading_inputs_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id),
+    CONSTRAINT fk_machine_grading_inputs_worker FOREIGN KEY (worker_id) REFERENCES workforce (id),
+    CONSTRAINT fk_machine_grading_inputs_size FOREIGN KEY (size_variations_id) REFERENCES size_variations (size_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE manual_grading (
+    id VARCHAR(64) NOT NULL,
+    grader_machine_outputs_id VARCHAR(64) NOT NULL,
+    stock_id VARCHAR(64) NOT NULL,
+    category_id INT UNSIGNED NULL,
+    size_id INT UNSIGNED NOT NULL,
+    piece_id INT UNSIGNED NULL,
+    weight INT NOT NULL,
+    worker_id INT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_manual_grading_stock (stock_id, created_at),
+    CONSTRAINT fk_manual_grading_output FOREIGN KEY (grader_machine_outputs_id) REFERENCES grader_machine_outputs (id),
+    CONSTRAINT fk_manual_grading_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id),
+    CONSTRAINT fk_manual_grading_category FOREIGN KEY (category_id) REFERENCES grading_categories (category_id),
+    CONSTRAINT fk_manual_grading_size FOREIGN KEY (size_id) REFERENCES size_variations (size_id),
+    CONSTRAINT fk_manual_grading_piece FOREIGN KEY (piece_id) REFERENCES pieces (piece_id),
+    CONSTRAINT fk_manual_grading_worker FOREIGN KEY (worker_id) REFERENCES workforce (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS stock_transitions;
+
+ALTER TABLE stock
+    DROP KEY idx_stock_status,
+    DROP COLUMN status;
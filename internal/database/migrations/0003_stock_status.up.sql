@@ -0,0 +1,27 @@
+ALTER TABLE stock
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'received' AFTER date,
+    ADD KEY idx_stock_status (status);
+
+-- Place existing lots at the furthest stage they have records for
+UPDATE stock s
+SET s.status = CASE
+    WHEN EXISTS (SELECT 1 FROM manual_grading mg WHERE mg.stock_id = s.stock_id)
+        OR EXISTS (SELECT 1 FROM machine_grading_inputs mgi WHERE mgi.stock_id = s.stock_id) THEN 'manual_grading'
+    WHEN EXISTS (SELECT 1 FROM machine_grading mg WHERE mg.stock_id = s.stock_id) THEN 'machine_grading'
+    WHEN EXISTS (SELECT 1 FROM color_sort cs WHERE cs.stock_id = s.stock_id) THEN 'color_sorting'
+    WHEN EXISTS (SELECT 1 FROM peeling_machine pm WHERE pm.stock_id = s.stock_id) THEN 'peeling'
+    WHEN EXISTS (SELECT 1 FROM humidifier h WHERE h.stock_id = s.stock_id) THEN 'humidifying'
+    ELSE 'received'
+END;
+
+CREATE TABLE stock_transitions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    stock_id VARCHAR(64) NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_stock_transitions_stock (stock_id, created_at),
+    CONSTRAINT fk_stock_transitions_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
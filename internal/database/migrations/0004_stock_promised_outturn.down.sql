@@ -0,0 +1,2 @@
+ALTER TABLE stock
+    DROP COLUMN promised_outturn;
@@ -0,0 +1,3 @@
+-- Outturn the seller promised, in lbs of kernel per 80 kg bag
+ALTER TABLE stock
+    ADD COLUMN promised_outturn DECIMAL(5,2) NULL AFTER weight;
@@ -0,0 +1,5 @@
+ALTER TABLE stock
+    DROP FOREIGN KEY fk_stock_seller,
+    DROP COLUMN seller_id;
+
+DROP TABLE sellers;
@@ -0,0 +1,35 @@
+CREATE TABLE sellers (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(255) NOT NULL,
+    -- Lower-cased name with whitespace collapsed, so spellings that differ
+    -- only in case or spacing are the same seller
+    name_key VARCHAR(255) NOT NULL,
+    contact_name VARCHAR(255) NOT NULL DEFAULT '',
+    phone VARCHAR(32) NOT NULL DEFAULT '',
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    gstin CHAR(15) NULL,
+    country CHAR(2) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_sellers_name_key (name_key),
+    UNIQUE KEY uq_sellers_gstin (gstin)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- One seller per distinct free-text name on existing lots. Their contact
+-- details and country are left for the office to fill in
+INSERT INTO sellers (name, name_key)
+SELECT MIN(REGEXP_REPLACE(TRIM(seller_name), '[[:space:]]+', ' ')),
+    LOWER(REGEXP_REPLACE(TRIM(seller_name), '[[:space:]]+', ' '))
+FROM stock
+WHERE TRIM(seller_name) <> ''
+GROUP BY 2;
+
+ALTER TABLE stock
+    ADD COLUMN seller_id INT UNSIGNED NULL AFTER stock_id,
+    ADD CONSTRAINT fk_stock_seller FOREIGN KEY (seller_id) REFERENCES sellers (id);
+
+UPDATE stock s
+JOIN sellers se ON se.name_key = LOWER(REGEXP_REPLACE(TRIM(s.seller_name), '[[:space:]]+', ' '))
+SET s.seller_id = se.id,
+    s.seller_name = se.name;
@@ -0,0 +1 @@
+DROP TABLE grading_sheets;
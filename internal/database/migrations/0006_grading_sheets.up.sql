@@ -0,0 +1,21 @@
+CREATE TABLE grading_sheets (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    stock_id VARCHAR(64) NOT NULL,
+    worker_id INT UNSIGNED NOT NULL,
+    size_variations_id INT UNSIGNED NOT NULL,
+    whole DECIMAL(12,3) NOT NULL DEFAULT 0,
+    a DECIMAL(12,3) NOT NULL DEFAULT 0,
+    sw DECIMAL(12,3) NOT NULL DEFAULT 0,
+    ssw DECIMAL(12,3) NOT NULL DEFAULT 0,
+    tw DECIMAL(12,3) NOT NULL DEFAULT 0,
+    jb DECIMAL(12,3) NOT NULL DEFAULT 0,
+    kw DECIMAL(12,3) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    -- A worker hands in one sheet per size category of a lot
+    UNIQUE KEY uq_grading_sheets_worker_size (stock_id, worker_id, size_variations_id),
+    CONSTRAINT fk_grading_sheets_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id),
+    CONSTRAINT fk_grading_sheets_worker FOREIGN KEY (worker_id) REFERENCES workforce (id),
+    CONSTRAINT fk_grading_sheets_size FOREIGN KEY (size_variations_id) REFERENCES size_variations (size_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
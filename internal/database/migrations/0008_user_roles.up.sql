@@ -0,0 +1,5 @@
+-- What each account is allowed to do; see handlers.RoutePolicy. Existing
+-- accounts start with the least privilege and are promoted with
+-- `user role`
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'operator' AFTER password_hash;
@@ -0,0 +1,17 @@
+ALTER TABLE machine_grading
+    DROP FOREIGN KEY fk_machine_grading_device,
+    DROP COLUMN device_id;
+
+ALTER TABLE color_sort
+    DROP FOREIGN KEY fk_color_sort_device,
+    DROP COLUMN device_id;
+
+ALTER TABLE peeling_machine
+    DROP FOREIGN KEY fk_peeling_machine_device,
+    DROP COLUMN device_id;
+
+ALTER TABLE humidifier
+    DROP FOREIGN KEY fk_humidifier_device,
+    DROP COLUMN device_id;
+
+DROP TABLE devices;
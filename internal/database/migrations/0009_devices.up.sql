@@ -0,0 +1,38 @@
+-- Scales and tablets on the shop floor that post weighings with an API key
+-- instead of signing in. Only a SHA-256 hash of each key is kept. After a
+-- rotation the previous key keeps working until previous_key_expires_at so
+-- the device can be reconfigured without losing weighings
+CREATE TABLE devices (
+    id INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name VARCHAR(100) NOT NULL,
+    stages SET('humidifying', 'peeling', 'color_sorting', 'machine_grading') NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    previous_key_hash CHAR(64) NULL,
+    previous_key_expires_at TIMESTAMP NULL,
+    key_rotated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_devices_name (name),
+    UNIQUE KEY uq_devices_key_hash (key_hash),
+    KEY idx_devices_previous_key_hash (previous_key_hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- The device that posted each stage record, NULL when a user did
+ALTER TABLE humidifier
+    ADD COLUMN device_id INT UNSIGNED NULL AFTER weight,
+    ADD CONSTRAINT fk_humidifier_device FOREIGN KEY (device_id) REFERENCES devices (id);
+
+ALTER TABLE peeling_machine
+    ADD COLUMN device_id INT UNSIGNED NULL AFTER weight,
+    ADD CONSTRAINT fk_peeling_machine_device FOREIGN KEY (device_id) REFERENCES devices (id);
+
+ALTER TABLE color_sort
+    ADD COLUMN device_id INT UNSIGNED NULL AFTER sort_counter,
+    ADD CONSTRAINT fk_color_sort_device FOREIGN KEY (device_id) REFERENCES devices (id);
+
+ALTER TABLE machine_grading
+    ADD COLUMN device_id INT UNSIGNED NULL AFTER weight,
+    ADD CONSTRAINT fk_machine_grading_device FOREIGN KEY (device_id) REFERENCES devices (id);
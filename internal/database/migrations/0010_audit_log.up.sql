@@ -0,0 +1,18 @@
+-- Every change made through the API or the admin commands, with the record
+-- as it was before and after. Rows are only ever inserted
+CREATE TABLE audit_log (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    actor_type VARCHAR(16) NOT NULL,
+    actor_id INT UNSIGNED NULL,
+    actor_name VARCHAR(100) NOT NULL DEFAULT '',
+    entity VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(64) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    before_json JSON NULL,
+    after_json JSON NULL,
+    created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (id),
+    KEY idx_audit_log_entity (entity, entity_id, id),
+    KEY idx_audit_log_actor (actor_type, actor_id, created_at),
+    KEY idx_audit_log_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,41 @@
+ALTER TABLE manual_grading
+    DROP FOREIGN KEY fk_manual_grading_corrects,
+    DROP INDEX uq_manual_grading_correction,
+    DROP COLUMN reason,
+    DROP COLUMN corrects_id,
+    DROP COLUMN entry_type;
+
+ALTER TABLE machine_grading_inputs
+    DROP FOREIGN KEY fk_machine_grading_inputs_corrects,
+    DROP INDEX uq_machine_grading_inputs_correction,
+    DROP COLUMN reason,
+    DROP COLUMN corrects_id,
+    DROP COLUMN entry_type;
+
+ALTER TABLE machine_grading
+    DROP FOREIGN KEY fk_machine_grading_corrects,
+    DROP INDEX uq_machine_grading_correction,
+    DROP COLUMN reason,
+    DROP COLUMN corrects_id,
+    DROP COLUMN entry_type;
+
+ALTER TABLE color_sort
+    DROP FOREIGN KEY fk_color_sort_corrects,
+    DROP INDEX uq_color_sort_correction,
+    DROP COLUMN reason,
+    DROP COLUMN corrects_id,
+    DROP COLUMN entry_type;
+
+ALTER TABLE peeling_machine
+    DROP FOREIGN KEY fk_peeling_machine_corrects,
+    DROP INDEX uq_peeling_machine_correction,
+    DROP COLUMN reason,
+    DROP COLUMN corrects_id,
+    DROP COLUMN entry_type;
+
+ALTER TABLE humidifier
+    DROP FOREIGN KEY fk_humidifier_corrects,
+    DROP INDEX uq_humidifier_correction,
+    DROP COLUMN reason,
+    DROP COLUMN corrects_id,
+    DROP COLUMN entry_type;
@@ -0,0 +1,46 @@
+-- Stage weights are append only. A correction is posted as a reversal that
+-- negates the weight of the record it corrects, plus an optional replacement
+-- that takes its place. Both point at the corrected record through
+-- corrects_id and carry the reason, so summing a lot's rows gives its
+-- current weight. The unique keys stop a record being corrected twice
+ALTER TABLE humidifier
+    ADD COLUMN entry_type VARCHAR(12) NOT NULL DEFAULT 'original' AFTER device_id,
+    ADD COLUMN corrects_id VARCHAR(64) NULL AFTER entry_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER corrects_id,
+    ADD UNIQUE KEY uq_humidifier_correction (corrects_id, entry_type),
+    ADD CONSTRAINT fk_humidifier_corrects FOREIGN KEY (corrects_id) REFERENCES humidifier (id);
+
+ALTER TABLE peeling_machine
+    ADD COLUMN entry_type VARCHAR(12) NOT NULL DEFAULT 'original' AFTER device_id,
+    ADD COLUMN corrects_id VARCHAR(64) NULL AFTER entry_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER corrects_id,
+    ADD UNIQUE KEY uq_peeling_machine_correction (corrects_id, entry_type),
+    ADD CONSTRAINT fk_peeling_machine_corrects FOREIGN KEY (corrects_id) REFERENCES peeling_machine (id);
+
+ALTER TABLE color_sort
+    ADD COLUMN entry_type VARCHAR(12) NOT NULL DEFAULT 'original' AFTER device_id,
+    ADD COLUMN corrects_id VARCHAR(64) NULL AFTER entry_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER corrects_id,
+    ADD UNIQUE KEY uq_color_sort_correction (corrects_id, entry_type),
+    ADD CONSTRAINT fk_color_sort_corrects FOREIGN KEY (corrects_id) REFERENCES color_sort (id);
+
+ALTER TABLE machine_grading
+    ADD COLUMN entry_type VARCHAR(12) NOT NULL DEFAULT 'original' AFTER device_id,
+    ADD COLUMN corrects_id VARCHAR(64) NULL AFTER entry_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER corrects_id,
+    ADD UNIQUE KEY uq_machine_grading_correction (corrects_id, entry_type),
+    ADD CONSTRAINT fk_machine_grading_corrects FOREIGN KEY (corrects_id) REFERENCES machine_grading (id);
+
+ALTER TABLE machine_grading_inputs
+    ADD COLUMN entry_type VARCHAR(12) NOT NULL DEFAULT 'original' AFTER weight,
+    ADD COLUMN corrects_id INT UNSIGNED NULL AFTER entry_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER corrects_id,
+    ADD UNIQUE KEY uq_machine_grading_inputs_correction (corrects_id, entry_type),
+    ADD CONSTRAINT fk_machine_grading_inputs_corrects FOREIGN KEY (corrects_id) REFERENCES machine_grading_inputs (id);
+
+ALTER TABLE manual_grading
+    ADD COLUMN entry_type VARCHAR(12) NOT NULL DEFAULT 'original' AFTER worker_id,
+    ADD COLUMN corrects_id VARCHAR(64) NULL AFTER entry_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER corrects_id,
+    ADD UNIQUE KEY uq_manual_grading_correction (corrects_id, entry_type),
+    ADD CONSTRAINT fk_manual_grading_corrects FOREIGN KEY (corrects_id) REFERENCES manual_grading (id);
@@ -0,0 +1,44 @@
+ALTER TABLE grading_categories
+    DROP INDEX idx_grading_categories_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE workforce
+    DROP INDEX idx_workforce_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE weight_types
+    DROP INDEX idx_weight_types_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE grader_machine_outputs
+    DROP INDEX idx_grader_machine_outputs_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE size_variations
+    DROP INDEX idx_size_variations_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE pieces
+    DROP INDEX idx_pieces_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE grading_sheets
+    DROP INDEX idx_grading_sheets_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE sellers
+    DROP INDEX idx_sellers_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE stock
+    DROP INDEX idx_stock_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
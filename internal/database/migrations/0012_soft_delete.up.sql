@@ -0,0 +1,48 @@
+-- Deleting a master record or stock lot moves it to the trash instead of
+-- removing the row: deleted_at and deleted_by are set and every read
+-- leaves it out until it is restored or purged. Stage weights are not
+-- listed here as they are never deleted, only corrected
+ALTER TABLE stock
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_stock_deleted (deleted_at);
+
+ALTER TABLE sellers
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_sellers_deleted (deleted_at);
+
+ALTER TABLE grading_sheets
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_grading_sheets_deleted (deleted_at);
+
+ALTER TABLE pieces
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_pieces_deleted (deleted_at);
+
+ALTER TABLE size_variations
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_size_variations_deleted (deleted_at);
+
+ALTER TABLE grader_machine_outputs
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_grader_machine_outputs_deleted (deleted_at);
+
+ALTER TABLE weight_types
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_weight_types_deleted (deleted_at);
+
+ALTER TABLE workforce
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_workforce_deleted (deleted_at);
+
+ALTER TABLE grading_categories
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_grading_categories_deleted (deleted_at);
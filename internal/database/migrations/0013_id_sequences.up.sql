@@ -0,0 +1,11 @@
+-- Counters for the IDs the server mints for stock lots and stage records.
+-- Each row is one scope, such as every lot received in a year or every
+-- color sort pass of one lot, named after the pattern with everything but
+-- the sequence number filled in. Incrementing the row takes its lock, so
+-- two requests never get the same number
+CREATE TABLE id_sequences (
+    name VARCHAR(191) NOT NULL,
+    value BIGINT UNSIGNED NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
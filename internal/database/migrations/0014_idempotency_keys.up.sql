@@ -0,0 +1,14 @@
+-- Idempotency-Key headers of create requests and the responses they got,
+-- so a client retrying after a dropped connection gets the original
+-- response back instead of creating the record twice. Keys belong to the
+-- caller that sent them; status_code stays 0 while the request is running
+CREATE TABLE idempotency_keys (
+    caller VARCHAR(64) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    response MEDIUMBLOB NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (caller, idempotency_key),
+    KEY idx_idempotency_keys_created (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
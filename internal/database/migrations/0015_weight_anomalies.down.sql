@@ -0,0 +1 @@
+DROP TABLE weight_anomalies;
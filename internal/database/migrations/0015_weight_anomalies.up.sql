@@ -0,0 +1,17 @@
+-- Stage records that took a lot's output at their stage past what the
+-- stage before it allows, kept when plausibility checks flag writes
+-- instead of blocking them. Rows are only ever inserted
+CREATE TABLE weight_anomalies (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    stock_id VARCHAR(64) NOT NULL,
+    stage VARCHAR(64) NOT NULL,
+    record_id VARCHAR(64) NOT NULL,
+    weight DECIMAL(12,3) NOT NULL,
+    upstream_stage VARCHAR(64) NOT NULL,
+    upstream_weight DECIMAL(12,3) NOT NULL,
+    allowed_weight DECIMAL(12,3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    KEY idx_weight_anomalies_stock (stock_id, id),
+    CONSTRAINT fk_weight_anomalies_stock FOREIGN KEY (stock_id) REFERENCES stock (stock_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
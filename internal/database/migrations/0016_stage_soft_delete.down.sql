@@ -0,0 +1,29 @@
+ALTER TABLE manual_grading
+    DROP INDEX idx_manual_grading_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE machine_grading_inputs
+    DROP INDEX idx_machine_grading_inputs_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE machine_grading
+    DROP INDEX idx_machine_grading_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE color_sort
+    DROP INDEX idx_color_sort_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE peeling_machine
+    DROP INDEX idx_peeling_machine_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE humidifier
+    DROP INDEX idx_humidifier_deleted,
+    DROP COLUMN deleted_by,
+    DROP COLUMN deleted_at;
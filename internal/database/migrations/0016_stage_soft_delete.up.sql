@@ -0,0 +1,35 @@
+-- Deleting a stage record moves it to the trash like the master records
+-- in 0012: deleted_at and deleted_by are set and every read, list and
+-- weight total leaves it out until it is restored or purged. Only an
+-- original that nothing corrects can be deleted, so a reversal or
+-- replacement never outlives the record it belongs to; anything else is
+-- corrected instead
+ALTER TABLE humidifier
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_humidifier_deleted (deleted_at);
+
+ALTER TABLE peeling_machine
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_peeling_machine_deleted (deleted_at);
+
+ALTER TABLE color_sort
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_color_sort_deleted (deleted_at);
+
+ALTER TABLE machine_grading
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_machine_grading_deleted (deleted_at);
+
+ALTER TABLE machine_grading_inputs
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_machine_grading_inputs_deleted (deleted_at);
+
+ALTER TABLE manual_grading
+    ADD COLUMN deleted_at TIMESTAMP NULL,
+    ADD COLUMN deleted_by VARCHAR(100) NULL,
+    ADD KEY idx_manual_grading_deleted (deleted_at);
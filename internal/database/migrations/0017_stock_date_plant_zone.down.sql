@@ -0,0 +1,2 @@
+UPDATE stock
+SET date = CONVERT_TZ(date, @@session.time_zone, '+00:00');
@@ -0,0 +1,8 @@
+-- The connection now reads and writes timestamps in the plant's zone, see
+-- database.InitializeDB. TIMESTAMP columns are stored in UTC and converted
+-- by the session time_zone, so their rows read back unchanged. stock.date
+-- is the only DATETIME: it is stored as written, and rows written before
+-- the change hold the UTC wall clock. Shift them to the plant's wall
+-- clock, the session time_zone this migration runs in
+UPDATE stock
+SET date = CONVERT_TZ(date, '+00:00', @@session.time_zone);
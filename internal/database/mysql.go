@@ -4,18 +4,16 @@ import (
 	"database/sql"
 	"fmt"
 	"healing_photons/internal/config"
+	"net/url"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 // InitializeDB establishes a connection to the MySQL database
 func InitializeDB(cfg *config.Config) (*sql.DB, error) {
-	// Construct connection string
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&tls=%s&parseTime=true",
-		cfg.DBUsername, cfg.DBPassword, cfg.DBHost, cfg.Port, cfg.DBName, cfg.UseSSL)
-
 	// Open database connection
-	db, err := sql.Open("mysql", connectionString)
+	db, err := sql.Open("mysql", dataSourceName(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
@@ -27,3 +25,31 @@ func InitializeDB(cfg *config.Config) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// dataSourceName builds the connection string. Timestamps are read and
+// written in the plant's zone so they reach clients with the plant's UTC
+// offset. TIMESTAMP columns are kept in UTC whatever the zone; DATETIME
+// ones hold the plant's wall clock, see migration 0017. clientFoundRows
+// makes an UPDATE report the rows it matched rather than the rows it
+// changed, so saving a record unchanged isn't mistaken for a missing one
+func dataSourceName(cfg *config.Config) string {
+	location := cfg.PlantLocation
+	if location == nil {
+		location = time.UTC
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&tls=%s&parseTime=true&clientFoundRows=true&loc=%s&time_zone=%s",
+		cfg.DBUsername, cfg.DBPassword, cfg.DBHost, cfg.Port, cfg.DBName, cfg.UseSSL,
+		url.QueryEscape(location.String()), url.QueryEscape(sessionTimeZone(location)))
+}
+
+// sessionTimeZone returns the MySQL time_zone value matching location. It is
+// the zone's current UTC offset, which is exact for zones without daylight
+// saving such as Asia/Kolkata and doesn't need MySQL's time zone tables
+func sessionTimeZone(location *time.Location) string {
+	_, offset := time.Now().In(location).Zone()
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("'%c%02d:%02d'", sign, offset/3600, offset%3600/60)
+}
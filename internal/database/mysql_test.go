@@ -0,0 +1,35 @@
+package database
+
+import (
+	"healing_photons/internal/config"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+func TestDataSourceName(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	dsn, err := mysqldriver.ParseDSN(dataSourceName(&config.Config{
+		DBUsername: "app", DBPassword: "secret", DBHost: "db", Port: "3306", DBName: "plant",
+		UseSSL: "false", PlantLocation: kolkata,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without clientFoundRows an UPDATE that changes nothing reports no
+	// rows, which the stores take to mean the record is gone
+	if !dsn.ClientFoundRows {
+		t.Error("clientFoundRows is off")
+	}
+	if !dsn.ParseTime || dsn.Loc.String() != "Asia/Kolkata" {
+		t.Errorf("parseTime = %v, loc = %v, want times parsed in the plant zone", dsn.ParseTime, dsn.Loc)
+	}
+	if got := dsn.Params["time_zone"]; got != "'+05:30'" {
+		t.Errorf("time_zone = %s, want '+05:30'", got)
+	}
+}
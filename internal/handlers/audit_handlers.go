@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLog - Get the recorded changes, oldest first. With ?entity= and
+// ?id= it is the history of a single record, e.g.
+// /audit?entity=color_sort&id=CS1
+func GetAuditLog(c *gin.Context, audit store.AuditStore) {
+	opts, err := parseListOptions(c, store.AuditList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	entity, hasEntity := c.GetQuery("entity")
+	if hasEntity && !slices.Contains(models.AuditEntities, entity) {
+		respondWithStatus(c, http.StatusBadRequest, "Unknown entity %q; use one of %v", entity, models.AuditEntities)
+		return
+	}
+	if id, ok := c.GetQuery("id"); ok {
+		if !hasEntity {
+			respondWithStatus(c, http.StatusBadRequest, "entity is required with id")
+			return
+		}
+		opts.Filters = append(opts.Filters, store.Filter{Field: "entity_id", Value: id})
+	}
+
+	page, err := audit.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithPage(c, store.AuditList, page)
+}
+
+// SetupAuditRoutes - Setup the routes for reading the audit log
+func SetupAuditRoutes(router *gin.Engine, stores *store.Stores) {
+	audit := stores.Audit
+	router.GET("/audit", func(c *gin.Context) { GetAuditLog(c, audit) })
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"healing_photons/internal/store/audited"
+	"healing_photons/internal/store/memory"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newAuditedRouter serves the seller and audit routes through audited
+// stores, returning the memory stores beneath them
+func newAuditedRouter() (*gin.Engine, *store.Stores) {
+	gin.SetMode(gin.TestMode)
+	stores := memory.NewStores()
+	router := gin.New()
+	logged := audited.NewStores(stores)
+	SetupSellerRoutes(router, logged)
+	SetupAuditRoutes(router, logged)
+	return router, stores
+}
+
+// failingAudit refuses every entry it is asked to record
+type failingAudit struct {
+	store.AuditStore
+}
+
+func (failingAudit) Record(context.Context, *models.AuditEntry) error {
+	return errors.New("audit log unavailable")
+}
+
+func TestAuditLogsBeforeAndAfter(t *testing.T) {
+	router, _ := newAuditedRouter()
+
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	tag := send(router, http.MethodGet, "/sellers/1", "").Header().Get("ETag")
+	expect(t, send(router, http.MethodPatch, "/sellers/1", `{"name":"Sree Traders Pvt"}`, "If-Match", tag), http.StatusOK)
+
+	w := send(router, http.MethodGet, "/audit?entity="+models.EntitySeller, "")
+	expect(t, w, http.StatusOK)
+	entries := decode[struct{ Data []models.AuditEntry }](t, w).Data
+	if len(entries) != 2 || entries[0].Action != models.AuditCreate || entries[1].Action != models.AuditUpdate {
+		t.Fatalf("entries = %+v, want a create then an update", entries)
+	}
+
+	var before, after models.Seller
+	if err := json.Unmarshal(entries[1].Before, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(entries[1].After, &after); err != nil {
+		t.Fatal(err)
+	}
+	if before.Name != "Sree Traders" || after.Name != "Sree Traders Pvt" {
+		t.Errorf("update logged %q -> %q, want the name before and after", before.Name, after.Name)
+	}
+}
+
+func TestAuditFailureRollsBackChange(t *testing.T) {
+	router, stores := newAuditedRouter()
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	tag := send(router, http.MethodGet, "/sellers/1", "").Header().Get("ETag")
+
+	// The audited stores read Audit from the stores each unit is given
+	stores.Audit = failingAudit{stores.Audit}
+
+	expect(t, send(router, http.MethodPost, "/sellers", `{"name":"Kerala Cashews","country":"IN"}`), http.StatusInternalServerError)
+	expect(t, send(router, http.MethodPatch, "/sellers/1", `{"name":"Sree Traders Pvt"}`, "If-Match", tag), http.StatusInternalServerError)
+	expect(t, send(router, http.MethodDelete, "/sellers/1", "", "If-Match", tag), http.StatusInternalServerError)
+
+	sellers, err := stores.Sellers.List(context.Background(), store.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sellers.Items) != 1 || sellers.Items[0].Name != "Sree Traders" {
+		t.Errorf("sellers = %+v, want only the first, unchanged", sellers.Items)
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"healing_photons/internal/auth"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const claimsKey = "auth_claims"
+
+// LoginRequest is the body accepted by Login
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshRequest is the body accepted by Refresh and Logout
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse is returned when a session starts or is refreshed
+type TokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	ExpiresIn        int64       `json:"expires_in"` // Seconds the access token is valid for
+	RefreshToken     string      `json:"refresh_token"`
+	RefreshExpiresAt time.Time   `json:"refresh_expires_at"`
+	User             models.User `json:"user"`
+}
+
+// Login - Exchange a username and password for an access and refresh token
+func Login(c *gin.Context, users store.UserStore, sessions store.SessionStore, tokens *auth.Tokens) {
+	var request LoginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	user, err := users.GetByUsername(c.Request.Context(), request.Username)
+	if errors.Is(err, store.ErrNotFound) {
+		err = auth.RejectPassword(request.Password)
+	} else if err == nil {
+		err = auth.CheckPassword(user.PasswordHash, request.Password)
+	}
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		respondWithStatus(c, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	startSession(c, sessions, tokens, user)
+}
+
+// Refresh - Exchange a refresh token for a new token pair. Each refresh
+// token works once; presenting a used one ends all of the user's sessions
+func Refresh(c *gin.Context, users store.UserStore, sessions store.SessionStore, tokens *auth.Tokens) {
+	var request RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	session, err := sessions.GetByTokenHash(c.Request.Context(), auth.HashRefreshToken(request.RefreshToken))
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if !time.Now().Before(session.ExpiresAt) {
+		respondWithStatus(c, http.StatusUnauthorized, "Session expired, please sign in again")
+		return
+	}
+
+	err = sessions.Revoke(c.Request.Context(), session.ID)
+	if errors.Is(err, store.ErrConflict) {
+		// A refresh token that was already spent has probably been copied
+		if err := sessions.RevokeUser(c.Request.Context(), session.UserID); err != nil {
+			respondWithError(c, err)
+			return
+		}
+		respondWithStatus(c, http.StatusUnauthorized, "Refresh token was already used, please sign in again")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	user, err := users.Get(c.Request.Context(), session.UserID)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusUnauthorized, "Invalid refresh token")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	startSession(c, sessions, tokens, user)
+}
+
+// Logout - End the session a refresh token belongs to
+func Logout(c *gin.Context, sessions store.SessionStore) {
+	var request RefreshRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	session, err := sessions.GetByTokenHash(c.Request.Context(), auth.HashRefreshToken(request.RefreshToken))
+	if err == nil {
+		err = sessions.Revoke(c.Request.Context(), session.ID)
+	}
+	// Logging out twice, or with an unknown token, leaves nothing to end
+	if err != nil && !errors.Is(err, store.ErrNotFound) && !errors.Is(err, store.ErrConflict) {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// GetCurrentUser - Get the account of the caller
+func GetCurrentUser(c *gin.Context, users store.UserStore) {
+	claims, _ := currentClaims(c)
+
+	user, err := users.Get(c.Request.Context(), claims.UserID())
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "User not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// startSession records a new session for user and responds with its tokens
+func startSession(c *gin.Context, sessions store.SessionStore, tokens *auth.Tokens, user models.User) {
+	accessToken, claims, err := tokens.Issue(user.ID, user.Username, string(user.Role))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	refreshToken, err := auth.NewRefreshToken()
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	session := models.Session{
+		UserID:    user.ID,
+		TokenHash: auth.HashRefreshToken(refreshToken),
+		ExpiresAt: time.Now().Add(tokens.RefreshTTL),
+	}
+	if err := sessions.Create(c.Request.Context(), &session); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        claims.ExpiresAt - claims.IssuedAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             user,
+	})
+}
+
+// RequireAuth rejects requests without a valid bearer access token and
+// makes the caller's claims available to the handlers after it. Requests
+// already signed in by AuthenticateDevice are let through
+func RequireAuth(tokens *auth.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := currentDevice(c); ok {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			abortWithError(c, reject(http.StatusUnauthorized, "Authentication required"))
+			return
+		}
+
+		claims, err := tokens.Verify(token)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			abortWithError(c, reject(http.StatusUnauthorized, "Invalid or expired access token"))
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		userID := claims.UserID()
+		actor := models.Actor{Type: models.ActorUser, ID: &userID, Name: claims.Username}
+		c.Request = c.Request.WithContext(store.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// currentClaims returns the claims of the caller set by RequireAuth
+func currentClaims(c *gin.Context) (auth.Claims, bool) {
+	if claims, ok := c.Get(claimsKey); ok {
+		return claims.(auth.Claims), true
+	}
+	return auth.Claims{}, false
+}
+
+// SetupAuthRoutes - Setup the routes for signing in and out. They must be
+// registered before RequireAuth is added to the router, which guards only
+// the routes registered after it
+func SetupAuthRoutes(router *gin.Engine, stores *store.Stores, tokens *auth.Tokens) {
+	users := stores.Users
+	sessions := stores.Sessions
+	router.POST("/auth/login", func(c *gin.Context) { Login(c, users, sessions, tokens) })
+	router.POST("/auth/refresh", func(c *gin.Context) { Refresh(c, users, sessions, tokens) })
+	router.POST("/auth/logout", func(c *gin.Context) { Logout(c, sessions) })
+	router.GET("/auth/me", RequireAuth(tokens), func(c *gin.Context) { GetCurrentUser(c, users) })
+}
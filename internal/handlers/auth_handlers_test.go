@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"healing_photons/internal/auth"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"healing_photons/internal/store/memory"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testPassword = "correct horse"
+
+// newAuthRouter wires every route behind sign in and the route policy the
+// way the server does, with an operator and an admin account
+func newAuthRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	tokens, err := auth.NewTokens("a test secret that is long enough to sign with", time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := memory.NewStores()
+	hash, err := auth.HashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, user := range []models.User{
+		{Username: "olive", PasswordHash: hash, Role: models.RoleOperator},
+		{Username: "ada", PasswordHash: hash, Role: models.RoleAdmin},
+	} {
+		if err := stores.Users.Create(context.Background(), &user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	router := gin.New()
+	SetupAuthRoutes(router, stores, tokens)
+	router.Use(AuthenticateDevice(stores.Devices))
+	router.Use(RequireAuth(tokens))
+	router.Use(Authorize(RoutePolicy))
+	setupAllRoutes(router, stores)
+	return router
+}
+
+// setupAllRoutes registers the routes the server serves behind sign in
+func setupAllRoutes(router *gin.Engine, stores *store.Stores) {
+	SetupRoutes(router, stores)
+	SetupWeightTypeRoutes(router, stores)
+	SetupPeelingMachineRoutes(router, stores)
+	SetupHumidifierRoutes(router, stores)
+	SetupGraderMachineOutputRoutes(router, stores)
+	SetupMachineGradingRoutes(router, stores)
+	SetupColorSortRoutes(router, stores)
+	SetupPiecesRoutes(router, stores)
+	SetupSizeVariationsRoutes(router, stores)
+	SetupManualGradingRoutes(router, stores)
+	SetupManualGradingInputRoutes(router, stores)
+	SetupWorkforceRoutes(router, stores)
+	SetupGradingCategoryRoutes(router, stores)
+	SetupOutturnRoutes(router, stores)
+	SetupSellerRoutes(router, stores)
+	SetupGradingSheetRoutes(router, stores)
+	SetupDeviceRoutes(router, stores)
+	SetupAuditRoutes(router, stores)
+	SetupTrashRoutes(router, stores)
+}
+
+// login signs username in and returns its tokens
+func login(t *testing.T, router http.Handler, username string) TokenResponse {
+	t.Helper()
+	w := send(router, http.MethodPost, "/auth/login", `{"username":"`+username+`","password":"`+testPassword+`"}`)
+	expect(t, w, http.StatusOK)
+	return decode[TokenResponse](t, w)
+}
+
+// bearer returns the Authorization header pair for an access token
+func bearer(tokens TokenResponse) []string {
+	return []string{"Authorization", "Bearer " + tokens.AccessToken}
+}
+
+func TestLogin(t *testing.T) {
+	router := newAuthRouter(t)
+
+	expect(t, send(router, http.MethodPost, "/auth/login", `{"username":"olive","password":"wrong password"}`), http.StatusUnauthorized)
+	// An unknown username is refused the same way as a wrong password
+	w := send(router, http.MethodPost, "/auth/login", `{"username":"nobody","password":"`+testPassword+`"}`)
+	expect(t, w, http.StatusUnauthorized)
+	if got := decode[errorResponse](t, w).Error.Message; got != "Invalid username or password" {
+		t.Errorf("message = %q, want the one a wrong password gets", got)
+	}
+
+	tokens := login(t, router, "olive")
+	w = send(router, http.MethodGet, "/auth/me", "", bearer(tokens)...)
+	expect(t, w, http.StatusOK)
+	if me := decode[models.User](t, w); me.Username != "olive" || me.Role != models.RoleOperator {
+		t.Errorf("me = %+v, want operator olive", me)
+	}
+	expect(t, send(router, http.MethodGet, "/auth/me", ""), http.StatusUnauthorized)
+}
+
+func TestRefreshTokenWorksOnce(t *testing.T) {
+	router := newAuthRouter(t)
+	first := login(t, router, "olive")
+
+	w := send(router, http.MethodPost, "/auth/refresh", `{"refresh_token":"`+first.RefreshToken+`"}`)
+	expect(t, w, http.StatusOK)
+	second := decode[TokenResponse](t, w)
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("refresh handed back the same refresh token")
+	}
+
+	// Spending a token twice ends every session of the user
+	expect(t, send(router, http.MethodPost, "/auth/refresh", `{"refresh_token":"`+first.RefreshToken+`"}`), http.StatusUnauthorized)
+	expect(t, send(router, http.MethodPost, "/auth/refresh", `{"refresh_token":"`+second.RefreshToken+`"}`), http.StatusUnauthorized)
+
+	third := login(t, router, "olive")
+	expect(t, send(router, http.MethodPost, "/auth/logout", `{"refresh_token":"`+third.RefreshToken+`"}`), http.StatusOK)
+	expect(t, send(router, http.MethodPost, "/auth/refresh", `{"refresh_token":"`+third.RefreshToken+`"}`), http.StatusUnauthorized)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxBatchSize caps the records in one bulk create
+const maxBatchSize = 500
+
+// Bulk create modes, chosen with ?mode=
+const (
+	// modeAllOrNothing creates every record or none of them
+	modeAllOrNothing = "all_or_nothing"
+	// modeBestEffort creates the records that can be and rejects the rest
+	modeBestEffort = "best_effort"
+)
+
+// Outcomes of one record in a bulk create
+const (
+	batchCreated  = "created"
+	batchRejected = "rejected"
+	// batchSkipped records were fine but not created, because another
+	// record failed an all-or-nothing batch
+	batchSkipped = "skipped"
+)
+
+// batchResult is the outcome of one record of a bulk create
+type batchResult struct {
+	Index  int        `json:"index"`
+	Status string     `json:"status"`
+	Record any        `json:"record,omitempty"`
+	Error  *errorBody `json:"error,omitempty"`
+}
+
+// batchResponse answers a bulk create with one result per record, in the
+// order they were sent
+type batchResponse struct {
+	Mode     string        `json:"mode"`
+	Created  int           `json:"created"`
+	Rejected int           `json:"rejected"`
+	Results  []batchResult `json:"results"`
+}
+
+// createBatch creates the array of records in the request body. Each is
+// checked as its single create would check it, then the valid ones are
+// inserted in one transaction. In all_or_nothing mode, the default, one
+// rejected record means none are created; in best_effort mode the others
+// still are. The answer is 201 when every record was created, 207 when
+// only some were and 422 when none were
+func createBatch[T any](c *gin.Context, prepare func(*T) error, createMany func(context.Context, []*T, bool) ([]error, error)) {
+	mode := c.DefaultQuery("mode", modeAllOrNothing)
+	if mode != modeAllOrNothing && mode != modeBestEffort {
+		respondWithStatus(c, http.StatusBadRequest, "mode must be %s or %s", modeAllOrNothing, modeBestEffort)
+		return
+	}
+	atomic := mode == modeAllOrNothing
+
+	var items []json.RawMessage
+	if err := c.ShouldBindJSON(&items); err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Body must be a JSON array of records")
+		return
+	}
+	if len(items) == 0 || len(items) > maxBatchSize {
+		respondWithStatus(c, http.StatusBadRequest, "Send between 1 and %d records", maxBatchSize)
+		return
+	}
+
+	response := batchResponse{Mode: mode, Results: make([]batchResult, len(items))}
+	var records []*T
+	var indexes []int
+	for i, item := range items {
+		response.Results[i] = batchResult{Index: i}
+		record := new(T)
+		if err := json.Unmarshal(item, record); err != nil {
+			response.reject(i, invalid(err))
+			continue
+		}
+		if err := binding.Validator.ValidateStruct(record); err != nil {
+			response.reject(i, invalid(err))
+			continue
+		}
+		err := prepare(record)
+		if status, _ := describe(err); err != nil && status == http.StatusInternalServerError {
+			respondWithError(c, err)
+			return
+		}
+		if err != nil {
+			response.reject(i, err)
+			continue
+		}
+		records = append(records, record)
+		indexes = append(indexes, i)
+	}
+
+	var errs []error
+	if len(records) > 0 && (!atomic || response.Rejected == 0) {
+		var err error
+		if errs, err = createMany(c.Request.Context(), records, atomic); err != nil {
+			respondWithError(c, err)
+			return
+		}
+		for j, err := range errs {
+			if status, _ := describe(err); err != nil && status == http.StatusInternalServerError {
+				c.Error(err)
+			}
+			if err != nil {
+				response.reject(indexes[j], err)
+			}
+		}
+	}
+	for j, record := range records {
+		result := &response.Results[indexes[j]]
+		if result.Status != "" {
+			continue
+		}
+		if atomic && response.Rejected > 0 {
+			result.Status = batchSkipped
+			continue
+		}
+		result.Status, result.Record = batchCreated, record
+		response.Created++
+	}
+
+	status := http.StatusMultiStatus
+	if response.Rejected == 0 {
+		status = http.StatusCreated
+	}
+	if response.Created == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, response)
+}
+
+// reject marks the record at index as rejected for err, described as it
+// would be answered on its own
+func (r *batchResponse) reject(index int, err error) {
+	_, body := describe(err)
+	r.Results[index].Status, r.Results[index].Error = batchRejected, &body
+	r.Rejected++
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+func TestBulkCreateAllOrNothing(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+
+	w := send(router, http.MethodPost, "/humidifiers/bulk", `[{"stock_id":"L1","weight":300},{"stock_id":"L9","weight":200}]`)
+	expect(t, w, http.StatusUnprocessableEntity)
+	response := decode[batchResponse](t, w)
+	if response.Mode != modeAllOrNothing || response.Created != 0 || response.Rejected != 1 ||
+		response.Results[0].Status != batchSkipped || response.Results[1].Status != batchRejected {
+		t.Fatalf("response = %+v, want the good record skipped and the bad one rejected", response)
+	}
+	expect(t, send(router, http.MethodGet, "/humidifiers/stock/L1", ""), http.StatusNotFound)
+
+	w = send(router, http.MethodPost, "/humidifiers/bulk", `[{"stock_id":"L1","weight":300},{"stock_id":"L1","weight":200}]`)
+	expect(t, w, http.StatusCreated)
+	if response := decode[batchResponse](t, w); response.Created != 2 {
+		t.Errorf("created %d, want 2", response.Created)
+	}
+}
+
+func TestBulkCreateBestEffort(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+
+	// The unknown lot is refused while checking, the repeated ID by the store
+	w := send(router, http.MethodPost, "/humidifiers/bulk?mode=best_effort",
+		`[{"id":"H1","stock_id":"L1","weight":300},{"stock_id":"L9","weight":200},{"id":"H1","stock_id":"L1","weight":100}]`)
+	expect(t, w, http.StatusMultiStatus)
+	response := decode[batchResponse](t, w)
+	if response.Created != 1 || response.Rejected != 2 || response.Results[0].Status != batchCreated {
+		t.Fatalf("response = %+v, want only the first record created", response)
+	}
+	if duplicate := response.Results[2].Error; duplicate == nil || duplicate.Code != codeDuplicate {
+		t.Errorf("repeated ID error = %+v, want %s", duplicate, codeDuplicate)
+	}
+
+	w = send(router, http.MethodGet, "/humidifiers/stock/L1", "")
+	expect(t, w, http.StatusOK)
+	if total := decode[struct{ Total int }](t, w).Total; total != 1 {
+		t.Errorf("stored %d records, want 1", total)
+	}
+}
+
+func TestBulkCreateRejectsBadBatches(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+
+	expect(t, send(router, http.MethodPost, "/humidifiers/bulk?mode=some", `[{"stock_id":"L1","weight":300}]`), http.StatusBadRequest)
+	expect(t, send(router, http.MethodPost, "/humidifiers/bulk", `[]`), http.StatusBadRequest)
+	expect(t, send(router, http.MethodPost, "/humidifiers/bulk", `{"stock_id":"L1","weight":300}`), http.StatusBadRequest)
+}
@@ -1,293 +1,198 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllColorSorts - Get all color sort records
-func GetAllColorSorts(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, peel_id, stock_id, weight_type_id, accepted_weight, 
-               sort_counter, created_at, updated_at 
-        FROM color_sort`)
+func GetAllColorSorts(c *gin.Context, colorSorts store.ColorSortStore) {
+	opts, err := parseListOptions(c, store.ColorSortList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
 
-	var colorSorts []models.ColorSort
-	for rows.Next() {
-		var colorSort models.ColorSort
-		if err := rows.Scan(
-			&colorSort.ID,
-			&colorSort.PeelID,
-			&colorSort.StockID,
-			&colorSort.WeightTypeID,
-			&colorSort.AcceptedWeight,
-			&colorSort.SortCounter,
-			&colorSort.CreatedAt,
-			&colorSort.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		colorSorts = append(colorSorts, colorSort)
+	page, err := colorSorts.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, colorSorts)
+	respondWithPage(c, store.ColorSortList, page)
 }
 
 // GetColorSort - Get single color sort record
-func GetColorSort(c *gin.Context, db *sql.DB) {
+func GetColorSort(c *gin.Context, colorSorts store.ColorSortStore) {
 	id := c.Param("id")
 
-	var colorSort models.ColorSort
-	err := db.QueryRow(`
-        SELECT id, peel_id, stock_id, weight_type_id, accepted_weight, 
-               sort_counter, created_at, updated_at 
-        FROM color_sort WHERE id = ?`, id).Scan(
-		&colorSort.ID,
-		&colorSort.PeelID,
-		&colorSort.StockID,
-		&colorSort.WeightTypeID,
-		&colorSort.AcceptedWeight,
-		&colorSort.SortCounter,
-		&colorSort.CreatedAt,
-		&colorSort.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	colorSort, err := colorSorts.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, colorSort)
+	respondWithRecord(c, colorSort)
 }
 
 // CreateColorSort - Create new color sort record
-func CreateColorSort(c *gin.Context, db *sql.DB) {
+func CreateColorSort(c *gin.Context, stores *store.Stores) {
 	var colorSort models.ColorSort
 	if err := c.ShouldBindJSON(&colorSort); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	// Insert the record
-	_, err := db.Exec(`
-        INSERT INTO color_sort (
-            id, peel_id, stock_id, weight_type_id, accepted_weight, 
-            sort_counter, created_at, updated_at
-        )
-        VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`,
-		colorSort.ID,
-		colorSort.PeelID,
-		colorSort.StockID,
-		colorSort.WeightTypeID,
-		colorSort.AcceptedWeight,
-		colorSort.SortCounter,
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := prepareColorSort(c, stores, &colorSort); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	// Fetch the created record to get timestamps
-	err = db.QueryRow(`
-        SELECT id, peel_id, stock_id, weight_type_id, accepted_weight, 
-               sort_counter, created_at, updated_at 
-        FROM color_sort WHERE id = ?`, colorSort.ID).Scan(
-		&colorSort.ID,
-		&colorSort.PeelID,
-		&colorSort.StockID,
-		&colorSort.WeightTypeID,
-		&colorSort.AcceptedWeight,
-		&colorSort.SortCounter,
-		&colorSort.CreatedAt,
-		&colorSort.UpdatedAt,
-	)
-
+	err := createPlausibly(c, stores, models.EntityColorSort, *colorSort.StockID, func(tx *store.Stores) (string, error) {
+		return colorSort.ID, tx.ColorSorts.Create(c.Request.Context(), &colorSort)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, colorSort)
 }
 
-// UpdateColorSort - Update existing color sort record
-func UpdateColorSort(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var colorSort models.ColorSort
-	if err := c.ShouldBindJSON(&colorSort); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+// CreateColorSorts - Create a batch of color sort records, all or nothing
+// unless ?mode=best_effort
+func CreateColorSorts(c *gin.Context, stores *store.Stores) {
+	createBatch(c, func(colorSort *models.ColorSort) error {
+		return prepareColorSort(c, stores, colorSort)
+	}, createManyPlausibly(c, stores, models.EntityColorSort, func(tx *store.Stores, ctx context.Context, records []*models.ColorSort, atomic bool) ([]error, error) {
+		return tx.ColorSorts.CreateMany(ctx, records, atomic)
+	}, func(colorSort *models.ColorSort) (string, string) {
+		return *colorSort.StockID, colorSort.ID
+	}))
+}
 
-	result, err := db.Exec(`
-        UPDATE color_sort
-        SET peel_id = ?,
-            stock_id = ?,
-            weight_type_id = ?,
-            accepted_weight = ?,
-            sort_counter = ?,
-            updated_at = NOW()
-        WHERE id = ?`,
-		colorSort.PeelID,
-		colorSort.StockID,
-		colorSort.WeightTypeID,
-		colorSort.AcceptedWeight,
-		colorSort.SortCounter,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// prepareColorSort checks a color sort record about to be created and
+// fills in the fields the server sets
+func prepareColorSort(c *gin.Context, stores *store.Stores, colorSort *models.ColorSort) error {
+	if err := checkColorSort(c, stores, colorSort); err != nil {
+		return err
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	fields := stageIDFields(c, *colorSort.StockID)
+	fields.Counter = colorSort.SortCounter
+	if err := assignID(c, stores.Sequences, models.EntityColorSort, fields, &colorSort.ID); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
+	colorSort.DeviceID = recordingDevice(c)
+	return nil
 }
 
-// DeleteColorSort - Delete color sort record
-func DeleteColorSort(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM color_sort WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// checkColorSort applies the rules a color sort record must meet to be
+// stored, as a new record or as the replacement of a corrected one
+func checkColorSort(c *gin.Context, stores *store.Stores, colorSort *models.ColorSort) error {
+	ctx := c.Request.Context()
+	var problems models.Violations
+	problems.Include(colorSort.Validate())
+	if colorSort.PeelID != nil {
+		machine, found, err := checkExists(ctx, &problems, "peel_id", *colorSort.PeelID, stores.PeelingMachines.Get, "Peeling machine record")
+		if err != nil {
+			return err
+		}
+		// Records posted without a stock belong to the peeled batch's stock
+		if found && colorSort.StockID == nil {
+			colorSort.StockID = machine.StockID
+		}
+		if found && colorSort.StockID != nil && machine.StockID != nil {
+			checkSameStock(&problems, "peel_id", *colorSort.StockID, *machine.StockID, "Peeling machine record", machine.ID)
+		}
+		if found {
+			err := checkCurrent(ctx, &problems, "peel_id", machine.ID, machine.EntryType, stores.PeelingMachines.List, func(m models.PeelingMachine) (models.EntryType, string) {
+				return m.EntryType, m.ID
+			}, "Peeling machine record")
+			if err != nil {
+				return err
+			}
+		}
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if err := checkWeightType(ctx, stores.WeightTypes, &problems, colorSort.WeightTypeID); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := problems.Err(); err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	if err := checkStockID(colorSort.StockID); err != nil {
+		return err
+	}
+	return checkStockStatus(ctx, stores.Stocks, *colorSort.StockID, models.StatusColorSorting)
 }
 
-// GetColorSortsByStock - Get color sort records for a specific stock ID with optional counter filter
-func GetColorSortsByStock(c *gin.Context, db *sql.DB) {
-	stockID := c.Param("stockId")
-	counter := c.Query("counter") // Optional query parameter
+// CorrectColorSort - Reverse a color sort record, posting its replacement
+// unless the record is only being voided
+func CorrectColorSort(c *gin.Context, stores *store.Stores) {
+	correctRecord(c, c.Param("id"), stores.ColorSorts.Get, func(colorSort *models.ColorSort) error {
+		return checkColorSort(c, stores, colorSort)
+	}, correctPlausibly(c, stores, models.EntityColorSort, func(tx *store.Stores, ctx context.Context, reversal, replacement *models.ColorSort) error {
+		return tx.ColorSorts.Correct(ctx, reversal, replacement)
+	}, func(colorSort *models.ColorSort) (string, string) {
+		return *colorSort.StockID, colorSort.ID
+	}))
+}
 
-	var query string
-	var args []interface{}
+// DeleteColorSort - Move a color sort record entered by mistake to the trash
+func DeleteColorSort(c *gin.Context, colorSorts store.ColorSortStore) {
+	id := c.Param("id")
+	deleteEntry(c, id, colorSorts.Get, colorSorts.Delete)
+}
 
-	if counter != "" {
-		query = `
-            SELECT id, peel_id, stock_id, weight_type_id, accepted_weight, 
-                   sort_counter, created_at, updated_at 
-            FROM color_sort 
-            WHERE stock_id = ? AND sort_counter = ?
-            ORDER BY created_at DESC`
-		args = []interface{}{stockID, counter}
-	} else {
-		query = `
-            SELECT id, peel_id, stock_id, weight_type_id, accepted_weight, 
-                   sort_counter, created_at, updated_at 
-            FROM color_sort 
-            WHERE stock_id = ?
-            ORDER BY created_at DESC`
-		args = []interface{}{stockID}
-	}
+// GetColorSortsByStock - Get color sort records for a specific stock ID with optional counter filter
+func GetColorSortsByStock(c *gin.Context, colorSorts store.ColorSortStore) {
+	stockID := c.Param("stockId")
 
-	rows, err := db.Query(query, args...)
+	opts, err := parseListOptions(c, store.ColorSortList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: stockID})
 
-	var colorSorts []models.ColorSort
-	for rows.Next() {
-		var colorSort models.ColorSort
-		if err := rows.Scan(
-			&colorSort.ID,
-			&colorSort.PeelID,
-			&colorSort.StockID,
-			&colorSort.WeightTypeID,
-			&colorSort.AcceptedWeight,
-			&colorSort.SortCounter,
-			&colorSort.CreatedAt,
-			&colorSort.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if value := c.Query("counter"); value != "" { // Optional query parameter
+		counter, err := strconv.Atoi(value)
+		if err != nil {
+			respondWithStatus(c, http.StatusBadRequest, "Invalid counter")
 			return
 		}
-		colorSorts = append(colorSorts, colorSort)
+		opts.Filters = append(opts.Filters, store.Filter{Field: "sort_counter", Value: float64(counter)})
 	}
 
-	if len(colorSorts) == 0 {
-		c.JSON(http.StatusOK, []models.ColorSort{}) // Return empty array instead of null
+	page, err := colorSorts.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, colorSorts)
+	respondWithPage(c, store.ColorSortList, page)
 }
 
 // GetAcceptedWeightSummary - Get summary of accepted weights for a stock ID and counter
-func GetAcceptedWeightSummary(c *gin.Context, db *sql.DB) {
+func GetAcceptedWeightSummary(c *gin.Context, colorSorts store.ColorSortStore) {
 	stockID := c.Param("stockId")
-	counter := c.Param("counter")
-
-	var summary struct {
-		StockID       string  `json:"stock_id"`
-		SortCounter   int     `json:"sort_counter"`
-		TotalAccepted float64 `json:"total_accepted_weight"`
-		RecordCount   int     `json:"record_count"`
-	}
-
-	err := db.QueryRow(`
-        SELECT 
-            stock_id,
-            sort_counter,
-            COALESCE(SUM(accepted_weight), 0) as total_accepted_weight,
-            COUNT(*) as record_count
-        FROM color_sort 
-        WHERE stock_id = ? AND sort_counter = ?
-        GROUP BY stock_id, sort_counter`,
-		stockID, counter,
-	).Scan(
-		&summary.StockID,
-		&summary.SortCounter,
-		&summary.TotalAccepted,
-		&summary.RecordCount,
-	)
-
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusOK, gin.H{
-			"stock_id":              stockID,
-			"sort_counter":          counter,
-			"total_accepted_weight": 0,
-			"record_count":          0,
-		})
+	counter, err := strconv.Atoi(c.Param("counter"))
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid counter")
 		return
 	}
 
+	summary, err := colorSorts.AcceptedWeightSummary(c.Request.Context(), stockID, counter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -295,58 +200,44 @@ func GetAcceptedWeightSummary(c *gin.Context, db *sql.DB) {
 }
 
 // GetColorSortsByStockAndCounter - Get color sort records for a specific stock ID and sort counter
-func GetColorSortsByStockAndCounter(c *gin.Context, db *sql.DB) {
+func GetColorSortsByStockAndCounter(c *gin.Context, colorSorts store.ColorSortStore) {
 	stockID := c.Param("stockId")
-	counter := c.Param("counter")
-
-	rows, err := db.Query(`
-        SELECT id, peel_id, stock_id, weight_type_id, accepted_weight, 
-               sort_counter, created_at, updated_at 
-        FROM color_sort 
-        WHERE stock_id = ? AND sort_counter = ?
-        ORDER BY created_at DESC`,
-		stockID, counter)
+	counter, err := strconv.Atoi(c.Param("counter"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithStatus(c, http.StatusBadRequest, "Invalid counter")
 		return
 	}
-	defer rows.Close()
 
-	var colorSorts []models.ColorSort
-	for rows.Next() {
-		var colorSort models.ColorSort
-		if err := rows.Scan(
-			&colorSort.ID,
-			&colorSort.PeelID,
-			&colorSort.StockID,
-			&colorSort.WeightTypeID,
-			&colorSort.AcceptedWeight,
-			&colorSort.SortCounter,
-			&colorSort.CreatedAt,
-			&colorSort.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		colorSorts = append(colorSorts, colorSort)
+	opts, err := parseListOptions(c, store.ColorSortList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
 	}
+	opts.Filters = append(opts.Filters,
+		store.Filter{Field: "stock_id", Value: stockID},
+		store.Filter{Field: "sort_counter", Value: float64(counter)},
+	)
 
-	if len(colorSorts) == 0 {
-		c.JSON(http.StatusOK, []models.ColorSort{}) // Return empty array instead of null
+	page, err := colorSorts.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, colorSorts)
+	respondWithPage(c, store.ColorSortList, page)
 }
 
 // SetupColorSortRoutes - Setup all routes for color sort
-func SetupColorSortRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/color-sorts", func(c *gin.Context) { GetAllColorSorts(c, db) })
-	router.GET("/color-sorts/:id", func(c *gin.Context) { GetColorSort(c, db) })
-	router.POST("/color-sorts", func(c *gin.Context) { CreateColorSort(c, db) })
-	router.PUT("/color-sorts/:id", func(c *gin.Context) { UpdateColorSort(c, db) })
-	router.DELETE("/color-sorts/:id", func(c *gin.Context) { DeleteColorSort(c, db) })
-	router.GET("/color-sorts/stock/:stockId", func(c *gin.Context) { GetColorSortsByStock(c, db) })
-	router.GET("/color-sorts/stock/:stockId/counter/:counter", func(c *gin.Context) { GetColorSortsByStockAndCounter(c, db) })
-	router.GET("/color-sorts/stock/:stockId/counter/:counter/summary", func(c *gin.Context) { GetAcceptedWeightSummary(c, db) })
+func SetupColorSortRoutes(router *gin.Engine, stores *store.Stores) {
+	colorSorts := stores.ColorSorts
+	router.GET("/color-sorts", func(c *gin.Context) { GetAllColorSorts(c, colorSorts) })
+	router.GET("/color-sorts/:id", func(c *gin.Context) { GetColorSort(c, colorSorts) })
+	router.POST("/color-sorts", func(c *gin.Context) { CreateColorSort(c, stores) })
+	router.POST("/color-sorts/bulk", func(c *gin.Context) { CreateColorSorts(c, stores) })
+	router.POST("/color-sorts/:id/corrections", func(c *gin.Context) { CorrectColorSort(c, stores) })
+	router.PATCH("/color-sorts/:id", func(c *gin.Context) { CorrectColorSort(c, stores) })
+	router.DELETE("/color-sorts/:id", func(c *gin.Context) { DeleteColorSort(c, colorSorts) })
+	router.GET("/color-sorts/stock/:stockId", func(c *gin.Context) { GetColorSortsByStock(c, colorSorts) })
+	router.GET("/color-sorts/stock/:stockId/counter/:counter", func(c *gin.Context) { GetColorSortsByStockAndCounter(c, colorSorts) })
+	router.GET("/color-sorts/stock/:stockId/counter/:counter/summary", func(c *gin.Context) { GetAcceptedWeightSummary(c, colorSorts) })
 }
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CorrectionRequest is the body posted to correct a stage record
+type CorrectionRequest[T any] struct {
+	// Reason says why the record is wrong. It is required and kept on every
+	// entry the correction adds
+	Reason string `json:"reason"`
+	// Replacement is posted like a new record of the stage and takes the
+	// corrected record's place on its lot. Leaving it out voids the record
+	Replacement *T `json:"replacement"`
+}
+
+// CorrectionResponse lists the entries a correction added
+type CorrectionResponse[T any] struct {
+	Reversal    T  `json:"reversal"`
+	Replacement *T `json:"replacement,omitempty"`
+}
+
+// ledgerEntry is implemented by pointers to the stage records that are
+// corrected with reversal and replacement entries rather than edited
+type ledgerEntry[T any] interface {
+	*T
+	Reversal(reason string) T
+	Replaces(original T, reason string)
+}
+
+// correctRecord reverses the stage record id and stores the replacement
+// posted with it, if any. The replacement must pass check, the rules a new
+// record of the stage is held to. With PATCH the body is instead a JSON merge patch
+// carrying the reason: the replacement is the record with the patch
+// applied, so only the fields that were wrong need sending
+func correctRecord[K any, T any, E ledgerEntry[T]](c *gin.Context, id K, get func(context.Context, K) (T, error), check func(*T) error, correct func(context.Context, *T, *T) error) {
+	var request CorrectionRequest[T]
+	var patch map[string]any
+	if c.Request.Method == http.MethodPatch {
+		var ok bool
+		if patch, ok = readMergePatch[T](c); !ok {
+			return
+		}
+		request.Reason, _ = patch["reason"].(string)
+	}
+	if patch == nil {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondWithError(c, invalid(err))
+			return
+		}
+	}
+	reason := strings.TrimSpace(request.Reason)
+	if reason == "" {
+		respondWithStatus(c, http.StatusBadRequest, "reason is required")
+		return
+	}
+	if len(reason) > models.MaxCorrectionReason {
+		respondWithStatus(c, http.StatusBadRequest, "reason must be at most %d characters", models.MaxCorrectionReason)
+		return
+	}
+
+	original, err := get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	// A record can only be corrected once, so If-Match is optional here
+	if c.GetHeader("If-Match") != "" && !requireMatch(c, original) {
+		return
+	}
+
+	if patch != nil {
+		replacement, err := applyMergePatch(original, patch)
+		if err != nil {
+			respondWithError(c, invalid(err))
+			return
+		}
+		request.Replacement = &replacement
+	}
+	reversal := E(&original).Reversal(reason)
+	if request.Replacement != nil {
+		E(request.Replacement).Replaces(original, reason)
+		if err := check(request.Replacement); err != nil {
+			respondWithError(c, err)
+			return
+		}
+	}
+
+	err = correct(c.Request.Context(), &reversal, request.Replacement)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
+		return
+	}
+	if errors.Is(err, store.ErrConflict) {
+		respondWithStatus(c, http.StatusConflict, "Record %v is a reversal or has already been corrected", id)
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CorrectionResponse[T]{Reversal: reversal, Replacement: request.Replacement})
+}
+
+// deleteEntry moves the stage record id to the trash with remove. Only an
+// original that nothing corrects can go; once a record has corrections they
+// stay on the ledger with it, and it is voided instead. Stage records are
+// never edited, so as with corrections If-Match is optional
+func deleteEntry[K any, T any](c *gin.Context, id K, get func(context.Context, K) (T, error), remove func(context.Context, K) error) {
+	if c.GetHeader("If-Match") != "" {
+		current, err := get(c.Request.Context(), id)
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithStatus(c, http.StatusNotFound, "Record not found")
+			return
+		}
+		if err != nil {
+			respondWithError(c, err)
+			return
+		}
+		if !requireMatch(c, current) {
+			return
+		}
+	}
+
+	err := remove(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
+		return
+	}
+	if errors.Is(err, store.ErrConflict) {
+		respondWithStatus(c, http.StatusConflict, "Record %v is a correction or has been corrected; void it with a correction instead", id)
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
+}
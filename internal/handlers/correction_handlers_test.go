@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+func TestCorrectRecordReplacesOriginal(t *testing.T) {
+	router, stores := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+
+	w := send(router, http.MethodPost, "/humidifiers/H1/corrections", `{"reason":"misread scale","replacement":{"id":"ignored","stock_id":"L9","weight":450}}`)
+	expect(t, w, http.StatusCreated)
+	got := decode[CorrectionResponse[models.Humidifier]](t, w)
+	if got.Reversal.EntryType != models.EntryReversal || got.Reversal.Weight != -500 {
+		t.Errorf("reversal = %+v, want a reversal of 500", got.Reversal)
+	}
+	// The replacement is named after the original and stays on its lot,
+	// whatever was sent
+	if got.Replacement == nil || got.Replacement.ID != "H1-C" || got.Replacement.StockID != "L1" || got.Replacement.Weight != 450 {
+		t.Fatalf("replacement = %+v, want H1-C on L1 weighing 450", got.Replacement)
+	}
+
+	totals, err := stores.Reports.StageTotals(context.Background(), "L1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.Humidifier.Weight != 450 {
+		t.Errorf("humidifier total = %v, want 450", totals.Humidifier.Weight)
+	}
+}
+
+func TestCorrectRecordVoids(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+
+	w := send(router, http.MethodPost, "/humidifiers/H1/corrections", `{"reason":"wrong lot"}`)
+	expect(t, w, http.StatusCreated)
+	if got := decode[CorrectionResponse[models.Humidifier]](t, w); got.Replacement != nil {
+		t.Errorf("replacement = %+v, want none", got.Replacement)
+	}
+}
+
+func TestCorrectRecordRefuses(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"no reason", "/humidifiers/H1/corrections", `{"replacement":{"stock_id":"L1","weight":450}}`, http.StatusBadRequest},
+		{"unknown record", "/humidifiers/H9/corrections", `{"reason":"typo"}`, http.StatusNotFound},
+		{"invalid replacement", "/humidifiers/H1/corrections", `{"reason":"typo","replacement":{"stock_id":"L1","weight":-5}}`, http.StatusUnprocessableEntity},
+		{"weightless replacement", "/humidifiers/H1/corrections", `{"reason":"typo","replacement":{"stock_id":"L1"}}`, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, send(router, http.MethodPost, tt.path, tt.body), tt.status)
+		})
+	}
+
+	// None of the refusals above touched H1, so it can still be corrected,
+	// but only once
+	expect(t, send(router, http.MethodPost, "/humidifiers/H1/corrections", `{"reason":"typo","replacement":{"stock_id":"L1","weight":450}}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/humidifiers/H1/corrections", `{"reason":"again"}`), http.StatusConflict)
+	expect(t, send(router, http.MethodPost, "/humidifiers/H1-R/corrections", `{"reason":"undo"}`), http.StatusConflict)
+	expect(t, send(router, http.MethodPost, "/humidifiers/H1-C/corrections", `{"reason":"typo","replacement":{"stock_id":"L1","weight":440}}`), http.StatusCreated)
+}
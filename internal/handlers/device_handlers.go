@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"healing_photons/internal/auth"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	deviceKey       = "auth_device"
+	deviceKeyHeader = "X-API-Key"
+
+	// maxKeyGracePeriod caps how long a rotated out key keeps working
+	maxKeyGracePeriod = 7 * 24 * time.Hour
+)
+
+// DeviceRoutes maps the routes a device can call to the stage each one
+// records. A device may only call those for the stages it was given
+var DeviceRoutes = map[string]models.StockStatus{
+	"POST /humidifiers":      models.StatusHumidifying,
+	"POST /peeling-machines": models.StatusPeeling,
+	"POST /color-sorts":      models.StatusColorSorting,
+	"POST /machine-gradings": models.StatusMachineGrading,
+
+	"POST /humidifiers/bulk":      models.StatusHumidifying,
+	"POST /peeling-machines/bulk": models.StatusPeeling,
+	"POST /color-sorts/bulk":      models.StatusColorSorting,
+	"POST /machine-gradings/bulk": models.StatusMachineGrading,
+}
+
+// DeviceKeyResponse is returned when a device is registered or its key
+// rotated. The key is only ever shown in this response
+type DeviceKeyResponse struct {
+	Device models.Device `json:"device"`
+	APIKey string        `json:"api_key"`
+}
+
+// RotateKeyRequest is the optional body accepted by RotateDeviceKey
+type RotateKeyRequest struct {
+	// GracePeriod is how long the old key keeps working, e.g. "30m". The
+	// old key stops at once when it is left out
+	GracePeriod string `json:"grace_period"`
+}
+
+// GetAllDevices - Get all registered devices
+func GetAllDevices(c *gin.Context, devices store.DeviceStore) {
+	opts, err := parseListOptions(c, store.DeviceList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	page, err := devices.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithPage(c, store.DeviceList, page)
+}
+
+// GetDevice - Get single device
+func GetDevice(c *gin.Context, devices store.DeviceStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	device, err := devices.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Device not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithRecord(c, device)
+}
+
+// CreateDevice - Register a device and issue its first API key
+func CreateDevice(c *gin.Context, devices store.DeviceStore) {
+	var device models.Device
+	if err := c.ShouldBindJSON(&device); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if err := normalizeDevice(&device); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !requireUniqueDevice(c, devices, 0, device) {
+		return
+	}
+
+	key, err := auth.NewDeviceKey()
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	device.KeyHash = auth.HashDeviceKey(key)
+	device.KeyPrefix = key[:auth.DeviceKeyPrefixLength]
+
+	if err := devices.Create(c.Request.Context(), &device); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, DeviceKeyResponse{Device: device, APIKey: key})
+}
+
+// UpdateDevice - Rename a device or change the stages it may record
+func UpdateDevice(c *gin.Context, devices store.DeviceStore, units store.UnitOfWork) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+	var device models.Device
+	if !bindUpdate(c, id, devices.Get, "Device not found", &device) {
+		return
+	}
+	if err := normalizeDevice(&device); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !requireUniqueDevice(c, devices, id, device) {
+		return
+	}
+
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Devices.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.Devices.Update(c.Request.Context(), id, device)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Device not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device updated successfully"})
+}
+
+// RotateDeviceKey - Issue a new API key for a device, optionally keeping
+// the old one working for a grace period while the device is reconfigured
+func RotateDeviceKey(c *gin.Context, devices store.DeviceStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+	var request RotateKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	var grace time.Duration
+	if request.GracePeriod != "" {
+		grace, err = time.ParseDuration(request.GracePeriod)
+		if err != nil || grace < 0 || grace > maxKeyGracePeriod {
+			respondWithStatus(c, http.StatusBadRequest, "grace_period must be a duration such as 30m, at most %gh", maxKeyGracePeriod.Hours())
+			return
+		}
+	}
+
+	key, err := auth.NewDeviceKey()
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	err = devices.RotateKey(c.Request.Context(), id, auth.HashDeviceKey(key), key[:auth.DeviceKeyPrefixLength], grace)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Device not found")
+		return
+	}
+	if errors.Is(err, store.ErrConflict) {
+		respondWithStatus(c, http.StatusConflict, "Device has been revoked; register it again instead")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	device, err := devices.Get(c.Request.Context(), id)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, DeviceKeyResponse{Device: device, APIKey: key})
+}
+
+// RevokeDevice - Disable a device and every key it was issued. The
+// records it posted keep their device_id
+func RevokeDevice(c *gin.Context, devices store.DeviceStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	err = devices.Revoke(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Device not found")
+		return
+	}
+	if errors.Is(err, store.ErrConflict) {
+		respondWithStatus(c, http.StatusConflict, "Device was already revoked")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Device revoked successfully"})
+}
+
+// normalizeDevice trims the name and checks the stages, dropping repeats
+func normalizeDevice(device *models.Device) error {
+	device.Name = strings.TrimSpace(device.Name)
+	if device.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	stages := []models.StockStatus{}
+	for _, stage := range device.Stages {
+		if !models.ValidDeviceStage(stage) {
+			return fmt.Errorf("devices cannot record stage %q; use one of %v", stage, models.DeviceStages)
+		}
+		if !slices.Contains(stages, stage) {
+			stages = append(stages, stage)
+		}
+	}
+	if len(stages) == 0 {
+		return fmt.Errorf("stages must name at least one stage")
+	}
+	device.Stages = stages
+	return nil
+}
+
+// requireUniqueDevice writes a 409 response when another device already
+// has the name
+func requireUniqueDevice(c *gin.Context, devices store.DeviceStore, id int64, device models.Device) bool {
+	named, err := devices.List(c.Request.Context(), store.All(store.Filter{Field: "name", Value: device.Name}))
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	for _, other := range named.Items {
+		if other.ID != id && other.Name == device.Name {
+			respondWithError(c, rejectField(http.StatusConflict, codeDuplicate, "name", "Device %q already exists with ID %d", other.Name, other.ID))
+			return false
+		}
+	}
+	return true
+}
+
+// AuthenticateDevice signs in requests carrying a device API key. Requests
+// without one are left for RequireAuth
+func AuthenticateDevice(devices store.DeviceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(deviceKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		device, err := devices.GetByKeyHash(c.Request.Context(), auth.HashDeviceKey(key))
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			abortWithError(c, err)
+			return
+		}
+		if err != nil || device.RevokedAt != nil {
+			abortWithError(c, reject(http.StatusUnauthorized, "Invalid or revoked API key"))
+			return
+		}
+
+		c.Set(deviceKey, device)
+		actor := models.Actor{Type: models.ActorDevice, ID: &device.ID, Name: device.Name}
+		c.Request = c.Request.WithContext(store.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// currentDevice returns the device set by AuthenticateDevice
+func currentDevice(c *gin.Context) (models.Device, bool) {
+	if device, ok := c.Get(deviceKey); ok {
+		return device.(models.Device), true
+	}
+	return models.Device{}, false
+}
+
+// recordingDevice returns the ID to store as a new record's device_id, or
+// nil when a user is posting it
+func recordingDevice(c *gin.Context) *int64 {
+	if device, ok := currentDevice(c); ok {
+		return &device.ID
+	}
+	return nil
+}
+
+// SetupDeviceRoutes - Setup all routes for devices
+func SetupDeviceRoutes(router *gin.Engine, stores *store.Stores) {
+	devices := stores.Devices
+	units := stores.UnitOfWork
+	router.GET("/devices", func(c *gin.Context) { GetAllDevices(c, devices) })
+	router.GET("/devices/:id", func(c *gin.Context) { GetDevice(c, devices) })
+	router.POST("/devices", func(c *gin.Context) { CreateDevice(c, devices) })
+	router.PUT("/devices/:id", func(c *gin.Context) { UpdateDevice(c, devices, units) })
+	router.PATCH("/devices/:id", func(c *gin.Context) { UpdateDevice(c, devices, units) })
+	router.POST("/devices/:id/rotate-key", func(c *gin.Context) { RotateDeviceKey(c, devices) })
+	router.POST("/devices/:id/revoke", func(c *gin.Context) { RevokeDevice(c, devices) })
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/store/memory"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newDeviceRouter serves the device routes behind AuthenticateDevice, and
+// GET /whoami answering with the ID of the device a request signed in as
+func newDeviceRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	stores := memory.NewStores()
+	router := gin.New()
+	router.Use(AuthenticateDevice(stores.Devices))
+	SetupDeviceRoutes(router, stores)
+	router.GET("/whoami", func(c *gin.Context) {
+		device, _ := currentDevice(c)
+		c.JSON(http.StatusOK, gin.H{"id": device.ID})
+	})
+	return router
+}
+
+// registerDevice creates a device and returns its API key
+func registerDevice(t *testing.T, router http.Handler, name string) string {
+	t.Helper()
+	w := send(router, http.MethodPost, "/devices", `{"name":"`+name+`","stages":["humidifying"]}`)
+	expect(t, w, http.StatusCreated)
+	return decode[DeviceKeyResponse](t, w).APIKey
+}
+
+// rotateKey rotates the key of device 1 with body and returns the new key
+func rotateKey(t *testing.T, router http.Handler, body string) string {
+	t.Helper()
+	w := send(router, http.MethodPost, "/devices/1/rotate-key", body)
+	expect(t, w, http.StatusOK)
+	return decode[DeviceKeyResponse](t, w).APIKey
+}
+
+func TestListDevicesPages(t *testing.T) {
+	router := newDeviceRouter()
+	registerDevice(t, router, "Scale C")
+	registerDevice(t, router, "Scale A")
+	registerDevice(t, router, "Scale B")
+	expect(t, send(router, http.MethodPost, "/devices", `{"name":"Scale A","stages":["peeling"]}`), http.StatusConflict)
+
+	w := send(router, http.MethodGet, "/devices?limit=2", "")
+	expect(t, w, http.StatusOK)
+	first := decode[struct {
+		Data       []models.Device
+		NextCursor *string `json:"next_cursor"`
+		Total      int
+	}](t, w)
+	if first.Total != 3 || len(first.Data) != 2 || first.Data[0].Name != "Scale A" || first.NextCursor == nil {
+		t.Fatalf("first page = %+v, want Scale A and B of 3 with a cursor", first)
+	}
+
+	w = send(router, http.MethodGet, "/devices?limit=2&cursor="+*first.NextCursor, "")
+	expect(t, w, http.StatusOK)
+	if rest := decode[struct{ Data []models.Device }](t, w).Data; len(rest) != 1 || rest[0].Name != "Scale C" {
+		t.Errorf("second page = %+v, want Scale C", rest)
+	}
+}
+
+func TestRotatedKeyGracePeriod(t *testing.T) {
+	router := newDeviceRouter()
+	original := registerDevice(t, router, "Scale A")
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, original), http.StatusOK)
+
+	// The old key keeps working through its grace period
+	graced := rotateKey(t, router, `{"grace_period":"1h"}`)
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, original), http.StatusOK)
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, graced), http.StatusOK)
+
+	// Without one it stops at once, and so does the key it replaced
+	current := rotateKey(t, router, "")
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, graced), http.StatusUnauthorized)
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, original), http.StatusUnauthorized)
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, current), http.StatusOK)
+
+	expect(t, send(router, http.MethodPost, "/devices/1/rotate-key", `{"grace_period":"30d"}`), http.StatusBadRequest)
+
+	expect(t, send(router, http.MethodPost, "/devices/1/revoke", ""), http.StatusOK)
+	expect(t, send(router, http.MethodGet, "/whoami", "", deviceKeyHeader, current), http.StatusUnauthorized)
+	expect(t, send(router, http.MethodPost, "/devices/1/rotate-key", ""), http.StatusConflict)
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Error codes tell clients what went wrong without parsing the message.
+// Most follow from the status; the rest single out a cause worth showing
+// a worker its own message for
+const (
+	codeInvalidRequest       = "invalid_request"
+	codeUnauthenticated      = "unauthenticated"
+	codeForbidden            = "forbidden"
+	codeNotFound             = "not_found"
+	codeConflict             = "conflict"
+	codePreconditionFailed   = "precondition_failed"
+	codeUnprocessable        = "unprocessable"
+	codePreconditionRequired = "precondition_required"
+	codeInternal             = "internal_error"
+
+	// codeInvalidField is a field that failed its binding rules
+	codeInvalidField = "invalid_field"
+	// codeValidationFailed is a record breaking the rules of its model
+	codeValidationFailed = "validation_failed"
+	// codeDuplicate is a record whose key is already taken
+	codeDuplicate = "duplicate"
+	// codeMissingReference is a field naming a record that does not exist
+	codeMissingReference = "missing_reference"
+	// codeValueTooLong is a value longer than its column allows
+	codeValueTooLong = "value_too_long"
+	// codeWrongStage is a stage record for a lot in another stage
+	codeWrongStage = "wrong_stage"
+	// codeKeyReused is an Idempotency-Key sent again with another request
+	codeKeyReused = "idempotency_key_reused"
+	// codeInProgress is a retry of a request that is still running
+	codeInProgress = "request_in_progress"
+	// codeReferenced is a record other live records still refer to
+	codeReferenced = "referenced"
+)
+
+// statusCodes are the codes errors are given when nothing more specific
+// applies
+var statusCodes = map[int]string{
+	http.StatusBadRequest:           codeInvalidRequest,
+	http.StatusUnauthorized:         codeUnauthenticated,
+	http.StatusForbidden:            codeForbidden,
+	http.StatusNotFound:             codeNotFound,
+	http.StatusConflict:             codeConflict,
+	http.StatusPreconditionFailed:   codePreconditionFailed,
+	http.StatusUnprocessableEntity:  codeUnprocessable,
+	http.StatusPreconditionRequired: codePreconditionRequired,
+	http.StatusInternalServerError:  codeInternal,
+}
+
+// errorBody describes why a request failed
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Field is the request field at fault, when there is one
+	Field   string `json:"field,omitempty"`
+	Details any    `json:"details,omitempty"`
+}
+
+// errorResponse is the body of every error answer
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+// rejection is a reason a request can't be carried out, along with the
+// status it is answered with
+type rejection struct {
+	status int
+	body   errorBody
+}
+
+func (r *rejection) Error() string {
+	return r.body.Message
+}
+
+// reject returns a rejection answered with status and the code it implies
+func reject(status int, format string, args ...any) error {
+	return &rejection{status: status, body: errorBody{Code: statusCodes[status], Message: fmt.Sprintf(format, args...)}}
+}
+
+// rejectField returns a rejection with its own code, caused by field
+func rejectField(status int, code, field, format string, args ...any) error {
+	return &rejection{status: status, body: errorBody{Code: code, Message: fmt.Sprintf(format, args...), Field: field}}
+}
+
+// invalid returns a 400 rejection for a request body or parameter that
+// couldn't be read, naming the fields at fault where it can
+func invalid(err error) error {
+	var r *rejection
+	if errors.As(err, &r) {
+		return err
+	}
+
+	var fields validator.ValidationErrors
+	if errors.As(err, &fields) {
+		details := make(models.ValidationError, len(fields))
+		for i, field := range fields {
+			details[i] = models.FieldError{Field: fieldPath(field), Rule: field.Tag(), Message: ruleMessage(field)}
+		}
+		return &rejection{status: http.StatusBadRequest, body: fieldErrorsBody(codeInvalidField, details)}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return rejectField(http.StatusBadRequest, codeInvalidField, typeErr.Field, "%s must be %s, not %s", typeErr.Field, jsonType(typeErr.Type), typeErr.Value)
+	}
+	return reject(http.StatusBadRequest, "%s", err)
+}
+
+// fieldErrorsBody describes the fields that broke their rules, leading with
+// the first
+func fieldErrorsBody(code string, fields models.ValidationError) errorBody {
+	message := fields[0].Message
+	if len(fields) > 1 {
+		message = fmt.Sprintf("%s, and %d more", message, len(fields)-1)
+	}
+	return errorBody{Code: code, Message: message, Field: fields[0].Field, Details: fields}
+}
+
+// jsonType names the kind of JSON value that decodes into t
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonType(t.Elem())
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	}
+	return "a number"
+}
+
+// fieldPath returns the JSON path of a field that failed validation,
+// without the name of the struct it starts from
+func fieldPath(field validator.FieldError) string {
+	_, path, ok := strings.Cut(field.Namespace(), ".")
+	if !ok {
+		return field.Field()
+	}
+	return path
+}
+
+// ruleMessage explains the rules request bodies are bound with
+func ruleMessage(field validator.FieldError) string {
+	name := fieldPath(field)
+	switch field.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", name)
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", name, field.Param())
+	case "gte", "min":
+		return fmt.Sprintf("%s must be at least %s", name, field.Param())
+	case "lt":
+		return fmt.Sprintf("%s must be less than %s", name, field.Param())
+	case "lte", "max":
+		return fmt.Sprintf("%s must be at most %s", name, field.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", name, field.Param())
+	}
+	return fmt.Sprintf("%s failed the %s rule", name, field.Tag())
+}
+
+func init() {
+	// Report fields by the names clients send them under
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+	}
+}
+
+// describe returns the status and body err is answered with. Rejections
+// carry their own; records breaking their model's rules are a 422 listing
+// every field at fault; writes the database refused for breaking a constraint
+// are pinned on the field at fault; anything else is a 500 whose cause is
+// kept from the client
+func describe(err error) (int, errorBody) {
+	var r *rejection
+	if errors.As(err, &r) {
+		return r.status, r.body
+	}
+
+	var fields models.ValidationError
+	if errors.As(err, &fields) && len(fields) > 0 {
+		return http.StatusUnprocessableEntity, fieldErrorsBody(codeValidationFailed, fields)
+	}
+
+	var violation *store.ConstraintError
+	if errors.As(err, &violation) {
+		switch violation.Constraint {
+		case store.ConstraintUnique:
+			return http.StatusConflict, errorBody{
+				Code:    codeDuplicate,
+				Message: fmt.Sprintf("%q is already taken", violation.Value),
+				Details: gin.H{"key": violation.Key, "value": violation.Value},
+			}
+		case store.ConstraintForeignKey:
+			return http.StatusUnprocessableEntity, errorBody{
+				Code:    codeMissingReference,
+				Message: fmt.Sprintf("Unknown %s; it refers to a %s that does not exist", violation.Column, violation.Parent),
+				Field:   violation.Column,
+				Details: gin.H{"parent": violation.Parent},
+			}
+		case store.ConstraintLength:
+			return http.StatusBadRequest, errorBody{
+				Code:    codeValueTooLong,
+				Message: fmt.Sprintf("%s is too long", violation.Column),
+				Field:   violation.Column,
+			}
+		}
+	}
+
+	return http.StatusInternalServerError, errorBody{Code: codeInternal, Message: "Something went wrong on the server; it has been logged"}
+}
+
+// respondWithError writes err, with its own status when it is a rejection
+// or a constraint violation and as a 500 otherwise. The cause of a 500 is
+// logged rather than sent
+func respondWithError(c *gin.Context, err error) {
+	status, body := describe(err)
+	if status == http.StatusInternalServerError {
+		c.Error(err)
+	}
+	c.JSON(status, errorResponse{Error: body})
+}
+
+// respondWithStatus writes an error answered with status and the code it
+// implies
+func respondWithStatus(c *gin.Context, status int, format string, args ...any) {
+	respondWithError(c, reject(status, format, args...))
+}
+
+// abortWithError writes err as respondWithError does and stops the
+// handlers after the current one from running
+func abortWithError(c *gin.Context, err error) {
+	respondWithError(c, err)
+	c.Abort()
+}
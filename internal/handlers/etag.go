@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"healing_photons/internal/store"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etag returns the entity tag of a record: a hash of its JSON form, so it
+// changes whenever anything a client can see of the record does
+func etag(record any) (string, error) {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`, nil
+}
+
+// matchesTag reports whether an If-Match or If-None-Match header lists tag
+func matchesTag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// respondWithRecord writes a single record along with its ETag, or just a
+// 304 when the client's If-None-Match shows it already has this version
+func respondWithRecord(c *gin.Context, record any) {
+	tag, err := etag(record)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	c.Header("ETag", tag)
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" && matchesTag(ifNoneMatch, tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// requireMatch writes a 428 response when the request has no If-Match
+// header and a 412 response when the header doesn't list the ETag of
+// current, the record as it is now
+func requireMatch(c *gin.Context, current any) bool {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		respondWithStatus(c, http.StatusPreconditionRequired, "If-Match header is required; send the ETag the record was read with")
+		return false
+	}
+
+	tag, err := etag(current)
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	if !matchesTag(ifMatch, tag) {
+		c.Header("ETag", tag)
+		respondWithStatus(c, http.StatusPreconditionFailed, "Record has changed since it was read; fetch it again and reapply the change")
+		return false
+	}
+	return true
+}
+
+// writeUnchanged runs write in a unit of work once the record get returns,
+// read again with its row locked, still has an ETag the request's If-Match
+// lists. Checking and writing together means a change committed after the
+// request first read the record is never overwritten; the write fails with
+// 412 instead. A missing record is store.ErrNotFound
+func writeUnchanged(c *gin.Context, units store.UnitOfWork, get func(tx *store.Stores, ctx context.Context) (any, error), write func(tx *store.Stores) error) error {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return reject(http.StatusPreconditionRequired, "If-Match header is required; send the ETag the record was read with")
+	}
+
+	ctx := c.Request.Context()
+	return units.Do(ctx, func(tx *store.Stores) error {
+		current, err := get(tx, store.ForUpdate(ctx))
+		if err != nil {
+			return err
+		}
+		tag, err := etag(current)
+		if err != nil {
+			return err
+		}
+		if !matchesTag(ifMatch, tag) {
+			c.Header("ETag", tag)
+			return reject(http.StatusPreconditionFailed, "Record has changed since it was read; fetch it again and reapply the change")
+		}
+		return write(tx)
+	})
+}
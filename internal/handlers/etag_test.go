@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+const sellerBody = `{"name":"Sree Traders","country":"IN"}`
+
+func TestUpdateRequiresIfMatch(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+
+	w := send(router, http.MethodPut, "/sellers/1", `{"name":"Sree Traders Pvt","country":"IN"}`)
+	expect(t, w, http.StatusPreconditionRequired)
+	if got := decode[errorResponse](t, w); got.Error.Code != codePreconditionRequired {
+		t.Errorf("code = %q, want %q", got.Error.Code, codePreconditionRequired)
+	}
+	expect(t, send(router, http.MethodDelete, "/sellers/1", ""), http.StatusPreconditionRequired)
+	expect(t, send(router, http.MethodPut, "/sellers/9", `{"name":"Nobody","country":"IN"}`, "If-Match", `"stale"`), http.StatusNotFound)
+}
+
+func TestUpdateWithCurrentETag(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	read := send(router, http.MethodGet, "/sellers/1", "")
+	expect(t, read, http.StatusOK)
+
+	expect(t, send(router, http.MethodPut, "/sellers/1", `{"name":"Sree Traders Pvt","country":"IN"}`, "If-Match", read.Header().Get("ETag")), http.StatusOK)
+	seller := decode[models.Seller](t, send(router, http.MethodGet, "/sellers/1", ""))
+	if seller.Name != "Sree Traders Pvt" {
+		t.Errorf("name = %q, want the update", seller.Name)
+	}
+}
+
+func TestStaleWriteIsRefused(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	tag := send(router, http.MethodGet, "/sellers/1", "").Header().Get("ETag")
+
+	// Two clients read the same version; the first to write wins
+	expect(t, send(router, http.MethodPatch, "/sellers/1", `{"phone":"0484 2345678"}`, "If-Match", tag), http.StatusOK)
+	w := send(router, http.MethodPut, "/sellers/1", `{"name":"Other Name","country":"IN"}`, "If-Match", tag)
+	expect(t, w, http.StatusPreconditionFailed)
+
+	current := send(router, http.MethodGet, "/sellers/1", "")
+	if got, want := w.Header().Get("ETag"), current.Header().Get("ETag"); got != want {
+		t.Errorf("412 ETag = %s, want the current %s", got, want)
+	}
+	if seller := decode[models.Seller](t, current); seller.Name != "Sree Traders" || seller.Phone != "0484 2345678" {
+		t.Errorf("seller = %+v, want the first write only", seller)
+	}
+	expect(t, send(router, http.MethodDelete, "/sellers/1", "", "If-Match", tag), http.StatusPreconditionFailed)
+	expect(t, send(router, http.MethodDelete, "/sellers/1", "", "If-Match", current.Header().Get("ETag")), http.StatusOK)
+}
+
+func TestUnchangedUpdateSucceeds(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/pieces", `{"piece_code":"W240"}`), http.StatusCreated)
+
+	// Pieces have no updated_at, so saving one as it is changes no column
+	tag := send(router, http.MethodGet, "/pieces/1", "").Header().Get("ETag")
+	expect(t, send(router, http.MethodPatch, "/pieces/1", `{}`, "If-Match", tag), http.StatusOK)
+	expect(t, send(router, http.MethodPut, "/pieces/1", `{"piece_code":"W240"}`, "If-Match", tag), http.StatusOK)
+}
@@ -1,80 +1,57 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllGraderMachineOutputs - Get all grader machine output records
-func GetAllGraderMachineOutputs(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, type 
-        FROM grader_machine_outputs`)
+func GetAllGraderMachineOutputs(c *gin.Context, outputs store.GraderMachineOutputStore) {
+	opts, err := parseListOptions(c, store.GraderMachineOutputList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var outputs []models.GraderMachineOutputs
-	for rows.Next() {
-		var output models.GraderMachineOutputs
-		if err := rows.Scan(
-			&output.ID,
-			&output.Type,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		outputs = append(outputs, output)
+
+	page, err := outputs.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, outputs)
+	respondWithPage(c, store.GraderMachineOutputList, page)
 }
 
 // GetGraderMachineOutput - Get single grader machine output record
-func GetGraderMachineOutput(c *gin.Context, db *sql.DB) {
+func GetGraderMachineOutput(c *gin.Context, outputs store.GraderMachineOutputStore) {
 	id := c.Param("id")
 
-	var output models.GraderMachineOutputs
-	err := db.QueryRow(`
-        SELECT id, type 
-        FROM grader_machine_outputs WHERE id = ?`, id).Scan(
-		&output.ID,
-		&output.Type,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	output, err := outputs.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, output)
+	respondWithRecord(c, output)
 }
 
 // CreateGraderMachineOutput - Create new grader machine output record
-func CreateGraderMachineOutput(c *gin.Context, db *sql.DB) {
+func CreateGraderMachineOutput(c *gin.Context, outputs store.GraderMachineOutputStore) {
 	var output models.GraderMachineOutputs
 	if err := c.ShouldBindJSON(&output); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	_, err := db.Exec(`
-        INSERT INTO grader_machine_outputs (
-            id, type
-        )
-        VALUES (?, ?)`,
-		output.ID,
-		output.Type,
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := outputs.Create(c.Request.Context(), &output); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -82,33 +59,24 @@ func CreateGraderMachineOutput(c *gin.Context, db *sql.DB) {
 }
 
 // UpdateGraderMachineOutput - Update existing grader machine output record
-func UpdateGraderMachineOutput(c *gin.Context, db *sql.DB) {
+func UpdateGraderMachineOutput(c *gin.Context, outputs store.GraderMachineOutputStore, units store.UnitOfWork) {
 	id := c.Param("id")
 	var output models.GraderMachineOutputs
-	if err := c.ShouldBindJSON(&output); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindUpdate(c, id, outputs.Get, "Record not found", &output) {
 		return
 	}
 
-	result, err := db.Exec(`
-        UPDATE grader_machine_outputs
-        SET type = ?
-        WHERE id = ?`,
-		output.Type,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.GraderMachineOutputs.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.GraderMachineOutputs.Update(c.Request.Context(), id, output)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithError(c, err)
 		return
 	}
 
@@ -116,33 +84,38 @@ func UpdateGraderMachineOutput(c *gin.Context, db *sql.DB) {
 }
 
 // DeleteGraderMachineOutput - Delete grader machine output record
-func DeleteGraderMachineOutput(c *gin.Context, db *sql.DB) {
+func DeleteGraderMachineOutput(c *gin.Context, outputs store.GraderMachineOutputStore, units store.UnitOfWork) {
 	id := c.Param("id")
 
-	result, err := db.Exec("DELETE FROM grader_machine_outputs WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.GraderMachineOutputs.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced("Grader machine output "+id, graderMachineOutputReferrers(ctx, tx, id)...); err != nil {
+			return err
+		}
+		return tx.GraderMachineOutputs.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
 }
 
 // SetupGraderMachineOutputRoutes - Setup all routes for grader machine outputs
-func SetupGraderMachineOutputRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/grader-machine-outputs", func(c *gin.Context) { GetAllGraderMachineOutputs(c, db) })
-	router.GET("/grader-machine-outputs/:id", func(c *gin.Context) { GetGraderMachineOutput(c, db) })
-	router.POST("/grader-machine-outputs", func(c *gin.Context) { CreateGraderMachineOutput(c, db) })
-	router.PUT("/grader-machine-outputs/:id", func(c *gin.Context) { UpdateGraderMachineOutput(c, db) })
-	router.DELETE("/grader-machine-outputs/:id", func(c *gin.Context) { DeleteGraderMachineOutput(c, db) })
+func SetupGraderMachineOutputRoutes(router *gin.Engine, stores *store.Stores) {
+	outputs := stores.GraderMachineOutputs
+	units := stores.UnitOfWork
+	router.GET("/grader-machine-outputs", func(c *gin.Context) { GetAllGraderMachineOutputs(c, outputs) })
+	router.GET("/grader-machine-outputs/:id", func(c *gin.Context) { GetGraderMachineOutput(c, outputs) })
+	router.POST("/grader-machine-outputs", func(c *gin.Context) { CreateGraderMachineOutput(c, outputs) })
+	router.PUT("/grader-machine-outputs/:id", func(c *gin.Context) { UpdateGraderMachineOutput(c, outputs, units) })
+	router.PATCH("/grader-machine-outputs/:id", func(c *gin.Context) { UpdateGraderMachineOutput(c, outputs, units) })
+	router.DELETE("/grader-machine-outputs/:id", func(c *gin.Context) { DeleteGraderMachineOutput(c, outputs, units) })
 }
@@ -1,99 +1,63 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"fmt"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllGradingCategories - Get all grading categories
-func GetAllGradingCategories(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-		SELECT category_id, category_code, description
-		FROM grading_categories
-		ORDER BY category_code`)
+func GetAllGradingCategories(c *gin.Context, categories store.GradingCategoryStore) {
+	opts, err := parseListOptions(c, store.GradingCategoryList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var categories []models.GradingCategory
-	for rows.Next() {
-		var category models.GradingCategory
-		if err := rows.Scan(
-			&category.CategoryID,
-			&category.CategoryCode,
-			&category.Description,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		categories = append(categories, category)
+
+	page, err := categories.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, categories)
+	respondWithPage(c, store.GradingCategoryList, page)
 }
 
 // GetGradingCategory - Get single grading category
-func GetGradingCategory(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
+func GetGradingCategory(c *gin.Context, categories store.GradingCategoryStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
 
-	var category models.GradingCategory
-	err := db.QueryRow(`
-		SELECT category_id, category_code, description
-		FROM grading_categories 
-		WHERE category_id = ?`, id).Scan(
-		&category.CategoryID,
-		&category.CategoryCode,
-		&category.Description,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	category, err := categories.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, category)
+	respondWithRecord(c, category)
 }
 
 // CreateGradingCategory - Create new grading category
-func CreateGradingCategory(c *gin.Context, db *sql.DB) {
+func CreateGradingCategory(c *gin.Context, categories store.GradingCategoryStore) {
 	var category models.GradingCategory
 	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	// Insert the record
-	_, err := db.Exec(`
-		INSERT INTO grading_categories (
-			category_id, category_code, description
-		)
-		VALUES (?, ?, ?)`,
-		category.CategoryID,
-		category.CategoryCode,
-		category.Description,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Fetch the created record to get timestamps
-	err = db.QueryRow(`
-		SELECT category_id, category_code, description
-		FROM grading_categories WHERE category_id = ?`, category.CategoryID).Scan(
-		&category.CategoryID,
-		&category.CategoryCode,
-		&category.Description,
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := categories.Create(c.Request.Context(), &category); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -101,35 +65,28 @@ func CreateGradingCategory(c *gin.Context, db *sql.DB) {
 }
 
 // UpdateGradingCategory - Update existing grading category
-func UpdateGradingCategory(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var category models.GradingCategory
-	if err := c.ShouldBindJSON(&category); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func UpdateGradingCategory(c *gin.Context, categories store.GradingCategoryStore, units store.UnitOfWork) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
-
-	result, err := db.Exec(`
-		UPDATE grading_categories
-		SET category_code = ?,
-			description = ?
-		WHERE category_id = ?`,
-		category.CategoryCode,
-		category.Description,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var category models.GradingCategory
+	if !bindUpdate(c, id, categories.Get, "Record not found", &category) {
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.GradingCategories.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.GradingCategories.Update(c.Request.Context(), id, category)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -137,33 +94,42 @@ func UpdateGradingCategory(c *gin.Context, db *sql.DB) {
 }
 
 // DeleteGradingCategory - Delete grading category
-func DeleteGradingCategory(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM grading_categories WHERE category_id = ?", id)
+func DeleteGradingCategory(c *gin.Context, categories store.GradingCategoryStore, units store.UnitOfWork) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.GradingCategories.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced(fmt.Sprintf("Grading category %d", id), gradingCategoryReferrers(ctx, tx, strconv.FormatInt(id, 10))...); err != nil {
+			return err
+		}
+		return tx.GradingCategories.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
 }
 
 // SetupGradingCategoryRoutes sets up all the routes for grading categories
-func SetupGradingCategoryRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/grading-categories", func(c *gin.Context) { GetAllGradingCategories(c, db) })
-	router.GET("/grading-categories/:id", func(c *gin.Context) { GetGradingCategory(c, db) })
-	router.POST("/grading-categories", func(c *gin.Context) { CreateGradingCategory(c, db) })
-	router.PUT("/grading-categories/:id", func(c *gin.Context) { UpdateGradingCategory(c, db) })
-	router.DELETE("/grading-categories/:id", func(c *gin.Context) { DeleteGradingCategory(c, db) })
-} 
\ No newline at end of file
+func SetupGradingCategoryRoutes(router *gin.Engine, stores *store.Stores) {
+	categories := stores.GradingCategories
+	units := stores.UnitOfWork
+	router.GET("/grading-categories", func(c *gin.Context) { GetAllGradingCategories(c, categories) })
+	router.GET("/grading-categories/:id", func(c *gin.Context) { GetGradingCategory(c, categories) })
+	router.POST("/grading-categories", func(c *gin.Context) { CreateGradingCategory(c, categories) })
+	router.PUT("/grading-categories/:id", func(c *gin.Context) { UpdateGradingCategory(c, categories, units) })
+	router.PATCH("/grading-categories/:id", func(c *gin.Context) { UpdateGradingCategory(c, categories, units) })
+	router.DELETE("/grading-categories/:id", func(c *gin.Context) { DeleteGradingCategory(c, categories, units) })
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sheetWeightTolerance absorbs rounding when a sheet is compared with the
+// weight the worker took in
+const sheetWeightTolerance = 0.0005
+
+// GetAllGradingSheets - Get all grading sheets
+func GetAllGradingSheets(c *gin.Context, sheets store.GradingSheetStore) {
+	opts, err := parseListOptions(c, store.GradingSheetList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	page, err := sheets.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithPage(c, store.GradingSheetList, page)
+}
+
+// GetGradingSheet - Get single grading sheet
+func GetGradingSheet(c *gin.Context, sheets store.GradingSheetStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	sheet, err := sheets.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithRecord(c, sheet)
+}
+
+// CreateGradingSheet - Submit a worker's grade weights for one size category
+func CreateGradingSheet(c *gin.Context, sheets store.GradingSheetStore, inputs store.ManualGradingInputStore, stocks store.StockStore) {
+	var sheet models.GradingSheet
+	if err := c.ShouldBindJSON(&sheet); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if err := sheet.Grades.Validate(); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !requireStockStatus(c, stocks, sheet.StockID, models.StatusManualGrading) ||
+		!requireOneSheetPerSize(c, sheets, 0, sheet) ||
+		!requireSheetWithinInput(c, inputs, sheet) {
+		return
+	}
+
+	if err := sheets.Create(c.Request.Context(), &sheet); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sheet)
+}
+
+// UpdateGradingSheet - Update existing grading sheet
+func UpdateGradingSheet(c *gin.Context, sheets store.GradingSheetStore, units store.UnitOfWork, inputs store.ManualGradingInputStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+	var sheet models.GradingSheet
+	if !bindUpdate(c, id, sheets.Get, "Record not found", &sheet) {
+		return
+	}
+	if err := sheet.Grades.Validate(); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !requireOneSheetPerSize(c, sheets, id, sheet) || !requireSheetWithinInput(c, inputs, sheet) {
+		return
+	}
+
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.GradingSheets.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.GradingSheets.Update(c.Request.Context(), id, sheet)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
+}
+
+// DeleteGradingSheet - Delete grading sheet
+func DeleteGradingSheet(c *gin.Context, sheets store.GradingSheetStore, units store.UnitOfWork) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.GradingSheets.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.GradingSheets.Delete(c.Request.Context(), id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
+}
+
+// GetGradeDistribution - Get the share of each grade in a stock lot's grading sheets
+func GetGradeDistribution(c *gin.Context, sheets store.GradingSheetStore, stocks store.StockStore) {
+	id := c.Param("id")
+
+	_, err := stocks.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := sheets.List(c.Request.Context(), store.All(store.Filter{Field: "stock_id", Value: id}))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports.GradeDistribution(id, page.Items))
+}
+
+// requireOneSheetPerSize writes a 409 response when the worker already
+// handed in a sheet for the lot and size. id is the sheet being updated, or 0
+func requireOneSheetPerSize(c *gin.Context, sheets store.GradingSheetStore, id int64, sheet models.GradingSheet) bool {
+	page, err := sheets.List(c.Request.Context(), store.All(
+		store.Filter{Field: "stock_id", Value: sheet.StockID},
+		store.Filter{Field: "worker_id", Value: sheet.WorkerID},
+		store.Filter{Field: "size_variations_id", Value: float64(sheet.SizeVariationsID)},
+	))
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	for _, other := range page.Items {
+		if other.ID != id {
+			respondWithStatus(c, http.StatusConflict, "Worker %s already handed in sheet %d for size %d of stock %s",
+				sheet.WorkerID, other.ID, sheet.SizeVariationsID, sheet.StockID)
+			return false
+		}
+	}
+	return true
+}
+
+// requireSheetWithinInput writes a 422 response unless the sheet's grades
+// add up to no more than the weight the worker took in for that lot and size
+func requireSheetWithinInput(c *gin.Context, inputs store.ManualGradingInputStore, sheet models.GradingSheet) bool {
+	page, err := inputs.List(c.Request.Context(), store.All(
+		store.Filter{Field: "stock_id", Value: sheet.StockID},
+		store.Filter{Field: "worker_id", Value: sheet.WorkerID},
+		store.Filter{Field: "size_variations_id", Value: float64(sheet.SizeVariationsID)},
+	))
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	if page.Total == 0 {
+		respondWithStatus(c, http.StatusUnprocessableEntity, "Worker %s has no manual grading input for size %d of stock %s",
+			sheet.WorkerID, sheet.SizeVariationsID, sheet.StockID)
+		return false
+	}
+
+	var takenIn float64
+	for _, input := range page.Items {
+		takenIn += input.Weight
+	}
+	if graded := sheet.Grades.Total(); graded > takenIn+sheetWeightTolerance {
+		respondWithStatus(c, http.StatusUnprocessableEntity, "Sheet grades add up to %.3f kg, more than the %.3f kg worker %s took in for size %d of stock %s",
+			graded, takenIn, sheet.WorkerID, sheet.SizeVariationsID, sheet.StockID)
+		return false
+	}
+	return true
+}
+
+// SetupGradingSheetRoutes - Setup all routes for grading sheets
+func SetupGradingSheetRoutes(router *gin.Engine, stores *store.Stores) {
+	sheets := stores.GradingSheets
+	units := stores.UnitOfWork
+	inputs := stores.ManualGradingInputs
+	stocks := stores.Stocks
+	router.GET("/grading-sheets", func(c *gin.Context) { GetAllGradingSheets(c, sheets) })
+	router.GET("/grading-sheets/:id", func(c *gin.Context) { GetGradingSheet(c, sheets) })
+	router.POST("/grading-sheets", func(c *gin.Context) { CreateGradingSheet(c, sheets, inputs, stocks) })
+	router.PUT("/grading-sheets/:id", func(c *gin.Context) { UpdateGradingSheet(c, sheets, units, inputs) })
+	router.PATCH("/grading-sheets/:id", func(c *gin.Context) { UpdateGradingSheet(c, sheets, units, inputs) })
+	router.DELETE("/grading-sheets/:id", func(c *gin.Context) { DeleteGradingSheet(c, sheets, units) })
+	router.GET("/stocks/:id/grade-distribution", func(c *gin.Context) { GetGradeDistribution(c, sheets, stocks) })
+}
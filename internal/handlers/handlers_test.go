@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"healing_photons/internal/store/memory"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestRouter wires the stock, seller, piece and humidifier routes to fresh
+// in-memory stores, checking stage writes with rules
+func newTestRouter(rules reports.Plausibility) (*gin.Engine, *store.Stores) {
+	gin.SetMode(gin.TestMode)
+	stores := memory.NewStores()
+	router := gin.New()
+	router.Use(UsePlausibility(rules))
+	SetupRoutes(router, stores)
+	SetupSellerRoutes(router, stores)
+	SetupPiecesRoutes(router, stores)
+	SetupHumidifierRoutes(router, stores)
+	return router, stores
+}
+
+// newRequest builds a request with a JSON body
+func newRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// send serves one request with a JSON body and the given header pairs
+func send(router http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := newRequest(method, path, body)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// expect fails the test unless w has status
+func expect(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("got %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+}
+
+// decode reads the JSON body of w
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var body T
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+// receiveStock creates seller 1 and the lot stockID weighing weight, and
+// moves the lot on to humidifying
+func receiveStock(t *testing.T, router http.Handler, stockID, weight string) {
+	t.Helper()
+	expect(t, send(router, http.MethodPost, "/sellers", `{"name":"Sree Traders","country":"IN"}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"`+stockID+`","seller_id":1,"weight":`+weight+`}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks/"+stockID+"/transitions", `{"status":"humidifying"}`), http.StatusCreated)
+}
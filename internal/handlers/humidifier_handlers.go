@@ -1,201 +1,156 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllHumidifiers - Get all humidifier records
-func GetAllHumidifiers(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, stock_id, weight, created_at, updated_at 
-        FROM humidifier`)
+func GetAllHumidifiers(c *gin.Context, humidifiers store.HumidifierStore) {
+	opts, err := parseListOptions(c, store.HumidifierList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var humidifiers []models.Humidifier
-	for rows.Next() {
-		var humidifier models.Humidifier
-		if err := rows.Scan(
-			&humidifier.ID,
-			&humidifier.StockID,
-			&humidifier.Weight,
-			&humidifier.CreatedAt,
-			&humidifier.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		humidifiers = append(humidifiers, humidifier)
+
+	page, err := humidifiers.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, humidifiers)
+	respondWithPage(c, store.HumidifierList, page)
 }
 
 // GetHumidifier - Get single humidifier record
-func GetHumidifier(c *gin.Context, db *sql.DB) {
+func GetHumidifier(c *gin.Context, humidifiers store.HumidifierStore) {
 	id := c.Param("id")
 
-	var humidifier models.Humidifier
-	err := db.QueryRow(`
-        SELECT id, stock_id, weight, created_at, updated_at 
-        FROM humidifier WHERE stock_id = ?`, id).Scan(
-		&humidifier.ID,
-		&humidifier.StockID,
-		&humidifier.Weight,
-		&humidifier.CreatedAt,
-		&humidifier.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	humidifier, err := humidifiers.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, humidifier)
+	respondWithRecord(c, humidifier)
 }
 
 // GetHumidifiersByStockID - Get all humidifiers for a specific stock
-func GetHumidifiersByStockID(c *gin.Context, db *sql.DB) {
+func GetHumidifiersByStockID(c *gin.Context, humidifiers store.HumidifierStore) {
 	stockID := c.Param("stock_id")
 
-	rows, err := db.Query(`
-        SELECT id, stock_id, weight, created_at, updated_at 
-        FROM humidifier WHERE stock_id = ?`, stockID)
+	opts, err := parseListOptions(c, store.HumidifierList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var humidifiers []models.Humidifier
-	for rows.Next() {
-		var humidifier models.Humidifier
-		if err := rows.Scan(
-			&humidifier.ID,
-			&humidifier.StockID,
-			&humidifier.Weight,
-			&humidifier.CreatedAt,
-			&humidifier.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		humidifiers = append(humidifiers, humidifier)
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: stockID})
+
+	page, err := humidifiers.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
 
-	if len(humidifiers) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No humidifiers found for this stock"})
+	if page.Total == 0 {
+		respondWithStatus(c, http.StatusNotFound, "No humidifiers found for this stock")
 		return
 	}
 
-	c.JSON(http.StatusOK, humidifiers)
+	respondWithPage(c, store.HumidifierList, page)
 }
 
 // CreateHumidifier - Create new humidifier record
-func CreateHumidifier(c *gin.Context, db *sql.DB) {
+func CreateHumidifier(c *gin.Context, stores *store.Stores) {
 	var humidifier models.Humidifier
 	if err := c.ShouldBindJSON(&humidifier); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
+		return
+	}
+	if err := prepareHumidifier(c, stores, &humidifier); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	_, err := db.Exec(`
-        INSERT INTO humidifier (
-            id, stock_id, weight, created_at, updated_at
-        )
-        VALUES (?, ?, ?, NOW(), NOW())`,
-		humidifier.ID,
-		humidifier.StockID,
-		humidifier.Weight,
-	)
-
+	err := createPlausibly(c, stores, models.EntityHumidifier, humidifier.StockID, func(tx *store.Stores) (string, error) {
+		return humidifier.ID, tx.Humidifiers.Create(c.Request.Context(), &humidifier)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
-	// Set timestamps manually since we can't get them from RETURNING
-	humidifier.CreatedAt = time.Now()
-	humidifier.UpdatedAt = time.Now()
-
 	c.JSON(http.StatusCreated, humidifier)
 }
 
-// UpdateHumidifier - Update existing humidifier record
-func UpdateHumidifier(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var humidifier models.Humidifier
-	if err := c.ShouldBindJSON(&humidifier); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	result, err := db.Exec(`
-        UPDATE humidifier
-        SET stock_id = ?,
-            weight = ?,
-            updated_at = NOW()
-        WHERE id = ?`,
-		humidifier.StockID,
-		humidifier.Weight,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+// CreateHumidifiers - Create a batch of humidifier records, all or nothing
+// unless ?mode=best_effort
+func CreateHumidifiers(c *gin.Context, stores *store.Stores) {
+	createBatch(c, func(humidifier *models.Humidifier) error {
+		return prepareHumidifier(c, stores, humidifier)
+	}, createManyPlausibly(c, stores, models.EntityHumidifier, func(tx *store.Stores, ctx context.Context, records []*models.Humidifier, atomic bool) ([]error, error) {
+		return tx.Humidifiers.CreateMany(ctx, records, atomic)
+	}, func(humidifier *models.Humidifier) (string, string) {
+		return humidifier.StockID, humidifier.ID
+	}))
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// prepareHumidifier checks a humidifier record about to be created and
+// fills in the fields the server sets
+func prepareHumidifier(c *gin.Context, stores *store.Stores, humidifier *models.Humidifier) error {
+	if err := checkHumidifier(c, stores, humidifier); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := assignID(c, stores.Sequences, models.EntityHumidifier, stageIDFields(c, humidifier.StockID), &humidifier.ID); err != nil {
+		return err
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
+	humidifier.DeviceID = recordingDevice(c)
+	return nil
 }
 
-// DeleteHumidifier - Delete humidifier record
-func DeleteHumidifier(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM humidifier WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// checkHumidifier applies the rules a humidifier record must meet to be
+// stored, as a new record or as the replacement of a corrected one
+func checkHumidifier(c *gin.Context, stores *store.Stores, humidifier *models.Humidifier) error {
+	if err := humidifier.Validate(); err != nil {
+		return err
 	}
+	return checkStockStatus(c.Request.Context(), stores.Stocks, humidifier.StockID, models.StatusHumidifying)
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
-	}
+// CorrectHumidifier - Reverse a humidifier record, posting its replacement
+// unless the record is only being voided
+func CorrectHumidifier(c *gin.Context, stores *store.Stores) {
+	correctRecord(c, c.Param("id"), stores.Humidifiers.Get, func(humidifier *models.Humidifier) error {
+		return checkHumidifier(c, stores, humidifier)
+	}, correctPlausibly(c, stores, models.EntityHumidifier, func(tx *store.Stores, ctx context.Context, reversal, replacement *models.Humidifier) error {
+		return tx.Humidifiers.Correct(ctx, reversal, replacement)
+	}, func(humidifier *models.Humidifier) (string, string) {
+		return humidifier.StockID, humidifier.ID
+	}))
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+// DeleteHumidifier - Move a humidifier record entered by mistake to the trash
+func DeleteHumidifier(c *gin.Context, humidifiers store.HumidifierStore) {
+	id := c.Param("id")
+	deleteEntry(c, id, humidifiers.Get, humidifiers.Delete)
 }
 
 // SetupHumidifierRoutes - Setup all routes for humidifier
-func SetupHumidifierRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/humidifiers", func(c *gin.Context) { GetAllHumidifiers(c, db) })
-	router.GET("/humidifiers/:id", func(c *gin.Context) { GetHumidifier(c, db) })
-	router.GET("/humidifiers/stock/:stock_id", func(c *gin.Context) { GetHumidifiersByStockID(c, db) })
-	router.POST("/humidifiers", func(c *gin.Context) { CreateHumidifier(c, db) })
-	router.PUT("/humidifiers/:id", func(c *gin.Context) { UpdateHumidifier(c, db) })
-	router.DELETE("/humidifiers/:id", func(c *gin.Context) { DeleteHumidifier(c, db) })
+func SetupHumidifierRoutes(router *gin.Engine, stores *store.Stores) {
+	humidifiers := stores.Humidifiers
+	router.GET("/humidifiers", func(c *gin.Context) { GetAllHumidifiers(c, humidifiers) })
+	router.GET("/humidifiers/:id", func(c *gin.Context) { GetHumidifier(c, humidifiers) })
+	router.GET("/humidifiers/stock/:stock_id", func(c *gin.Context) { GetHumidifiersByStockID(c, humidifiers) })
+	router.POST("/humidifiers", func(c *gin.Context) { CreateHumidifier(c, stores) })
+	router.POST("/humidifiers/bulk", func(c *gin.Context) { CreateHumidifiers(c, stores) })
+	router.POST("/humidifiers/:id/corrections", func(c *gin.Context) { CorrectHumidifier(c, stores) })
+	router.PATCH("/humidifiers/:id", func(c *gin.Context) { CorrectHumidifier(c, stores) })
+	router.DELETE("/humidifiers/:id", func(c *gin.Context) { DeleteHumidifier(c, humidifiers) })
 }
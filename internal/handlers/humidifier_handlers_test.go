@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+func TestGetHumidifierByID(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H2","stock_id":"L1","weight":400}`), http.StatusCreated)
+
+	w := send(router, http.MethodGet, "/humidifiers/H1", "")
+	expect(t, w, http.StatusOK)
+	if got := decode[models.Humidifier](t, w); got.ID != "H1" {
+		t.Errorf("got record %s, want H1", got.ID)
+	}
+
+	// A lot's records are listed under /humidifiers/stock/:stock_id instead
+	expect(t, send(router, http.MethodGet, "/humidifiers/L1", ""), http.StatusNotFound)
+}
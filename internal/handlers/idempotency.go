@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// replayedHeader marks a response that was stored for an earlier
+	// request with the same key
+	replayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// unreplayable lists the POST routes whose responses must not be stored
+// for replay, because they carry a secret that is only ever shown once.
+// A retry of one of these runs again, as it would without the header
+var unreplayable = map[string]bool{
+	"POST /devices":                true,
+	"POST /devices/:id/rotate-key": true,
+}
+
+// responseRecorder keeps a copy of the response body as it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotent makes POST requests sent with an Idempotency-Key header safe
+// to retry. The first request with a key runs as usual and a successful
+// response is kept for ttl; a retry with the same key and body gets that
+// response back, marked Idempotent-Replayed, without running again. A
+// request that fails keeps nothing, so it can be retried as it was. A key
+// whose request has run for longer than lease is taken to belong to a
+// request that died, such as in a crash, and the next retry takes it over
+func Idempotent(keys store.IdempotencyStore, ttl, lease time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" || unreplayable[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, reject(http.StatusBadRequest, "%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+			return
+		}
+		actor, ok := store.ActorFrom(c.Request.Context())
+		if !ok {
+			c.Next()
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil {
+			abortWithError(c, invalid(err))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		claim := models.IdempotencyKey{
+			Caller:      idempotencyCaller(actor),
+			Key:         key,
+			RequestHash: requestHash(c.Request, body),
+		}
+		now := time.Now()
+		stored, claimed, err := keys.Claim(c.Request.Context(), claim, now.Add(-ttl), now.Add(-lease))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if !claimed {
+			replay(c, claim, stored)
+			return
+		}
+		claim = stored
+
+		// The outcome is stored even when the client has gone away, since
+		// that is exactly when it will retry
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		ctx := context.WithoutCancel(c.Request.Context())
+		if status := recorder.Status(); status >= 200 && status < 300 {
+			err = keys.Complete(ctx, claim, status, recorder.body.Bytes())
+		} else {
+			err = keys.Release(ctx, claim)
+		}
+		if err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// SweepIdempotencyKeys forgets the keys older than ttl every interval
+// until ctx is done. Claim only clears an expired key when its caller sends
+// it again, so without the sweep the keys of requests that are never
+// retried would be kept for good
+func SweepIdempotencyKeys(ctx context.Context, keys store.IdempotencyStore, ttl, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		swept, err := keys.Sweep(ctx, time.Now().Add(-ttl))
+		if err != nil {
+			log.Printf("Failed to sweep idempotency keys: %v", err)
+		} else if swept > 0 {
+			log.Printf("Swept %d expired idempotency key(s)", swept)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replay answers a request whose key was already claimed by an earlier one
+func replay(c *gin.Context, claim, stored models.IdempotencyKey) {
+	if stored.RequestHash != claim.RequestHash {
+		abortWithError(c, rejectField(http.StatusUnprocessableEntity, codeKeyReused, "", "%s %q was already used for a different request", idempotencyKeyHeader, claim.Key))
+		return
+	}
+	if !stored.Completed() {
+		c.Header("Retry-After", "1")
+		abortWithError(c, rejectField(http.StatusConflict, codeInProgress, "", "A request with %s %q is still being processed; retry shortly", idempotencyKeyHeader, claim.Key))
+		return
+	}
+	c.Header(replayedHeader, "true")
+	c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.Response)
+	c.Abort()
+}
+
+// idempotencyCaller names the caller a key belongs to, so two devices that
+// happen to pick the same key don't see each other's responses
+func idempotencyCaller(actor models.Actor) string {
+	if actor.ID != nil {
+		return actor.Type + ":" + strconv.FormatInt(*actor.ID, 10)
+	}
+	return actor.Type + ":" + actor.Name
+}
+
+// requestHash identifies a request by its method, target and body, so a
+// key reused for something else can be told apart from a retry
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
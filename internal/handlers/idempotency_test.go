@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"healing_photons/internal/store/memory"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newIdempotentRouter serves the seller and device routes to user 1
+// through Idempotent
+func newIdempotentRouter(lease time.Duration) (*gin.Engine, *store.Stores) {
+	gin.SetMode(gin.TestMode)
+	stores := memory.NewStores()
+	router := gin.New()
+	id := int64(1)
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(store.WithActor(c.Request.Context(), models.Actor{Type: models.ActorUser, ID: &id, Name: "admin"}))
+	})
+	router.Use(Idempotent(stores.Idempotency, time.Hour, lease))
+	SetupSellerRoutes(router, stores)
+	SetupDeviceRoutes(router, stores)
+	return router, stores
+}
+
+func TestIdempotentReplaysResponse(t *testing.T) {
+	router, stores := newIdempotentRouter(time.Minute)
+
+	first := send(router, http.MethodPost, "/sellers", sellerBody, "Idempotency-Key", "k1")
+	expect(t, first, http.StatusCreated)
+	retry := send(router, http.MethodPost, "/sellers", sellerBody, "Idempotency-Key", "k1")
+	expect(t, retry, http.StatusCreated)
+	if retry.Header().Get(replayedHeader) != "true" || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %s, want the first response replayed", retry.Body.String())
+	}
+	page, err := stores.Sellers.List(context.Background(), store.ListOptions{Sort: store.SellerList.DefaultSort})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 1 {
+		t.Errorf("sellers = %d, want the retry not to create another", page.Total)
+	}
+
+	w := send(router, http.MethodPost, "/sellers", `{"name":"Other","country":"IN"}`, "Idempotency-Key", "k1")
+	expect(t, w, http.StatusUnprocessableEntity)
+	if got := decode[errorResponse](t, w); got.Error.Code != codeKeyReused {
+		t.Errorf("code = %q, want %q", got.Error.Code, codeKeyReused)
+	}
+}
+
+func TestIdempotentForgetsFailures(t *testing.T) {
+	router, _ := newIdempotentRouter(time.Minute)
+
+	expect(t, send(router, http.MethodPost, "/sellers", `{"country":"IN"}`, "Idempotency-Key", "k1"), http.StatusBadRequest)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody, "Idempotency-Key", "k1"), http.StatusCreated)
+}
+
+func TestIdempotentTakesOverAbandonedClaims(t *testing.T) {
+	claim := func(stores *store.Stores) {
+		t.Helper()
+		req := newRequest(http.MethodPost, "/sellers", sellerBody)
+		_, claimed, err := stores.Idempotency.Claim(context.Background(), models.IdempotencyKey{
+			Caller: "user:1", Key: "k1", RequestHash: requestHash(req, []byte(sellerBody)),
+		}, time.Now().Add(-time.Hour), time.Now().Add(-time.Minute))
+		if err != nil || !claimed {
+			t.Fatalf("claim = %v, %v", claimed, err)
+		}
+	}
+
+	// A request still within its lease holds the key
+	router, stores := newIdempotentRouter(time.Hour)
+	claim(stores)
+	w := send(router, http.MethodPost, "/sellers", sellerBody, "Idempotency-Key", "k1")
+	expect(t, w, http.StatusConflict)
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("want Retry-After on an in-progress key")
+	}
+
+	// One that has run past it is taken to have died
+	router, stores = newIdempotentRouter(time.Nanosecond)
+	claim(stores)
+	time.Sleep(time.Millisecond)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody, "Idempotency-Key", "k1"), http.StatusCreated)
+}
+
+func TestIdempotentNeverStoresDeviceKeys(t *testing.T) {
+	router, stores := newIdempotentRouter(time.Minute)
+
+	body := `{"name":"Scale 1","stages":["humidifying"]}`
+	first := decode[DeviceKeyResponse](t, send(router, http.MethodPost, "/devices", body, "Idempotency-Key", "k1"))
+	rotated := decode[DeviceKeyResponse](t, send(router, http.MethodPost, "/devices/1/rotate-key", "", "Idempotency-Key", "k2"))
+	if first.APIKey == "" || rotated.APIKey == "" || rotated.APIKey == first.APIKey {
+		t.Fatalf("keys = %q and %q, want two fresh keys", first.APIKey, rotated.APIKey)
+	}
+	if swept, _ := stores.Idempotency.Sweep(context.Background(), time.Now()); swept != 0 {
+		t.Errorf("stored %d responses, want none holding an API key", swept)
+	}
+}
+
+func TestSweepIdempotencyKeys(t *testing.T) {
+	router, stores := newIdempotentRouter(time.Minute)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody, "Idempotency-Key", "k1"), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/sellers", `{"name":"Other","country":"IN"}`, "Idempotency-Key", "k2"), http.StatusCreated)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	SweepIdempotencyKeys(ctx, stores.Idempotency, -time.Second, time.Hour)
+
+	// Both keys are gone, so k1 now creates a second seller
+	expect(t, send(router, http.MethodPost, "/sellers", `{"name":"Third","country":"IN"}`, "Idempotency-Key", "k1"), http.StatusCreated)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"healing_photons/internal/ids"
+	"healing_photons/internal/store"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const idSchemeKey = "id_scheme"
+
+// UseIDScheme makes the plant's ID patterns available to the create
+// handlers that mint IDs
+func UseIDScheme(scheme ids.Scheme) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(idSchemeKey, scheme)
+		c.Next()
+	}
+}
+
+// idScheme returns the scheme set by UseIDScheme, or the default patterns
+func idScheme(c *gin.Context) ids.Scheme {
+	if scheme, ok := c.Get(idSchemeKey); ok {
+		return scheme.(ids.Scheme)
+	}
+	return defaultIDScheme
+}
+
+var defaultIDScheme = ids.DefaultScheme()
+
+// assignID mints the next ID of entity into id when the client left it
+// empty. A client-supplied ID is kept if the scheme accepts client IDs and
+// it can't be mistaken for one the server mints; otherwise a rejection is
+// returned
+func assignID(c *gin.Context, sequences store.SequenceStore, entity string, fields ids.Fields, id *string) error {
+	scheme := idScheme(c)
+	pattern := scheme.Patterns[entity]
+	if *id != "" {
+		if !scheme.ClientIDs {
+			return reject(http.StatusBadRequest, "%s IDs are assigned by the server; leave the ID out", entity)
+		}
+		if pattern.Matches(*id) {
+			return reject(http.StatusBadRequest, "ID %s has the form of a server-assigned ID (%s); choose one that doesn't, or leave it out", *id, pattern)
+		}
+		return nil
+	}
+
+	seq, err := sequences.Next(c.Request.Context(), pattern.Sequence(fields))
+	if err != nil {
+		return err
+	}
+	*id = pattern.Format(fields, seq)
+	return nil
+}
+
+// stageIDFields are the values a stage record's ID is filled in with: its
+// lot and the plant day it is recorded on
+func stageIDFields(c *gin.Context, stockID string) ids.Fields {
+	return ids.Fields{Day: plantCalendar(c).DayOf(time.Now()), StockID: stockID}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"healing_photons/internal/ids"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store/memory"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCreateStockMintsID(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+
+	for _, want := range []string{"STK-2025-000001", "STK-2025-000002"} {
+		w := send(router, http.MethodPost, "/stocks", `{"seller_id":1,"weight":1000,"date":"2025-03-10T08:00:00Z"}`)
+		expect(t, w, http.StatusCreated)
+		if got := decode[models.Stock](t, w).StockID; got != want {
+			t.Errorf("minted %s, want %s", got, want)
+		}
+	}
+
+	// A lot without a date is dated, and numbered, as received now
+	w := send(router, http.MethodPost, "/stocks", `{"seller_id":1,"weight":1000}`)
+	expect(t, w, http.StatusCreated)
+	stock := decode[models.Stock](t, w)
+	if stock.Date.IsZero() {
+		t.Fatal("lot created without a date was left undated")
+	}
+	if want := "STK-" + strconv.Itoa(stock.Date.Year()) + "-"; stock.StockID[:len(want)] != want {
+		t.Errorf("minted %s, want it numbered in %d", stock.StockID, stock.Date.Year())
+	}
+}
+
+func TestCreateStockRejectsClientIDInServerForm(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"STK-2025-000099","seller_id":1,"weight":1000}`), http.StatusBadRequest)
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"L1","seller_id":1,"weight":1000}`), http.StatusCreated)
+}
+
+func TestCreateStockWithoutClientIDs(t *testing.T) {
+	scheme := ids.DefaultScheme()
+	scheme.ClientIDs = false
+	stores := memory.NewStores()
+	router := gin.New()
+	router.Use(UseIDScheme(scheme))
+	SetupRoutes(router, stores)
+	SetupSellerRoutes(router, stores)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"L1","seller_id":1,"weight":1000}`), http.StatusBadRequest)
+}
+
+func TestCreateStageRecordMintsID(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+
+	w := send(router, http.MethodPost, "/humidifiers", `{"stock_id":"L1","weight":500}`)
+	expect(t, w, http.StatusCreated)
+	if got := decode[models.Humidifier](t, w).ID; got != "HUM-L1-001" {
+		t.Errorf("minted %s, want HUM-L1-001", got)
+	}
+}
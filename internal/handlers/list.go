@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"healing_photons/internal/plant"
+	"healing_photons/internal/store"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+const calendarKey = "plant_calendar"
+
+// UsePlantCalendar makes the plant's working days available to handlers so
+// dates in queries mean the plant's day rather than the UTC one
+func UsePlantCalendar(calendar plant.Calendar) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(calendarKey, calendar)
+		c.Next()
+	}
+}
+
+// plantCalendar returns the calendar set by UsePlantCalendar, or UTC days
+func plantCalendar(c *gin.Context) plant.Calendar {
+	if calendar, ok := c.Get(calendarKey); ok {
+		return calendar.(plant.Calendar)
+	}
+	return plant.UTC
+}
+
+// parseListOptions reads the query parameters shared by every list
+// endpoint: limit, cursor, sort, from, to, day and one equality filter per
+// filterable field, e.g. ?limit=20&sort=-created_at&stock_id=S1&day=today
+func parseListOptions[T any](c *gin.Context, spec store.ListSpec[T]) (store.ListOptions, error) {
+	opts := store.ListOptions{Limit: defaultPageLimit}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return opts, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+		}
+		opts.Limit = limit
+	}
+
+	if raw := c.Query("sort"); raw != "" {
+		sort, err := spec.ParseSort(raw)
+		if err != nil {
+			return opts, err
+		}
+		opts.Sort = sort
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := spec.DecodeCursor(raw)
+		if err != nil {
+			return opts, err
+		}
+		if opts.Sort.Field == "" {
+			opts.Sort = cursor.Sort
+		} else if opts.Sort != cursor.Sort {
+			return opts, fmt.Errorf("cursor was issued for sort %s", cursor.Sort)
+		}
+		opts.After = &cursor
+	}
+
+	for _, field := range spec.Filterable {
+		raw, ok := c.GetQuery(field)
+		if !ok {
+			continue
+		}
+		value, err := spec.ParseValue(field, raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s", field)
+		}
+		opts.Filters = append(opts.Filters, store.Filter{Field: field, Value: value})
+	}
+
+	from, hasFrom := c.GetQuery("from")
+	to, hasTo := c.GetQuery("to")
+	day, hasDay := c.GetQuery("day")
+	if (hasFrom || hasTo || hasDay) && spec.TimeField == "" {
+		return opts, fmt.Errorf("this list cannot be filtered by date")
+	}
+	if hasDay && (hasFrom || hasTo) {
+		return opts, fmt.Errorf("day cannot be combined with from or to")
+	}
+
+	calendar := plantCalendar(c)
+	if hasDay {
+		start, end, err := calendar.Day(day, time.Now())
+		if err != nil {
+			return opts, fmt.Errorf("invalid day: %v", err)
+		}
+		opts.From, opts.To = start, end
+	}
+	if hasFrom {
+		start, err := parseTimeParam(calendar, from, false)
+		if err != nil {
+			return opts, fmt.Errorf("invalid from: %v", err)
+		}
+		opts.From = start
+	}
+	if hasTo {
+		end, err := parseTimeParam(calendar, to, true)
+		if err != nil {
+			return opts, fmt.Errorf("invalid to: %v", err)
+		}
+		opts.To = end
+	}
+
+	return opts, nil
+}
+
+// parseTimeParam accepts an RFC 3339 timestamp or a working day as taken by
+// plant.Calendar.Day. A day used as the end of a range covers that whole day
+func parseTimeParam(calendar plant.Calendar, raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	start, next, err := calendar.Day(raw, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 timestamp, YYYY-MM-DD, today or yesterday")
+	}
+	if end {
+		return next, nil
+	}
+	return start, nil
+}
+
+// respondWithPage writes a page in the envelope shared by every list
+// endpoint. next_cursor is null on the last page
+func respondWithPage[T any](c *gin.Context, spec store.ListSpec[T], page store.Page[T]) {
+	var next *string
+	if page.Next != nil {
+		cursor := spec.EncodeCursor(*page.Next)
+		next = &cursor
+	}
+	respondWithItems(c, page.Items, next, page.Total)
+}
+
+// respondWithItems writes the list envelope for items derived from a page
+// of another resource, such as reports built over stock lots
+func respondWithItems(c *gin.Context, items any, next *string, total int) {
+	c.JSON(http.StatusOK, gin.H{
+		"data":        items,
+		"next_cursor": next,
+		"total":       total,
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+// sellerPage is a page of the seller list
+type sellerPage struct {
+	Data       []models.Seller
+	NextCursor *string `json:"next_cursor"`
+	Total      int
+}
+
+// listSellers walks every page of query and returns the names in order
+func listSellers(t *testing.T, router http.Handler, query string) []string {
+	t.Helper()
+	var names []string
+	cursor := ""
+	for {
+		w := send(router, http.MethodGet, "/sellers?"+query+cursor, "")
+		expect(t, w, http.StatusOK)
+		page := decode[sellerPage](t, w)
+		for _, seller := range page.Data {
+			names = append(names, seller.Name)
+		}
+		if page.NextCursor == nil {
+			return names
+		}
+		cursor = "&cursor=" + url.QueryEscape(*page.NextCursor)
+	}
+}
+
+func TestListPagesWithCursors(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	for _, seller := range []string{
+		`{"name":"Delta Cashews","country":"IN"}`,
+		`{"name":"Alpha Nuts","country":"CI"}`,
+		`{"name":"Echo Exports","country":"IN"}`,
+		`{"name":"Bravo Traders","country":"GH"}`,
+		`{"name":"Charlie Agro","country":"IN"}`,
+	} {
+		expect(t, send(router, http.MethodPost, "/sellers", seller), http.StatusCreated)
+	}
+
+	w := send(router, http.MethodGet, "/sellers?limit=2", "")
+	expect(t, w, http.StatusOK)
+	if page := decode[sellerPage](t, w); page.Total != 5 || len(page.Data) != 2 || page.NextCursor == nil {
+		t.Fatalf("first page = %+v, want 2 of 5 with a cursor", page)
+	}
+
+	byName := []string{"Alpha Nuts", "Bravo Traders", "Charlie Agro", "Delta Cashews", "Echo Exports"}
+	if got := listSellers(t, router, "limit=2"); !slices.Equal(got, byName) {
+		t.Errorf("pages by name = %v, want %v", got, byName)
+	}
+	reversed := slices.Clone(byName)
+	slices.Reverse(reversed)
+	if got := listSellers(t, router, "limit=2&sort=-name"); !slices.Equal(got, reversed) {
+		t.Errorf("pages by -name = %v, want %v", got, reversed)
+	}
+	if got := listSellers(t, router, "limit=1&country=IN"); !slices.Equal(got, []string{"Charlie Agro", "Delta Cashews", "Echo Exports"}) {
+		t.Errorf("pages from IN = %v, want the three Indian sellers", got)
+	}
+}
+
+func TestListRejectsBadOptions(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/sellers", `{"name":"Alpha Nuts","country":"CI"}`), http.StatusCreated)
+
+	w := send(router, http.MethodGet, "/sellers?limit=1", "")
+	expect(t, w, http.StatusOK)
+	cursor := url.QueryEscape(*decode[sellerPage](t, w).NextCursor)
+
+	for _, query := range []string{
+		"limit=0",
+		"limit=many",
+		"sort=aadhaar",
+		"cursor=garbage",
+		// A cursor only continues the sort it was issued for
+		"sort=-name&cursor=" + cursor,
+	} {
+		expect(t, send(router, http.MethodGet, "/sellers?"+query, ""), http.StatusBadRequest)
+	}
+}
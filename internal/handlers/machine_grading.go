@@ -1,271 +1,187 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllMachineGradings - Get all machine grading records
-func GetAllMachineGradings(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, color_sort_id, stock_id, size_variations_id, pieces_id,
-               weight, created_at, updated_at 
-        FROM machine_grading`)
+func GetAllMachineGradings(c *gin.Context, gradings store.MachineGradingStore) {
+	opts, err := parseListOptions(c, store.MachineGradingList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
 
-	var gradings []models.MachineGrading
-	for rows.Next() {
-		var grading models.MachineGrading
-		if err := rows.Scan(
-			&grading.ID,
-			&grading.ColorSortID,
-			&grading.StockID,
-			&grading.SizeVariationsID,
-			&grading.PiecesID,
-			&grading.Weight,
-			&grading.CreatedAt,
-			&grading.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		gradings = append(gradings, grading)
+	page, err := gradings.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, gradings)
+	respondWithPage(c, store.MachineGradingList, page)
 }
 
 // GetMachineGrading - Get single machine grading record
-func GetMachineGrading(c *gin.Context, db *sql.DB) {
+func GetMachineGrading(c *gin.Context, gradings store.MachineGradingStore) {
 	id := c.Param("id")
 
-	var grading models.MachineGrading
-	err := db.QueryRow(`
-        SELECT id, color_sort_id, stock_id, size_variations_id, pieces_id,
-               weight, created_at, updated_at 
-        FROM machine_grading WHERE id = ?`, id).Scan(
-		&grading.ID,
-		&grading.ColorSortID,
-		&grading.StockID,
-		&grading.SizeVariationsID,
-		&grading.PiecesID,
-		&grading.Weight,
-		&grading.CreatedAt,
-		&grading.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	grading, err := gradings.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, grading)
+	respondWithRecord(c, grading)
 }
 
 // CreateMachineGrading - Create new machine grading record
-func CreateMachineGrading(c *gin.Context, db *sql.DB) {
+func CreateMachineGrading(c *gin.Context, stores *store.Stores) {
 	var grading models.MachineGrading
 	if err := c.ShouldBindJSON(&grading); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-
-	// Insert the record
-	_, err := db.Exec(`
-        INSERT INTO machine_grading (
-            id, color_sort_id, stock_id, size_variations_id, pieces_id,
-            weight, created_at, updated_at
-        )
-        VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())`,
-		grading.ID,
-		grading.ColorSortID,
-		grading.StockID,
-		grading.SizeVariationsID,
-		grading.PiecesID,
-		grading.Weight,
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := prepareMachineGrading(c, stores, &grading); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	// Fetch the created record to get timestamps
-	err = db.QueryRow(`
-        SELECT id, color_sort_id, stock_id, size_variations_id, pieces_id,
-               weight, created_at, updated_at 
-        FROM machine_grading WHERE id = ?`, grading.ID).Scan(
-		&grading.ID,
-		&grading.ColorSortID,
-		&grading.StockID,
-		&grading.SizeVariationsID,
-		&grading.PiecesID,
-		&grading.Weight,
-		&grading.CreatedAt,
-		&grading.UpdatedAt,
-	)
-
+	err := createPlausibly(c, stores, models.EntityMachineGrading, grading.StockID, func(tx *store.Stores) (string, error) {
+		return grading.ID, tx.MachineGradings.Create(c.Request.Context(), &grading)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, grading)
 }
 
-// UpdateMachineGrading - Update existing machine grading record
-func UpdateMachineGrading(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var grading models.MachineGrading
-	if err := c.ShouldBindJSON(&grading); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	result, err := db.Exec(`
-        UPDATE machine_grading
-        SET color_sort_id = ?,
-            stock_id = ?,
-            size_variations_id = ?,
-            pieces_id = ?,
-            weight = ?,
-            updated_at = NOW()
-        WHERE id = ?`,
-		grading.ColorSortID,
-		grading.StockID,
-		grading.SizeVariationsID,
-		grading.PiecesID,
-		grading.Weight,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+// CreateMachineGradings - Create a batch of machine grading records, all or
+// nothing unless ?mode=best_effort
+func CreateMachineGradings(c *gin.Context, stores *store.Stores) {
+	createBatch(c, func(grading *models.MachineGrading) error {
+		return prepareMachineGrading(c, stores, grading)
+	}, createManyPlausibly(c, stores, models.EntityMachineGrading, func(tx *store.Stores, ctx context.Context, records []*models.MachineGrading, atomic bool) ([]error, error) {
+		return tx.MachineGradings.CreateMany(ctx, records, atomic)
+	}, func(grading *models.MachineGrading) (string, string) {
+		return grading.StockID, grading.ID
+	}))
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// prepareMachineGrading checks a machine grading record about to be
+// created and fills in the fields the server sets
+func prepareMachineGrading(c *gin.Context, stores *store.Stores, grading *models.MachineGrading) error {
+	if err := checkMachineGrading(c, stores, grading); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := assignID(c, stores.Sequences, models.EntityMachineGrading, stageIDFields(c, grading.StockID), &grading.ID); err != nil {
+		return err
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
+	grading.DeviceID = recordingDevice(c)
+	return nil
 }
 
-// DeleteMachineGrading - Delete machine grading record
-func DeleteMachineGrading(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM machine_grading WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// checkMachineGrading applies the rules a machine grading record must meet
+// to be stored, as a new record or as the replacement of a corrected one
+func checkMachineGrading(c *gin.Context, stores *store.Stores, grading *models.MachineGrading) error {
+	ctx := c.Request.Context()
+	var problems models.Violations
+	problems.Include(grading.Validate())
+	if grading.ColorSortID != "" {
+		colorSort, found, err := checkExists(ctx, &problems, "color_sort_id", grading.ColorSortID, stores.ColorSorts.Get, "Color sort record")
+		if err != nil {
+			return err
+		}
+		// Records posted without a stock belong to the sorted batch's stock
+		if found && grading.StockID == "" && colorSort.StockID != nil {
+			grading.StockID = *colorSort.StockID
+		}
+		if found && colorSort.StockID != nil {
+			checkSameStock(&problems, "color_sort_id", grading.StockID, *colorSort.StockID, "Color sort record", colorSort.ID)
+		}
+		if found {
+			err := checkCurrent(ctx, &problems, "color_sort_id", colorSort.ID, colorSort.EntryType, stores.ColorSorts.List, func(s models.ColorSort) (models.EntryType, string) {
+				return s.EntryType, s.ID
+			}, "Color sort record")
+			if err != nil {
+				return err
+			}
+		}
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if grading.SizeVariationsID.Valid {
+		if err := checkSize(ctx, stores.SizeVariations, &problems, "size_variations_id", grading.SizeVariationsID.Int64); err != nil {
+			return err
+		}
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if grading.PiecesID.Valid {
+		if err := checkPiece(ctx, stores.Pieces, &problems, "pieces_id", grading.PiecesID.Int64); err != nil {
+			return err
+		}
+	}
+	if err := problems.Err(); err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	if err := checkStockID(&grading.StockID); err != nil {
+		return err
+	}
+	return checkStockStatus(ctx, stores.Stocks, grading.StockID, models.StatusMachineGrading)
+}
+
+// CorrectMachineGrading - Reverse a machine grading record, posting its replacement
+// unless the record is only being voided
+func CorrectMachineGrading(c *gin.Context, stores *store.Stores) {
+	correctRecord(c, c.Param("id"), stores.MachineGradings.Get, func(grading *models.MachineGrading) error {
+		return checkMachineGrading(c, stores, grading)
+	}, correctPlausibly(c, stores, models.EntityMachineGrading, func(tx *store.Stores, ctx context.Context, reversal, replacement *models.MachineGrading) error {
+		return tx.MachineGradings.Correct(ctx, reversal, replacement)
+	}, func(grading *models.MachineGrading) (string, string) {
+		return grading.StockID, grading.ID
+	}))
+}
+
+// DeleteMachineGrading - Move a machine grading record entered by mistake to the trash
+func DeleteMachineGrading(c *gin.Context, gradings store.MachineGradingStore) {
+	id := c.Param("id")
+	deleteEntry(c, id, gradings.Get, gradings.Delete)
 }
 
 // GetMachineGradingsByStock - Get machine grading records for a specific stock ID
-func GetMachineGradingsByStock(c *gin.Context, db *sql.DB) {
+func GetMachineGradingsByStock(c *gin.Context, gradings store.MachineGradingStore) {
 	stockID := c.Param("stockId")
 
-	rows, err := db.Query(`
-        SELECT id, color_sort_id, stock_id, size_variations_id, pieces_id,
-               weight, created_at, updated_at 
-        FROM machine_grading 
-        WHERE stock_id = ?
-        ORDER BY created_at DESC`, stockID)
+	opts, err := parseListOptions(c, store.MachineGradingList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: stockID})
 
-	var gradings []models.MachineGrading
-	for rows.Next() {
-		var grading models.MachineGrading
-		if err := rows.Scan(
-			&grading.ID,
-			&grading.ColorSortID,
-			&grading.StockID,
-			&grading.SizeVariationsID,
-			&grading.PiecesID,
-			&grading.Weight,
-			&grading.CreatedAt,
-			&grading.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		gradings = append(gradings, grading)
-	}
-
-	if len(gradings) == 0 {
-		c.JSON(http.StatusOK, []models.MachineGrading{}) // Return empty array instead of null
+	page, err := gradings.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gradings)
+	respondWithPage(c, store.MachineGradingList, page)
 }
 
 // GetWeightSummary - Get summary of weights for a stock ID
-func GetWeightSummary(c *gin.Context, db *sql.DB) {
+func GetWeightSummary(c *gin.Context, gradings store.MachineGradingStore) {
 	stockID := c.Param("stockId")
 
-	var summary struct {
-		StockID     string  `json:"stock_id"`
-		TotalWeight float64 `json:"total_weight"`
-		RecordCount int     `json:"record_count"`
-	}
-
-	err := db.QueryRow(`
-        SELECT 
-            stock_id,
-            COALESCE(SUM(weight), 0) as total_weight,
-            COUNT(*) as record_count
-        FROM machine_grading 
-        WHERE stock_id = ?
-        GROUP BY stock_id`,
-		stockID,
-	).Scan(
-		&summary.StockID,
-		&summary.TotalWeight,
-		&summary.RecordCount,
-	)
-
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusOK, gin.H{
-			"stock_id":     stockID,
-			"total_weight": 0,
-			"record_count": 0,
-		})
-		return
-	}
-
+	summary, err := gradings.WeightSummary(c.Request.Context(), stockID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
@@ -273,12 +189,15 @@ func GetWeightSummary(c *gin.Context, db *sql.DB) {
 }
 
 // SetupMachineGradingRoutes - Setup all routes for machine grading
-func SetupMachineGradingRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/machine-gradings", func(c *gin.Context) { GetAllMachineGradings(c, db) })
-	router.GET("/machine-gradings/:id", func(c *gin.Context) { GetMachineGrading(c, db) })
-	router.POST("/machine-gradings", func(c *gin.Context) { CreateMachineGrading(c, db) })
-	router.PUT("/machine-gradings/:id", func(c *gin.Context) { UpdateMachineGrading(c, db) })
-	router.DELETE("/machine-gradings/:id", func(c *gin.Context) { DeleteMachineGrading(c, db) })
-	router.GET("/machine-gradings/stock/:stockId", func(c *gin.Context) { GetMachineGradingsByStock(c, db) })
-	router.GET("/machine-gradings/stock/:stockId/summary", func(c *gin.Context) { GetWeightSummary(c, db) })
+func SetupMachineGradingRoutes(router *gin.Engine, stores *store.Stores) {
+	gradings := stores.MachineGradings
+	router.GET("/machine-gradings", func(c *gin.Context) { GetAllMachineGradings(c, gradings) })
+	router.GET("/machine-gradings/:id", func(c *gin.Context) { GetMachineGrading(c, gradings) })
+	router.POST("/machine-gradings", func(c *gin.Context) { CreateMachineGrading(c, stores) })
+	router.POST("/machine-gradings/bulk", func(c *gin.Context) { CreateMachineGradings(c, stores) })
+	router.POST("/machine-gradings/:id/corrections", func(c *gin.Context) { CorrectMachineGrading(c, stores) })
+	router.PATCH("/machine-gradings/:id", func(c *gin.Context) { CorrectMachineGrading(c, stores) })
+	router.DELETE("/machine-gradings/:id", func(c *gin.Context) { DeleteMachineGrading(c, gradings) })
+	router.GET("/machine-gradings/stock/:stockId", func(c *gin.Context) { GetMachineGradingsByStock(c, gradings) })
+	router.GET("/machine-gradings/stock/:stockId/summary", func(c *gin.Context) { GetWeightSummary(c, gradings) })
 }
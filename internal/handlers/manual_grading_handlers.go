@@ -1,249 +1,162 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllManualGradings - Get all manual grading records
-func GetAllManualGradings(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-		SELECT id, grader_machine_outputs_id, stock_id, category_id, 
-			size_id, piece_id, weight, worker_id, created_at, updated_at
-		FROM manual_grading
-		ORDER BY created_at DESC`)
+func GetAllManualGradings(c *gin.Context, gradings store.ManualGradingStore) {
+	opts, err := parseListOptions(c, store.ManualGradingList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var gradings []models.ManualGrading
-	for rows.Next() {
-		var grading models.ManualGrading
-		if err := rows.Scan(
-			&grading.ID,
-			&grading.GraderMachineOutputsID,
-			&grading.StockID,
-			&grading.CategoryID,
-			&grading.SizeID,
-			&grading.PieceID,
-			&grading.Weight,
-			&grading.WorkerID,
-			&grading.CreatedAt,
-			&grading.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		gradings = append(gradings, grading)
+
+	page, err := gradings.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, gradings)
+	respondWithPage(c, store.ManualGradingList, page)
 }
 
 // GetManualGrading - Get single manual grading record
-func GetManualGrading(c *gin.Context, db *sql.DB) {
+func GetManualGrading(c *gin.Context, gradings store.ManualGradingStore) {
 	id := c.Param("id")
 
-	var grading models.ManualGrading
-	err := db.QueryRow(`
-		SELECT id, grader_machine_outputs_id, stock_id, category_id, 
-			size_id, piece_id, weight, worker_id, created_at, updated_at
-		FROM manual_grading WHERE id = ?`, id).Scan(
-		&grading.ID,
-		&grading.GraderMachineOutputsID,
-		&grading.StockID,
-		&grading.CategoryID,
-		&grading.SizeID,
-		&grading.PieceID,
-		&grading.Weight,
-		&grading.WorkerID,
-		&grading.CreatedAt,
-		&grading.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	grading, err := gradings.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, grading)
+	respondWithRecord(c, grading)
 }
 
 // CreateManualGrading - Create new manual grading record
-func CreateManualGrading(c *gin.Context, db *sql.DB) {
+func CreateManualGrading(c *gin.Context, stores *store.Stores) {
 	var grading models.ManualGrading
 	if err := c.ShouldBindJSON(&grading); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-
-	// Insert the record
-	_, err := db.Exec(`
-		INSERT INTO manual_grading (
-			id, grader_machine_outputs_id, stock_id, category_id, 
-			size_id, piece_id, weight, worker_id, created_at, updated_at
-		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
-		grading.ID,
-		grading.GraderMachineOutputsID,
-		grading.StockID,
-		grading.CategoryID,
-		grading.SizeID,
-		grading.PieceID,
-		grading.Weight,
-		grading.WorkerID,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := checkManualGrading(c, stores, &grading); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	// Fetch the created record to get timestamps
-	err = db.QueryRow(`
-        SELECT id, grader_machine_outputs_id, stock_id, category_id, 
-			size_id, piece_id, weight, worker_id, created_at, updated_at
-        FROM manual_grading WHERE id = ?`, grading.ID).Scan(
-		&grading.ID,
-		&grading.GraderMachineOutputsID,
-		&grading.StockID,
-		&grading.CategoryID,
-		&grading.SizeID,
-		&grading.PieceID,
-		&grading.Weight,
-		&grading.WorkerID,
-		&grading.CreatedAt,
-		&grading.UpdatedAt,
-	)
-
+	err := createPlausibly(c, stores, models.EntityManualGrading, grading.StockID, func(tx *store.Stores) (string, error) {
+		return grading.ID, tx.ManualGradings.Create(c.Request.Context(), &grading)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, grading)
 }
 
-// UpdateManualGrading - Update existing manual grading record
-func UpdateManualGrading(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var grading models.ManualGrading
-	if err := c.ShouldBindJSON(&grading); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+// CreateManualGradings - Create a batch of manual grading records, all or
+// nothing unless ?mode=best_effort
+func CreateManualGradings(c *gin.Context, stores *store.Stores) {
+	createBatch(c, func(grading *models.ManualGrading) error {
+		return checkManualGrading(c, stores, grading)
+	}, createManyPlausibly(c, stores, models.EntityManualGrading, func(tx *store.Stores, ctx context.Context, records []*models.ManualGrading, atomic bool) ([]error, error) {
+		return tx.ManualGradings.CreateMany(ctx, records, atomic)
+	}, func(grading *models.ManualGrading) (string, string) {
+		return grading.StockID, grading.ID
+	}))
+}
 
-	result, err := db.Exec(`
-		UPDATE manual_grading
-		SET grader_machine_outputs_id = ?,
-			stock_id = ?,
-			category_id = ?,
-			size_id = ?,
-			piece_id = ?,
-			weight = ?,
-			worker_id = ?,
-			updated_at = NOW()
-		WHERE id = ?`,
-		grading.GraderMachineOutputsID,
-		grading.StockID,
-		grading.CategoryID,
-		grading.SizeID,
-		grading.PieceID,
-		grading.Weight,
-		grading.WorkerID,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// checkManualGrading applies the rules a manual grading record must meet to
+// be stored, as a new record or as the replacement of a corrected one
+func checkManualGrading(c *gin.Context, stores *store.Stores, grading *models.ManualGrading) error {
+	ctx := c.Request.Context()
+	var problems models.Violations
+	problems.Include(grading.Validate())
+	if grading.GraderMachineOutputsID != "" {
+		if _, _, err := checkExists(ctx, &problems, "grader_machine_outputs_id", grading.GraderMachineOutputsID, stores.GraderMachineOutputs.Get, "Grader machine output"); err != nil {
+			return err
+		}
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if grading.CategoryID.Valid {
+		if _, _, err := checkExists(ctx, &problems, "category_id", grading.CategoryID.Int64, stores.GradingCategories.Get, "Grading category"); err != nil {
+			return err
+		}
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := checkSize(ctx, stores.SizeVariations, &problems, "size_id", grading.SizeID); err != nil {
+		return err
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
-}
-
-// DeleteManualGrading - Delete manual grading record
-func DeleteManualGrading(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM manual_grading WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if grading.PieceID.Valid {
+		if err := checkPiece(ctx, stores.Pieces, &problems, "piece_id", grading.PieceID.Int64); err != nil {
+			return err
+		}
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if err := checkWorker(ctx, stores.Workforce, &problems, "worker_id", grading.WorkerID); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := problems.Err(); err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	return checkStockStatus(ctx, stores.Stocks, grading.StockID, models.StatusManualGrading)
+}
+
+// CorrectManualGrading - Reverse a manual grading record, posting its replacement
+// unless the record is only being voided
+func CorrectManualGrading(c *gin.Context, stores *store.Stores) {
+	correctRecord(c, c.Param("id"), stores.ManualGradings.Get, func(grading *models.ManualGrading) error {
+		return checkManualGrading(c, stores, grading)
+	}, correctPlausibly(c, stores, models.EntityManualGrading, func(tx *store.Stores, ctx context.Context, reversal, replacement *models.ManualGrading) error {
+		return tx.ManualGradings.Correct(ctx, reversal, replacement)
+	}, func(grading *models.ManualGrading) (string, string) {
+		return grading.StockID, grading.ID
+	}))
+}
+
+// DeleteManualGrading - Move a manual grading record entered by mistake to the trash
+func DeleteManualGrading(c *gin.Context, gradings store.ManualGradingStore) {
+	id := c.Param("id")
+	deleteEntry(c, id, gradings.Get, gradings.Delete)
 }
 
 // GetManualGradingsByStock - Get manual grading records for a specific stock ID
-func GetManualGradingsByStock(c *gin.Context, db *sql.DB) {
+func GetManualGradingsByStock(c *gin.Context, gradings store.ManualGradingStore) {
 	stockID := c.Param("stockId")
 
-	rows, err := db.Query(`
-		SELECT id, grader_machine_outputs_id, stock_id, category_id, 
-			size_id, piece_id, weight, worker_id, created_at, updated_at
-		FROM manual_grading 
-		WHERE stock_id = ?
-		ORDER BY created_at DESC`, stockID)
+	opts, err := parseListOptions(c, store.ManualGradingList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var gradings []models.ManualGrading
-	for rows.Next() {
-		var grading models.ManualGrading
-		if err := rows.Scan(
-			&grading.ID,
-			&grading.GraderMachineOutputsID,
-			&grading.StockID,
-			&grading.CategoryID,
-			&grading.SizeID,
-			&grading.PieceID,
-			&grading.Weight,
-			&grading.WorkerID,
-			&grading.CreatedAt,
-			&grading.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		gradings = append(gradings, grading)
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: stockID})
+
+	page, err := gradings.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, gradings)
+	respondWithPage(c, store.ManualGradingList, page)
 }
 
 // SetupManualGradingRoutes sets up all the routes for manual grading
-func SetupManualGradingRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/manual-grading", func(c *gin.Context) { GetAllManualGradings(c, db) })
-	router.GET("/manual-grading/:id", func(c *gin.Context) { GetManualGrading(c, db) })
-	router.POST("/manual-grading", func(c *gin.Context) { CreateManualGrading(c, db) })
-	router.PUT("/manual-grading/:id", func(c *gin.Context) { UpdateManualGrading(c, db) })
-	router.DELETE("/manual-grading/:id", func(c *gin.Context) { DeleteManualGrading(c, db) })
-	router.GET("/manual-grading/stock/:stockId", func(c *gin.Context) { GetManualGradingsByStock(c, db) })
-} 
\ No newline at end of file
+func SetupManualGradingRoutes(router *gin.Engine, stores *store.Stores) {
+	gradings := stores.ManualGradings
+	router.GET("/manual-grading", func(c *gin.Context) { GetAllManualGradings(c, gradings) })
+	router.GET("/manual-grading/:id", func(c *gin.Context) { GetManualGrading(c, gradings) })
+	router.POST("/manual-grading", func(c *gin.Context) { CreateManualGrading(c, stores) })
+	router.POST("/manual-grading/bulk", func(c *gin.Context) { CreateManualGradings(c, stores) })
+	router.POST("/manual-grading/:id/corrections", func(c *gin.Context) { CorrectManualGrading(c, stores) })
+	router.PATCH("/manual-grading/:id", func(c *gin.Context) { CorrectManualGrading(c, stores) })
+	router.DELETE("/manual-grading/:id", func(c *gin.Context) { DeleteManualGrading(c, gradings) })
+	router.GET("/manual-grading/stock/:stockId", func(c *gin.Context) { GetManualGradingsByStock(c, gradings) })
+}
@@ -1,234 +1,166 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllManualGradingInputs - Get all machine grading input records
-func GetAllManualGradingInputs(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, stock_id, worker_id, size_variations_id, weight, 
-               created_at, updated_at 
-        FROM machine_grading_inputs`)
+func GetAllManualGradingInputs(c *gin.Context, inputs store.ManualGradingInputStore) {
+	opts, err := parseListOptions(c, store.ManualGradingInputList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var inputs []models.ManualGradingInput
-	for rows.Next() {
-		var input models.ManualGradingInput
-		if err := rows.Scan(
-			&input.ID,
-			&input.StockID,
-			&input.WorkerID,
-			&input.SizeVariationsID,
-			&input.Weight,
-			&input.CreatedAt,
-			&input.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		inputs = append(inputs, input)
+
+	page, err := inputs.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, inputs)
+	respondWithPage(c, store.ManualGradingInputList, page)
 }
 
 // GetManualGradingInput - Get single machine grading input record
-func GetManualGradingInput(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
+func GetManualGradingInput(c *gin.Context, inputs store.ManualGradingInputStore) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
 
-	var input models.ManualGradingInput
-	err := db.QueryRow(`
-        SELECT id, stock_id, worker_id, size_variations_id, weight, 
-               created_at, updated_at 
-        FROM machine_grading_inputs WHERE id = ?`, id).Scan(
-		&input.ID,
-		&input.StockID,
-		&input.WorkerID,
-		&input.SizeVariationsID,
-		&input.Weight,
-		&input.CreatedAt,
-		&input.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	input, err := inputs.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, input)
+	respondWithRecord(c, input)
 }
 
 // CreateManualGradingInput - Create new machine grading input record
-func CreateManualGradingInput(c *gin.Context, db *sql.DB) {
+func CreateManualGradingInput(c *gin.Context, stores *store.Stores) {
 	var input models.ManualGradingInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-
-	// Insert the record
-	_, err := db.Exec(`
-        INSERT INTO machine_grading_inputs (
-            id, stock_id, worker_id, size_variations_id, weight,
-            created_at, updated_at
-        )
-        VALUES (?, ?, ?, ?, ?, NOW(), NOW())`,
-		input.ID,
-		input.StockID,
-		input.WorkerID,
-		input.SizeVariationsID,
-		input.Weight,
-	)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := checkManualGradingInput(c, stores, &input); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	// Fetch the created record to get timestamps
-	err = db.QueryRow(`
-        SELECT id, stock_id, worker_id, size_variations_id, weight,
-               created_at, updated_at 
-        FROM machine_grading_inputs WHERE id = ?`, input.ID).Scan(
-		&input.ID,
-		&input.StockID,
-		&input.WorkerID,
-		&input.SizeVariationsID,
-		&input.Weight,
-		&input.CreatedAt,
-		&input.UpdatedAt,
-	)
-
+	err := createPlausibly(c, stores, models.EntityManualGradingInput, input.StockID, func(tx *store.Stores) (string, error) {
+		err := tx.ManualGradingInputs.Create(c.Request.Context(), &input)
+		return strconv.Itoa(input.ID), err
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, input)
 }
 
-// UpdateManualGradingInput - Update existing machine grading input record
-func UpdateManualGradingInput(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var input models.ManualGradingInput
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
+// CreateManualGradingInputs - Create a batch of machine grading input
+// records, all or nothing unless ?mode=best_effort
+func CreateManualGradingInputs(c *gin.Context, stores *store.Stores) {
+	createBatch(c, func(input *models.ManualGradingInput) error {
+		return checkManualGradingInput(c, stores, input)
+	}, createManyPlausibly(c, stores, models.EntityManualGradingInput, func(tx *store.Stores, ctx context.Context, records []*models.ManualGradingInput, atomic bool) ([]error, error) {
+		return tx.ManualGradingInputs.CreateMany(ctx, records, atomic)
+	}, func(input *models.ManualGradingInput) (string, string) {
+		return input.StockID, strconv.Itoa(input.ID)
+	}))
+}
 
-	result, err := db.Exec(`
-        UPDATE machine_grading_inputs
-        SET stock_id = ?,
-            worker_id = ?,
-            size_variations_id = ?,
-            weight = ?,
-            updated_at = NOW()
-        WHERE id = ?`,
-		input.StockID,
-		input.WorkerID,
-		input.SizeVariationsID,
-		input.Weight,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// checkManualGradingInput applies the rules a machine grading input record
+// must meet to be stored, as a new record or as the replacement of a
+// corrected one
+func checkManualGradingInput(c *gin.Context, stores *store.Stores, input *models.ManualGradingInput) error {
+	ctx := c.Request.Context()
+	var problems models.Violations
+	problems.Include(input.Validate())
+	if input.SizeVariationsID.Valid {
+		if err := checkSize(ctx, stores.SizeVariations, &problems, "size_variations_id", input.SizeVariationsID.Int64); err != nil {
+			return err
+		}
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if err := checkWorker(ctx, stores.Workforce, &problems, "worker_id", input.WorkerID); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := problems.Err(); err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
+	return checkStockStatus(ctx, stores.Stocks, input.StockID, models.StatusManualGrading)
 }
 
-// DeleteManualGradingInput - Delete machine grading input record
-func DeleteManualGradingInput(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM machine_grading_inputs WHERE id = ?", id)
+// CorrectManualGradingInput - Reverse a machine grading input record, posting its replacement
+// unless the record is only being voided
+func CorrectManualGradingInput(c *gin.Context, stores *store.Stores) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
+	correctRecord(c, id, stores.ManualGradingInputs.Get, func(input *models.ManualGradingInput) error {
+		return checkManualGradingInput(c, stores, input)
+	}, correctPlausibly(c, stores, models.EntityManualGradingInput, func(tx *store.Stores, ctx context.Context, reversal, replacement *models.ManualGradingInput) error {
+		return tx.ManualGradingInputs.Correct(ctx, reversal, replacement)
+	}, func(input *models.ManualGradingInput) (string, string) {
+		return input.StockID, strconv.Itoa(input.ID)
+	}))
+}
 
-	rowsAffected, err := result.RowsAffected()
+// DeleteManualGradingInput - Move a manual grading input record entered by mistake to the trash
+func DeleteManualGradingInput(c *gin.Context, inputs store.ManualGradingInputStore) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	deleteEntry(c, id, inputs.Get, inputs.Delete)
 }
 
 // GetManualGradingInputsByStock - Get machine grading input records for a specific stock ID
-func GetManualGradingInputsByStock(c *gin.Context, db *sql.DB) {
+func GetManualGradingInputsByStock(c *gin.Context, inputs store.ManualGradingInputStore) {
 	stockID := c.Param("stockId")
 
-	rows, err := db.Query(`
-        SELECT id, stock_id, worker_id, size_variations_id, weight, 
-               created_at, updated_at 
-        FROM machine_grading_inputs 
-        WHERE stock_id = ?
-        ORDER BY created_at DESC`, stockID)
+	opts, err := parseListOptions(c, store.ManualGradingInputList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var inputs []models.ManualGradingInput
-	for rows.Next() {
-		var input models.ManualGradingInput
-		if err := rows.Scan(
-			&input.ID,
-			&input.StockID,
-			&input.WorkerID,
-			&input.SizeVariationsID,
-			&input.Weight,
-			&input.CreatedAt,
-			&input.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		inputs = append(inputs, input)
-	}
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: stockID})
 
-	if len(inputs) == 0 {
-		c.JSON(http.StatusOK, []models.ManualGradingInput{}) // Return empty array instead of null
+	page, err := inputs.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, inputs)
+	respondWithPage(c, store.ManualGradingInputList, page)
 }
 
 // SetupManualGradingInputRoutes - Setup all routes for machine grading inputs
-func SetupManualGradingInputRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/manual-grading-inputs", func(c *gin.Context) { GetAllManualGradingInputs(c, db) })
-	router.GET("/manual-grading-inputs/:id", func(c *gin.Context) { GetManualGradingInput(c, db) })
-	router.POST("/manual-grading-inputs", func(c *gin.Context) { CreateManualGradingInput(c, db) })
-	router.PUT("/manual-grading-inputs/:id", func(c *gin.Context) { UpdateManualGradingInput(c, db) })
-	router.DELETE("/manual-grading-inputs/:id", func(c *gin.Context) { DeleteManualGradingInput(c, db) })
-	router.GET("/manual-grading-inputs/stock/:stockId", func(c *gin.Context) { GetManualGradingInputsByStock(c, db) })
-} 
\ No newline at end of file
+func SetupManualGradingInputRoutes(router *gin.Engine, stores *store.Stores) {
+	inputs := stores.ManualGradingInputs
+	router.GET("/manual-grading-inputs", func(c *gin.Context) { GetAllManualGradingInputs(c, inputs) })
+	router.GET("/manual-grading-inputs/:id", func(c *gin.Context) { GetManualGradingInput(c, inputs) })
+	router.POST("/manual-grading-inputs", func(c *gin.Context) { CreateManualGradingInput(c, stores) })
+	router.POST("/manual-grading-inputs/bulk", func(c *gin.Context) { CreateManualGradingInputs(c, stores) })
+	router.POST("/manual-grading-inputs/:id/corrections", func(c *gin.Context) { CorrectManualGradingInput(c, stores) })
+	router.PATCH("/manual-grading-inputs/:id", func(c *gin.Context) { CorrectManualGradingInput(c, stores) })
+	router.DELETE("/manual-grading-inputs/:id", func(c *gin.Context) { DeleteManualGradingInput(c, inputs) })
+	router.GET("/manual-grading-inputs/stock/:stockId", func(c *gin.Context) { GetManualGradingInputsByStock(c, inputs) })
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockOutturn - Get the promised and realised outturn of a stock lot
+func GetStockOutturn(c *gin.Context, stocks store.StockStore, reportStore store.ReportStore) {
+	id := c.Param("id")
+
+	stock, err := stocks.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	grades, err := reportStore.GradeWeights(c.Request.Context(), []string{id})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports.Outturn(stock, reports.KernelWeights(grades)[id]))
+}
+
+// GetLotOutturns - Get the outturn of every stock lot, paged like /stocks
+func GetLotOutturns(c *gin.Context, stocks store.StockStore, reportStore store.ReportStore) {
+	opts, err := parseListOptions(c, store.StockList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	page, err := stocks.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	lots, err := lotOutturns(c, reportStore, page.Items)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	var next *string
+	if page.Next != nil {
+		cursor := store.StockList.EncodeCursor(*page.Next)
+		next = &cursor
+	}
+	respondWithItems(c, lots, next, page.Total)
+}
+
+// GetSellerOutturns - Get the realised outturn of each seller's graded lots
+func GetSellerOutturns(c *gin.Context, stocks store.StockStore, reportStore store.ReportStore) {
+	respondWithOutturnGroups(c, stocks, reportStore, func(lot models.LotOutturn) string { return lot.SellerName })
+}
+
+// GetOriginOutturns - Get the realised outturn of graded lots by origin country
+func GetOriginOutturns(c *gin.Context, stocks store.StockStore, reportStore store.ReportStore) {
+	respondWithOutturnGroups(c, stocks, reportStore, func(lot models.LotOutturn) string { return lot.OriginCountry })
+}
+
+// respondWithOutturnGroups totals every lot matching the stock filters in
+// the query by key
+func respondWithOutturnGroups(c *gin.Context, stocks store.StockStore, reportStore store.ReportStore, key func(models.LotOutturn) string) {
+	opts, err := parseListOptions(c, store.StockList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	// Groups cover every matching lot, so paging does not apply
+	opts.Limit, opts.After = 0, nil
+
+	page, err := stocks.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	lots, err := lotOutturns(c, reportStore, page.Items)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": reports.GroupOutturns(lots, key)})
+}
+
+func lotOutturns(c *gin.Context, reportStore store.ReportStore, stocks []models.Stock) ([]models.LotOutturn, error) {
+	ids := make([]string, len(stocks))
+	for i, stock := range stocks {
+		ids[i] = stock.StockID
+	}
+	grades, err := reportStore.GradeWeights(c.Request.Context(), ids)
+	if err != nil {
+		return nil, err
+	}
+	kernels := reports.KernelWeights(grades)
+
+	lots := make([]models.LotOutturn, len(stocks))
+	for i, stock := range stocks {
+		lots[i] = reports.Outturn(stock, kernels[stock.StockID])
+	}
+	return lots, nil
+}
+
+// SetupOutturnRoutes - Setup all routes for outturn reports
+func SetupOutturnRoutes(router *gin.Engine, stores *store.Stores) {
+	stocks := stores.Stocks
+	reportStore := stores.Reports
+	router.GET("/outturn/lots", func(c *gin.Context) { GetLotOutturns(c, stocks, reportStore) })
+	router.GET("/outturn/sellers", func(c *gin.Context) { GetSellerOutturns(c, stocks, reportStore) })
+	router.GET("/outturn/origins", func(c *gin.Context) { GetOriginOutturns(c, stocks, reportStore) })
+}
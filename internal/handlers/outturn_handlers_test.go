@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+func TestStockOutturn(t *testing.T) {
+	router, stores := newTestRouter(reports.DefaultPlausibility())
+	SetupOutturnRoutes(router, stores)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"L1","seller_id":1,"weight":800,"promised_outturn":48}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"L2","seller_id":1,"weight":800}`), http.StatusCreated)
+
+	// Grade the kernel of L1 straight into the store; husk isn't kernel
+	ctx := context.Background()
+	for i, code := range []string{"W320", reports.HuskCategory} {
+		category := models.GradingCategory{CategoryCode: code}
+		if err := stores.GradingCategories.Create(ctx, &category); err != nil {
+			t.Fatal(err)
+		}
+		grading := models.ManualGrading{
+			ID:         code,
+			StockID:    "L1",
+			CategoryID: sql.NullInt64{Int64: category.CategoryID, Valid: true},
+			Weight:     int64(200 + 100*i),
+		}
+		if err := stores.ManualGradings.Create(ctx, &grading); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := send(router, http.MethodGet, "/stocks/L1/outturn", "")
+	expect(t, w, http.StatusOK)
+	lot := decode[models.LotOutturn](t, w)
+	// 200 kg of kernel from 800 kg raw is 20 kg, or 44.09 lbs, per 80 kg bag
+	if lot.KernelWeight != 200 || lot.GradingRecords != 1 || lot.ActualOutturn == nil || *lot.ActualOutturn != 44.09 ||
+		lot.Variance == nil || *lot.Variance != -3.91 {
+		t.Errorf("outturn = %+v, want 44.09 lbs, 3.91 short of the promise", lot)
+	}
+
+	w = send(router, http.MethodGet, "/stocks/L2/outturn", "")
+	expect(t, w, http.StatusOK)
+	if lot := decode[models.LotOutturn](t, w); lot.ActualOutturn != nil || lot.Variance != nil {
+		t.Errorf("ungraded outturn = %+v, want no actual outturn yet", lot)
+	}
+
+	w = send(router, http.MethodGet, "/outturn/lots", "")
+	expect(t, w, http.StatusOK)
+	if body := decode[struct {
+		Data  []models.LotOutturn
+		Total int
+	}](t, w); body.Total != 2 || len(body.Data) != 2 {
+		t.Errorf("lots = %+v, want both lots", body)
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"healing_photons/internal/store"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// jsonFields maps the JSON names of the fields of struct type t to whether
+// each may be null, which is the case for pointers and sql.Null* columns
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded, nullable := range jsonFields(field.Type) {
+				fields[embedded] = nullable
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type.Kind() == reflect.Pointer || reflect.PointerTo(field.Type).Implements(scannerType)
+	}
+	return fields
+}
+
+// decodeJSON decodes data keeping numbers as written, so IDs and weights
+// survive a round trip through a map unchanged
+func decodeJSON(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// readMergePatch reads a JSON merge patch (RFC 7396) for a T from the
+// request body, writing a 400 response when it isn't an object, names a
+// field T doesn't have or sets a field that can't be null to null
+func readMergePatch[T any](c *gin.Context) (map[string]any, bool) {
+	body, err := c.GetRawData()
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return nil, false
+	}
+	var patch map[string]any
+	if err := decodeJSON(body, &patch); err != nil || patch == nil {
+		respondWithStatus(c, http.StatusBadRequest, "Body must be a JSON object of the fields to change")
+		return nil, false
+	}
+
+	fields := jsonFields(reflect.TypeFor[T]())
+	for name, value := range patch {
+		nullable, ok := fields[name]
+		if !ok {
+			respondWithStatus(c, http.StatusBadRequest, "Unknown field %q", name)
+			return nil, false
+		}
+		if value == nil && !nullable {
+			respondWithStatus(c, http.StatusBadRequest, "%s cannot be null", name)
+			return nil, false
+		}
+	}
+	return patch, true
+}
+
+// mergePatch applies patch to target as RFC 7396 describes: objects are
+// merged member by member, null removes a member and anything else
+// replaces it
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// applyMergePatch returns record with patch applied to its JSON form.
+// Removed members decode as zero values, so a nullable field set to null
+// ends up NULL
+func applyMergePatch[T any](record T, patch map[string]any) (T, error) {
+	var patched T
+	body, err := json.Marshal(record)
+	if err != nil {
+		return patched, err
+	}
+	var document any
+	if err := decodeJSON(body, &document); err != nil {
+		return patched, err
+	}
+	if body, err = json.Marshal(mergePatch(document, patch)); err != nil {
+		return patched, err
+	}
+	err = json.Unmarshal(body, &patched)
+	return patched, err
+}
+
+// bindUpdate reads the new state of the record id into record. A PUT body
+// is the whole record; a PATCH body is a JSON merge patch that only changes
+// the fields it names, applied to the record as it is now once the
+// request's If-Match shows that is the version the client read. Either way
+// the write itself goes through writeUnchanged, which checks If-Match again
+// against the locked row
+func bindUpdate[K any, T any](c *gin.Context, id K, get func(context.Context, K) (T, error), notFound string, record *T) bool {
+	if c.Request.Method != http.MethodPatch {
+		if err := c.ShouldBindJSON(record); err != nil {
+			respondWithError(c, invalid(err))
+			return false
+		}
+		return true
+	}
+
+	patch, ok := readMergePatch[T](c)
+	if !ok {
+		return false
+	}
+	current, err := get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "%s", notFound)
+		return false
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	if !requireMatch(c, current) {
+		return false
+	}
+
+	patched, err := applyMergePatch(current, patch)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return false
+	}
+	if err := binding.Validator.ValidateStruct(patched); err != nil {
+		respondWithError(c, invalid(err))
+		return false
+	}
+	*record = patched
+	return true
+}
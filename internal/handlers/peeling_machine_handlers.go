@@ -1,238 +1,180 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllPeelingMachineData - Get all peeling machine records
-func GetAllPeelingMachineData(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, humidifier_id, stock_id, weight_type_id, weight, 
-               created_at, updated_at 
-        FROM peeling_machine`)
+func GetAllPeelingMachineData(c *gin.Context, machines store.PeelingMachineStore) {
+	opts, err := parseListOptions(c, store.PeelingMachineList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var machines []models.PeelingMachine
-	for rows.Next() {
-		var machine models.PeelingMachine
-		if err := rows.Scan(
-			&machine.ID,
-			&machine.HumidifierID,
-			&machine.StockID,
-			&machine.WeightTypeID,
-			&machine.Weight,
-			&machine.CreatedAt,
-			&machine.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		machines = append(machines, machine)
-	}
-	c.JSON(http.StatusOK, machines)
-}
 
-// GetPeelingMachine - Get single peeling machine record
-func GetPeelingMachine(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	var machine models.PeelingMachine
-	rows, err := db.Query(`
-        SELECT id, humidifier_id, stock_id, weight_type_id, weight, 
-               created_at, updated_at 
-        FROM peeling_machine WHERE stock_id = ?`, id)
+	page, err := machines.List(c.Request.Context(), opts)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	defer rows.Close()
-
-	var machines []models.PeelingMachine
-	for rows.Next() {
-		if err := rows.Scan(
-			&machine.ID,
-			&machine.HumidifierID,
-			&machine.StockID,
-			&machine.WeightTypeID,
-			&machine.Weight,
-			&machine.CreatedAt,
-			&machine.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		machines = append(machines, machine)
-	}
+	respondWithPage(c, store.PeelingMachineList, page)
+}
 
-	if len(machines) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
-	}
+// GetPeelingMachine - Get single peeling machine record
+func GetPeelingMachine(c *gin.Context, machines store.PeelingMachineStore) {
+	id := c.Param("id")
 
-	machine = machines[0]
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	machine, err := machines.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, machine)
+	respondWithRecord(c, machine)
 }
 
 // CreatePeelingMachine - Create new peeling machine record
-func CreatePeelingMachine(c *gin.Context, db *sql.DB) {
+func CreatePeelingMachine(c *gin.Context, stores *store.Stores) {
 	var machine models.PeelingMachine
 	if err := c.ShouldBindJSON(&machine); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	_, err := db.Exec(`
-        INSERT INTO peeling_machine (
-            id, humidifier_id, stock_id, weight_type_id, weight, created_at, updated_at
-        )
-        VALUES (?, ?, ?, ?, ?, NOW(), NOW())`,
-		machine.ID,
-		machine.HumidifierID,
-		machine.StockID,
-		machine.WeightTypeID,
-		machine.Weight,
-	)
-
-	// Set timestamps manually since we can't get them from the insert
-	machine.CreatedAt = time.Now()
-	machine.UpdatedAt = time.Now()
+	if err := preparePeelingMachine(c, stores, &machine); err != nil {
+		respondWithError(c, err)
+		return
+	}
 
+	err := createPlausibly(c, stores, models.EntityPeelingMachine, *machine.StockID, func(tx *store.Stores) (string, error) {
+		return machine.ID, tx.PeelingMachines.Create(c.Request.Context(), &machine)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, machine)
 }
 
-// UpdatePeelingMachine - Update existing peeling machine record
-func UpdatePeelingMachine(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var machine models.PeelingMachine
-	if err := c.ShouldBindJSON(&machine); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	result, err := db.Exec(`
-        UPDATE peeling_machine 
-        SET humidifier_id = ?,
-            stock_id = ?,
-            weight_type_id = ?,
-            weight = ?,
-            updated_at = NOW()
-        WHERE id = ?`,
-		machine.HumidifierID,
-		machine.StockID,
-		machine.WeightTypeID,
-		machine.Weight,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
+// CreatePeelingMachines - Create a batch of peeling machine records, all or
+// nothing unless ?mode=best_effort
+func CreatePeelingMachines(c *gin.Context, stores *store.Stores) {
+	createBatch(c, func(machine *models.PeelingMachine) error {
+		return preparePeelingMachine(c, stores, machine)
+	}, createManyPlausibly(c, stores, models.EntityPeelingMachine, func(tx *store.Stores, ctx context.Context, records []*models.PeelingMachine, atomic bool) ([]error, error) {
+		return tx.PeelingMachines.CreateMany(ctx, records, atomic)
+	}, func(machine *models.PeelingMachine) (string, string) {
+		return *machine.StockID, machine.ID
+	}))
+}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// preparePeelingMachine checks a peeling machine record about to be created
+// and fills in the fields the server sets
+func preparePeelingMachine(c *gin.Context, stores *store.Stores, machine *models.PeelingMachine) error {
+	if err := checkPeelingMachine(c, stores, machine); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := assignID(c, stores.Sequences, models.EntityPeelingMachine, stageIDFields(c, *machine.StockID), &machine.ID); err != nil {
+		return err
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Record updated successfully"})
+	machine.DeviceID = recordingDevice(c)
+	return nil
 }
 
-// DeletePeelingMachine - Delete peeling machine record
-func DeletePeelingMachine(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM peeling_machine WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// checkPeelingMachine applies the rules a peeling machine record must meet
+// to be stored, as a new record or as the replacement of a corrected one
+func checkPeelingMachine(c *gin.Context, stores *store.Stores, machine *models.PeelingMachine) error {
+	ctx := c.Request.Context()
+	var problems models.Violations
+	problems.Include(machine.Validate())
+	if machine.HumidifierID != "" {
+		humidifier, found, err := checkExists(ctx, &problems, "humidifier_id", machine.HumidifierID, stores.Humidifiers.Get, "Humidifier record")
+		if err != nil {
+			return err
+		}
+		// Records posted without a stock belong to the humidified batch's stock
+		if found && machine.StockID == nil {
+			machine.StockID = &humidifier.StockID
+		}
+		if found {
+			checkSameStock(&problems, "humidifier_id", *machine.StockID, humidifier.StockID, "Humidifier record", humidifier.ID)
+			err := checkCurrent(ctx, &problems, "humidifier_id", humidifier.ID, humidifier.EntryType, stores.Humidifiers.List, func(h models.Humidifier) (models.EntryType, string) {
+				return h.EntryType, h.ID
+			}, "Humidifier record")
+			if err != nil {
+				return err
+			}
+		}
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+	if err := checkWeightType(ctx, stores.WeightTypes, &problems, machine.WeightTypeID); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
-		return
+	if err := problems.Err(); err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	if err := checkStockID(machine.StockID); err != nil {
+		return err
+	}
+	return checkStockStatus(ctx, stores.Stocks, *machine.StockID, models.StatusPeeling)
+}
+
+// CorrectPeelingMachine - Reverse a peeling machine record, posting its replacement
+// unless the record is only being voided
+func CorrectPeelingMachine(c *gin.Context, stores *store.Stores) {
+	correctRecord(c, c.Param("id"), stores.PeelingMachines.Get, func(machine *models.PeelingMachine) error {
+		return checkPeelingMachine(c, stores, machine)
+	}, correctPlausibly(c, stores, models.EntityPeelingMachine, func(tx *store.Stores, ctx context.Context, reversal, replacement *models.PeelingMachine) error {
+		return tx.PeelingMachines.Correct(ctx, reversal, replacement)
+	}, func(machine *models.PeelingMachine) (string, string) {
+		return *machine.StockID, machine.ID
+	}))
+}
+
+// DeletePeelingMachine - Move a peeling machine record entered by mistake to the trash
+func DeletePeelingMachine(c *gin.Context, machines store.PeelingMachineStore) {
+	id := c.Param("id")
+	deleteEntry(c, id, machines.Get, machines.Delete)
 }
 
 // GetPeelingMachinesByStockID - Get all peeling machine records for a specific stock ID
-func GetPeelingMachinesByStockID(c *gin.Context, db *sql.DB) {
+func GetPeelingMachinesByStockID(c *gin.Context, machines store.PeelingMachineStore) {
 	stockID := c.Param("stockId")
 
-	rows, err := db.Query(`
-        SELECT id, humidifier_id, stock_id, weight_type_id, weight, 
-               created_at, updated_at 
-        FROM peeling_machine 
-        WHERE stock_id = ?
-        ORDER BY created_at DESC`, stockID)
+	opts, err := parseListOptions(c, store.PeelingMachineList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var machines []models.PeelingMachine
-	for rows.Next() {
-		var machine models.PeelingMachine
-		if err := rows.Scan(
-			&machine.ID,
-			&machine.HumidifierID,
-			&machine.StockID,
-			&machine.WeightTypeID,
-			&machine.Weight,
-			&machine.CreatedAt,
-			&machine.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		machines = append(machines, machine)
-	}
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: stockID})
 
-	if len(machines) == 0 {
-		c.JSON(http.StatusOK, []models.PeelingMachine{}) // Return empty array instead of null
+	page, err := machines.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, machines)
+	respondWithPage(c, store.PeelingMachineList, page)
 }
 
 // SetupPeelingMachineRoutes - Setup all routes for peeling machine
-func SetupPeelingMachineRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/peeling-machines", func(c *gin.Context) { GetAllPeelingMachineData(c, db) })
-	router.GET("/peeling-machines/:id", func(c *gin.Context) { GetPeelingMachine(c, db) })
-	router.POST("/peeling-machines", func(c *gin.Context) { CreatePeelingMachine(c, db) })
-	router.PUT("/peeling-machines/:id", func(c *gin.Context) { UpdatePeelingMachine(c, db) })
-	router.DELETE("/peeling-machines/:id", func(c *gin.Context) { DeletePeelingMachine(c, db) })
-	router.GET("/peeling-machines/stock/:stockId", func(c *gin.Context) { GetPeelingMachinesByStockID(c, db) })
+func SetupPeelingMachineRoutes(router *gin.Engine, stores *store.Stores) {
+	machines := stores.PeelingMachines
+	router.GET("/peeling-machines", func(c *gin.Context) { GetAllPeelingMachineData(c, machines) })
+	router.GET("/peeling-machines/:id", func(c *gin.Context) { GetPeelingMachine(c, machines) })
+	router.POST("/peeling-machines", func(c *gin.Context) { CreatePeelingMachine(c, stores) })
+	router.POST("/peeling-machines/bulk", func(c *gin.Context) { CreatePeelingMachines(c, stores) })
+	router.POST("/peeling-machines/:id/corrections", func(c *gin.Context) { CorrectPeelingMachine(c, stores) })
+	router.PATCH("/peeling-machines/:id", func(c *gin.Context) { CorrectPeelingMachine(c, stores) })
+	router.DELETE("/peeling-machines/:id", func(c *gin.Context) { DeletePeelingMachine(c, machines) })
+	router.GET("/peeling-machines/stock/:stockId", func(c *gin.Context) { GetPeelingMachinesByStockID(c, machines) })
 }
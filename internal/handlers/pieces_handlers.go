@@ -1,125 +1,92 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"fmt"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllPieces - Get all pieces records
-func GetAllPieces(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT piece_id, piece_code, description
-        FROM pieces`)
+func GetAllPieces(c *gin.Context, pieces store.PieceStore) {
+	opts, err := parseListOptions(c, store.PieceList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var piecesList []models.Pieces
-	for rows.Next() {
-		var piece models.Pieces
-		if err := rows.Scan(
-			&piece.PieceID,
-			&piece.PieceCode,
-			&piece.Description,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		piecesList = append(piecesList, piece)
+
+	page, err := pieces.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, piecesList)
+	respondWithPage(c, store.PieceList, page)
 }
 
 // GetPiece - Get single piece record
-func GetPiece(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
+func GetPiece(c *gin.Context, pieces store.PieceStore) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
 
-	var piece models.Pieces
-	err := db.QueryRow(`
-        SELECT piece_id, piece_code, description
-        FROM pieces WHERE piece_id = ?`, id).Scan(
-		&piece.PieceID,
-		&piece.PieceCode,
-		&piece.Description,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	piece, err := pieces.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, piece)
+	respondWithRecord(c, piece)
 }
 
 // CreatePiece - Create new piece record
-func CreatePiece(c *gin.Context, db *sql.DB) {
+func CreatePiece(c *gin.Context, pieces store.PieceStore) {
 	var piece models.Pieces
 	if err := c.ShouldBindJSON(&piece); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	result, err := db.Exec(`
-        INSERT INTO pieces (
-            piece_id, piece_code, description
-        )
-        VALUES (?, ?, ?)`,
-		piece.PieceID,
-		piece.PieceCode,
-		piece.Description,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	lastID, err := result.LastInsertId()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := pieces.Create(c.Request.Context(), &piece); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	piece.PieceID = int(lastID)
 	c.JSON(http.StatusCreated, piece)
 }
 
 // UpdatePiece - Update existing piece record
-func UpdatePiece(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var piece models.Pieces
-	if err := c.ShouldBindJSON(&piece); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func UpdatePiece(c *gin.Context, pieces store.PieceStore, units store.UnitOfWork) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
-
-	result, err := db.Exec(`
-        UPDATE pieces
-        SET piece_code = ?,
-            description = ?
-        WHERE piece_id = ?`,
-		piece.PieceCode,
-		piece.Description,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var piece models.Pieces
+	if !bindUpdate(c, id, pieces.Get, "Record not found", &piece) {
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Pieces.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.Pieces.Update(c.Request.Context(), id, piece)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -127,33 +94,42 @@ func UpdatePiece(c *gin.Context, db *sql.DB) {
 }
 
 // DeletePiece - Delete piece record
-func DeletePiece(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM pieces WHERE piece_id = ?", id)
+func DeletePiece(c *gin.Context, pieces store.PieceStore, units store.UnitOfWork) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Pieces.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced(fmt.Sprintf("Piece %d", id), pieceReferrers(ctx, tx, strconv.Itoa(id))...); err != nil {
+			return err
+		}
+		return tx.Pieces.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
 }
 
 // SetupPiecesRoutes - Setup all routes for pieces
-func SetupPiecesRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/pieces", func(c *gin.Context) { GetAllPieces(c, db) })
-	router.GET("/pieces/:id", func(c *gin.Context) { GetPiece(c, db) })
-	router.POST("/pieces", func(c *gin.Context) { CreatePiece(c, db) })
-	router.PUT("/pieces/:id", func(c *gin.Context) { UpdatePiece(c, db) })
-	router.DELETE("/pieces/:id", func(c *gin.Context) { DeletePiece(c, db) })
-} 
\ No newline at end of file
+func SetupPiecesRoutes(router *gin.Engine, stores *store.Stores) {
+	pieces := stores.Pieces
+	units := stores.UnitOfWork
+	router.GET("/pieces", func(c *gin.Context) { GetAllPieces(c, pieces) })
+	router.GET("/pieces/:id", func(c *gin.Context) { GetPiece(c, pieces) })
+	router.POST("/pieces", func(c *gin.Context) { CreatePiece(c, pieces) })
+	router.PUT("/pieces/:id", func(c *gin.Context) { UpdatePiece(c, pieces, units) })
+	router.PATCH("/pieces/:id", func(c *gin.Context) { UpdatePiece(c, pieces, units) })
+	router.DELETE("/pieces/:id", func(c *gin.Context) { DeletePiece(c, pieces, units) })
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// codeImplausibleWeight is a stage record taking its stage's output for a
+// lot past what the stage before it allows
+const codeImplausibleWeight = "implausible_weight"
+
+const plausibilityKey = "plausibility"
+
+// UsePlausibility makes the plant's weight plausibility rules available to
+// the handlers that create stage records
+func UsePlausibility(rules reports.Plausibility) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(plausibilityKey, rules)
+		c.Next()
+	}
+}
+
+// plausibility returns the rules set by UsePlausibility, or the defaults
+func plausibility(c *gin.Context) reports.Plausibility {
+	if rules, ok := c.Get(plausibilityKey); ok {
+		return rules.(reports.Plausibility)
+	}
+	return reports.DefaultPlausibility()
+}
+
+// weightFields name the field holding each stage's output weight
+var weightFields = map[string]string{
+	models.EntityColorSort: "accepted_weight",
+}
+
+// checkPlausibility compares the lot's output at stage, with the record id
+// just written to it, against the stage before it. An implausible total is
+// a rejection when writes are blocked, which rolls back the unit of work
+// tx belongs to, and is recorded as an anomaly when they are flagged
+func checkPlausibility(c *gin.Context, tx *store.Stores, stage, stockID, id string) error {
+	rules := plausibility(c)
+	if rules.Mode == reports.PlausibilityOff {
+		return nil
+	}
+
+	ctx := c.Request.Context()
+	stock, err := tx.Stocks.Get(ctx, stockID)
+	if err != nil {
+		return err
+	}
+	totals, err := tx.Reports.StageTotals(ctx, stockID)
+	if err != nil {
+		return err
+	}
+	anomaly, found := rules.Check(stock, totals, stage)
+	if !found {
+		return nil
+	}
+	anomaly.RecordID = id
+
+	if rules.Mode == reports.PlausibilityBlock {
+		field := weightFields[stage]
+		if field == "" {
+			field = "weight"
+		}
+		return &rejection{status: http.StatusUnprocessableEntity, body: errorBody{
+			Code: codeImplausibleWeight,
+			Message: fmt.Sprintf("Stock %s would have %g recorded at %s, more than the %g its %s weight of %g allows",
+				stockID, anomaly.Weight, stageName(stage), anomaly.AllowedWeight, stageName(anomaly.UpstreamStage), anomaly.UpstreamWeight),
+			Field: field,
+			Details: gin.H{
+				"stage":           anomaly.Stage,
+				"weight":          anomaly.Weight,
+				"upstream_stage":  anomaly.UpstreamStage,
+				"upstream_weight": anomaly.UpstreamWeight,
+				"allowed_weight":  anomaly.AllowedWeight,
+			},
+		}}
+	}
+	return tx.Anomalies.Record(ctx, &anomaly)
+}
+
+// stageName spells out a stage for messages, e.g. "color sort"
+func stageName(stage string) string {
+	return strings.ReplaceAll(stage, "_", " ")
+}
+
+// createPlausibly creates a stage record for the lot stockID with create,
+// which returns the record's ID, and checks the lot's output in the same
+// unit of work, so a blocked write is never kept
+func createPlausibly(c *gin.Context, stores *store.Stores, stage, stockID string, create func(tx *store.Stores) (string, error)) error {
+	return stores.UnitOfWork.Do(c.Request.Context(), func(tx *store.Stores) error {
+		id, err := create(tx)
+		if err != nil {
+			return err
+		}
+		return checkPlausibility(c, tx, stage, stockID, id)
+	})
+}
+
+// createManyPlausibly returns the createMany of a bulk create, checking the
+// output of every lot the batch wrote to once its records are in. A lot
+// whose output is blocked fails the whole batch, whatever its mode; its
+// anomalies name the last of the batch's records for the lot
+func createManyPlausibly[T any](c *gin.Context, stores *store.Stores, stage string, createMany func(tx *store.Stores, ctx context.Context, records []*T, atomic bool) ([]error, error), written func(*T) (stockID, id string)) func(context.Context, []*T, bool) ([]error, error) {
+	return func(ctx context.Context, records []*T, atomic bool) (errs []error, err error) {
+		err = stores.UnitOfWork.Do(ctx, func(tx *store.Stores) error {
+			if errs, err = createMany(tx, ctx, records, atomic); err != nil {
+				return err
+			}
+			var stockIDs []string
+			last := map[string]string{}
+			for i, record := range records {
+				if errs[i] != nil {
+					continue
+				}
+				stockID, id := written(record)
+				if _, ok := last[stockID]; !ok {
+					stockIDs = append(stockIDs, stockID)
+				}
+				last[stockID] = id
+			}
+			for _, stockID := range stockIDs {
+				if err := checkPlausibility(c, tx, stage, stockID, last[stockID]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return errs, err
+	}
+}
+
+// correctPlausibly returns the correct of a correctRecord, storing the
+// reversal and replacement and checking the lot's output at stage in one
+// unit of work. Raising a record's weight through a correction is held to
+// the same limits as recording it that way in the first place
+func correctPlausibly[T any](c *gin.Context, stores *store.Stores, stage string, correct func(tx *store.Stores, ctx context.Context, reversal, replacement *T) error, written func(*T) (stockID, id string)) func(context.Context, *T, *T) error {
+	return func(ctx context.Context, reversal, replacement *T) error {
+		return stores.UnitOfWork.Do(ctx, func(tx *store.Stores) error {
+			if err := correct(tx, ctx, reversal, replacement); err != nil {
+				return err
+			}
+			entry := reversal
+			if replacement != nil {
+				entry = replacement
+			}
+			stockID, id := written(entry)
+			return checkPlausibility(c, tx, stage, stockID, id)
+		})
+	}
+}
+
+// GetStockAnomalies - Get the stage records flagged for weighing more than
+// the stage before them allows, for a stock lot
+func GetStockAnomalies(c *gin.Context, stocks store.StockStore, anomalies store.AnomalyStore) {
+	id := c.Param("id")
+
+	opts, err := parseListOptions(c, store.AnomalyList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: id})
+
+	if _, err := stocks.Get(c.Request.Context(), id); errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
+		return
+	} else if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	page, err := anomalies.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithPage(c, store.AnomalyList, page)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"net/http"
+	"testing"
+)
+
+// blocking refuses implausible writes, with the default allowances
+func blocking() reports.Plausibility {
+	rules := reports.DefaultPlausibility()
+	rules.Mode = reports.PlausibilityBlock
+	return rules
+}
+
+// anomalies lists the anomalies recorded against a lot
+func anomalies(t *testing.T, stores *store.Stores, stockID string) []models.Anomaly {
+	t.Helper()
+	page, err := stores.Anomalies.List(context.Background(), store.ListOptions{Sort: store.AnomalyList.DefaultSort, Filters: []store.Filter{{Field: "stock_id", Value: stockID}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return page.Items
+}
+
+// stageWeight returns the lot's humidifier total
+func stageWeight(t *testing.T, stores *store.Stores, stockID string) float64 {
+	t.Helper()
+	totals, err := stores.Reports.StageTotals(context.Background(), stockID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return totals.Humidifier.Weight
+}
+
+func TestImplausibleWeightIsFlagged(t *testing.T) {
+	router, stores := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+
+	// Humidifying may add 10% to the 1000 received
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":1100}`), http.StatusCreated)
+	if got := anomalies(t, stores, "L1"); len(got) != 0 {
+		t.Fatalf("anomalies = %+v, want none within the allowance", got)
+	}
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H2","stock_id":"L1","weight":50}`), http.StatusCreated)
+
+	got := anomalies(t, stores, "L1")
+	if len(got) != 1 || got[0].RecordID != "H2" || got[0].Stage != models.EntityHumidifier || got[0].UpstreamStage != models.StageReceived {
+		t.Fatalf("anomalies = %+v, want one for H2 against the received weight", got)
+	}
+	if got[0].Weight != 1150 || got[0].AllowedWeight != 1100 {
+		t.Errorf("anomaly weighs %v against %v allowed, want 1150 against 1100", got[0].Weight, got[0].AllowedWeight)
+	}
+}
+
+func TestImplausibleWeightIsBlocked(t *testing.T) {
+	router, stores := newTestRouter(blocking())
+	receiveStock(t, router, "L1", "1000")
+
+	w := send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":1200}`)
+	expect(t, w, http.StatusUnprocessableEntity)
+	if got := decode[errorResponse](t, w); got.Error.Code != codeImplausibleWeight || got.Error.Field != "weight" {
+		t.Errorf("error = %+v, want %s on weight", got.Error, codeImplausibleWeight)
+	}
+	expect(t, send(router, http.MethodGet, "/humidifiers/L1", ""), http.StatusNotFound)
+	if weight := stageWeight(t, stores, "L1"); weight != 0 {
+		t.Errorf("humidifier total = %v, want the blocked record rolled back", weight)
+	}
+	if got := anomalies(t, stores, "L1"); len(got) != 0 {
+		t.Errorf("anomalies = %+v, want none when blocking", got)
+	}
+}
+
+func TestImplausibleBatchIsBlocked(t *testing.T) {
+	router, stores := newTestRouter(blocking())
+	receiveStock(t, router, "L1", "1000")
+
+	// A blocked lot fails the whole batch, best effort or not
+	w := send(router, http.MethodPost, "/humidifiers/bulk?mode=best_effort", `[{"id":"H1","stock_id":"L1","weight":600},{"id":"H2","stock_id":"L1","weight":600}]`)
+	expect(t, w, http.StatusUnprocessableEntity)
+	if weight := stageWeight(t, stores, "L1"); weight != 0 {
+		t.Errorf("humidifier total = %v, want the batch rolled back", weight)
+	}
+}
+
+func TestImplausibleCorrectionIsBlocked(t *testing.T) {
+	router, stores := newTestRouter(blocking())
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+
+	expect(t, send(router, http.MethodPost, "/humidifiers/H1/corrections", `{"reason":"typo","replacement":{"stock_id":"L1","weight":1500}}`), http.StatusUnprocessableEntity)
+	if weight := stageWeight(t, stores, "L1"); weight != 500 {
+		t.Errorf("humidifier total = %v, want H1 left as it was", weight)
+	}
+
+	// The refused correction left nothing behind, so H1 can still be
+	// corrected
+	expect(t, send(router, http.MethodPost, "/humidifiers/H1/corrections", `{"reason":"typo","replacement":{"stock_id":"L1","weight":1050}}`), http.StatusCreated)
+	if weight := stageWeight(t, stores, "L1"); weight != 1050 {
+		t.Errorf("humidifier total = %v, want 1050", weight)
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Policy maps each route, written as "METHOD /path" the way it is
+// registered, to the roles allowed to call it
+type Policy map[string][]models.Role
+
+var (
+	everyone    = []models.Role{models.RoleOperator, models.RoleGrader, models.RoleSupervisor, models.RoleAdmin}
+	operators   = []models.Role{models.RoleOperator, models.RoleSupervisor, models.RoleAdmin}
+	graders     = []models.Role{models.RoleGrader, models.RoleSupervisor, models.RoleAdmin}
+	supervisors = []models.Role{models.RoleSupervisor, models.RoleAdmin}
+	admins      = []models.Role{models.RoleAdmin}
+)
+
+// RoutePolicy decides who may call every route behind RequireAuth. Anyone
+// signed in can read production data. Operators record the machine stages
+// and graders the hand grading, but only supervisors can correct or remove
+// what was recorded. Reference tables are kept by admins
+var RoutePolicy = Policy{
+	"GET /stocks":                        everyone,
+	"GET /stocks/:id":                    everyone,
+	"POST /stocks":                       operators,
+	"PUT /stocks/:id":                    supervisors,
+	"PATCH /stocks/:id":                  supervisors,
+	"DELETE /stocks/:id":                 supervisors,
+	"GET /stocks/:id/transitions":        everyone,
+	"POST /stocks/:id/transitions":       operators,
+	"POST /stocks/:id/split":             supervisors,
+	"GET /stocks/:id/yield":              everyone,
+	"GET /stocks/:id/outturn":            everyone,
+	"GET /stocks/:id/anomalies":          everyone,
+	"GET /stocks/:id/grade-distribution": everyone,
+
+	"GET /humidifiers":                  everyone,
+	"GET /humidifiers/:id":              everyone,
+	"GET /humidifiers/stock/:stock_id":  everyone,
+	"POST /humidifiers":                 operators,
+	"POST /humidifiers/bulk":            operators,
+	"POST /humidifiers/:id/corrections": supervisors,
+	"PATCH /humidifiers/:id":            supervisors,
+	"DELETE /humidifiers/:id":           supervisors,
+
+	"GET /peeling-machines":                  everyone,
+	"GET /peeling-machines/:id":              everyone,
+	"GET /peeling-machines/stock/:stockId":   everyone,
+	"POST /peeling-machines":                 operators,
+	"POST /peeling-machines/bulk":            operators,
+	"POST /peeling-machines/:id/corrections": supervisors,
+	"PATCH /peeling-machines/:id":            supervisors,
+	"DELETE /peeling-machines/:id":           supervisors,
+
+	"GET /color-sorts":                                         everyone,
+	"GET /color-sorts/:id":                                     everyone,
+	"GET /color-sorts/stock/:stockId":                          everyone,
+	"GET /color-sorts/stock/:stockId/counter/:counter":         everyone,
+	"GET /color-sorts/stock/:stockId/counter/:counter/summary": everyone,
+	"POST /color-sorts":                                        operators,
+	"POST /color-sorts/bulk":                                   operators,
+	"POST /color-sorts/:id/corrections":                        supervisors,
+	"PATCH /color-sorts/:id":                                   supervisors,
+	"DELETE /color-sorts/:id":                                  supervisors,
+
+	"GET /machine-gradings":                        everyone,
+	"GET /machine-gradings/:id":                    everyone,
+	"GET /machine-gradings/stock/:stockId":         everyone,
+	"GET /machine-gradings/stock/:stockId/summary": everyone,
+	"POST /machine-gradings":                       operators,
+	"POST /machine-gradings/bulk":                  operators,
+	"POST /machine-gradings/:id/corrections":       supervisors,
+	"PATCH /machine-gradings/:id":                  supervisors,
+	"DELETE /machine-gradings/:id":                 supervisors,
+
+	"GET /manual-grading-inputs":                  everyone,
+	"GET /manual-grading-inputs/:id":              everyone,
+	"GET /manual-grading-inputs/stock/:stockId":   everyone,
+	"POST /manual-grading-inputs":                 graders,
+	"POST /manual-grading-inputs/bulk":            graders,
+	"POST /manual-grading-inputs/:id/corrections": supervisors,
+	"PATCH /manual-grading-inputs/:id":            supervisors,
+	"DELETE /manual-grading-inputs/:id":           supervisors,
+
+	"GET /manual-grading":                  everyone,
+	"GET /manual-grading/:id":              everyone,
+	"GET /manual-grading/stock/:stockId":   everyone,
+	"POST /manual-grading":                 graders,
+	"POST /manual-grading/bulk":            graders,
+	"POST /manual-grading/:id/corrections": supervisors,
+	"PATCH /manual-grading/:id":            supervisors,
+	"DELETE /manual-grading/:id":           supervisors,
+
+	"GET /grading-sheets":        everyone,
+	"GET /grading-sheets/:id":    everyone,
+	"POST /grading-sheets":       graders,
+	"PUT /grading-sheets/:id":    supervisors,
+	"PATCH /grading-sheets/:id":  supervisors,
+	"DELETE /grading-sheets/:id": supervisors,
+
+	"GET /weight-types":        everyone,
+	"GET /weight-types/:id":    everyone,
+	"GET /weight-types/usage":  everyone,
+	"POST /weight-types":       admins,
+	"PUT /weight-types/:id":    admins,
+	"PATCH /weight-types/:id":  admins,
+	"DELETE /weight-types/:id": admins,
+
+	"GET /pieces":        everyone,
+	"GET /pieces/:id":    everyone,
+	"POST /pieces":       admins,
+	"PUT /pieces/:id":    admins,
+	"PATCH /pieces/:id":  admins,
+	"DELETE /pieces/:id": admins,
+
+	"GET /size-variations":        everyone,
+	"GET /size-variations/:id":    everyone,
+	"POST /size-variations":       admins,
+	"PUT /size-variations/:id":    admins,
+	"PATCH /size-variations/:id":  admins,
+	"DELETE /size-variations/:id": admins,
+
+	"GET /grading-categories":        everyone,
+	"GET /grading-categories/:id":    everyone,
+	"POST /grading-categories":       admins,
+	"PUT /grading-categories/:id":    admins,
+	"PATCH /grading-categories/:id":  admins,
+	"DELETE /grading-categories/:id": admins,
+
+	"GET /grader-machine-outputs":        everyone,
+	"GET /grader-machine-outputs/:id":    everyone,
+	"POST /grader-machine-outputs":       admins,
+	"PUT /grader-machine-outputs/:id":    admins,
+	"PATCH /grader-machine-outputs/:id":  admins,
+	"DELETE /grader-machine-outputs/:id": admins,
+
+	// Worker records carry Aadhaar numbers
+	"GET /workforce":        supervisors,
+	"GET /workforce/:id":    supervisors,
+	"POST /workforce":       admins,
+	"PUT /workforce/:id":    admins,
+	"PATCH /workforce/:id":  admins,
+	"DELETE /workforce/:id": admins,
+
+	"GET /sellers":               everyone,
+	"GET /sellers/:id":           everyone,
+	"GET /sellers/scorecards":    everyone,
+	"GET /sellers/:id/scorecard": everyone,
+	"POST /sellers":              supervisors,
+	"PUT /sellers/:id":           supervisors,
+	"PATCH /sellers/:id":         supervisors,
+	"DELETE /sellers/:id":        admins,
+
+	"GET /outturn/lots":    everyone,
+	"GET /outturn/sellers": everyone,
+	"GET /outturn/origins": everyone,
+
+	"GET /devices":                 admins,
+	"GET /devices/:id":             admins,
+	"POST /devices":                admins,
+	"PUT /devices/:id":             admins,
+	"PATCH /devices/:id":           admins,
+	"POST /devices/:id/rotate-key": admins,
+	"POST /devices/:id/revoke":     admins,
+
+	"GET /audit": supervisors,
+
+	"GET /trash":                      supervisors,
+	"POST /trash/:entity/:id/restore": supervisors,
+	"DELETE /trash/:entity/:id":       supervisors,
+}
+
+// authRoutes are registered by SetupAuthRoutes ahead of RequireAuth and
+// Authorize, so the policy never sees them
+var authRoutes = map[string]bool{
+	"POST /auth/login":   true,
+	"POST /auth/refresh": true,
+	"POST /auth/logout":  true,
+	"GET /auth/me":       true,
+}
+
+// Allows reports whether role may call the route
+func (p Policy) Allows(role models.Role, method, path string) bool {
+	return slices.Contains(p[method+" "+path], role)
+}
+
+// Missing returns the registered routes the policy has no entry for. A
+// route left out of the policy can't be called by anyone
+func (p Policy) Missing(routes gin.RoutesInfo) []string {
+	var missing []string
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		if _, ok := p[key]; !ok && !authRoutes[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Authorize rejects callers whose role the policy doesn't allow on the
+// matched route, and devices posting to a stage they weren't given. It must
+// run after RequireAuth
+func Authorize(policy Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			// No route matched; let the router answer 404
+			c.Next()
+			return
+		}
+
+		if device, ok := currentDevice(c); ok {
+			stage, ok := DeviceRoutes[c.Request.Method+" "+path]
+			if !ok || !device.CanRecord(stage) {
+				abortWithError(c, reject(http.StatusForbidden, "Device %q cannot %s %s", device.Name, c.Request.Method, path))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		claims, ok := currentClaims(c)
+		if !ok {
+			abortWithError(c, reject(http.StatusUnauthorized, "Authentication required"))
+			return
+		}
+
+		role := models.Role(claims.Role)
+		if !policy.Allows(role, c.Request.Method, path) {
+			abortWithError(c, reject(http.StatusForbidden, "Role %q cannot %s %s", role, c.Request.Method, path))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRoutePolicy(t *testing.T) {
+	router := newAuthRouter(t)
+	operator, admin := login(t, router, "olive"), login(t, router, "ada")
+
+	expect(t, send(router, http.MethodGet, "/stocks", ""), http.StatusUnauthorized)
+	expect(t, send(router, http.MethodGet, "/stocks", "", "Authorization", "Bearer forged"), http.StatusUnauthorized)
+	expect(t, send(router, http.MethodGet, "/stocks", "", bearer(operator)...), http.StatusOK)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody, bearer(operator)...), http.StatusForbidden)
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody, bearer(admin)...), http.StatusCreated)
+	expect(t, send(router, http.MethodGet, "/audit", "", bearer(operator)...), http.StatusForbidden)
+}
+
+func TestRoutePolicyCoversEveryRoute(t *testing.T) {
+	router := newAuthRouter(t)
+	if missing := RoutePolicy.Missing(router.Routes()); len(missing) > 0 {
+		t.Errorf("routes missing from the policy: %v", missing)
+	}
+}
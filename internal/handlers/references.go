@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// referrer counts the live records of one entity that refer to a record
+type referrer struct {
+	entity string
+	// label names the records in messages, such as "humidifier records"
+	label string
+	count func() (int, error)
+}
+
+// referencedBy returns a referrer counting the records list finds with
+// field equal to id
+func referencedBy[T any](ctx context.Context, entity, label string, spec store.ListSpec[T], list func(context.Context, store.ListOptions) (store.Page[T], error), field, id string) referrer {
+	return referrer{entity: entity, label: label, count: func() (int, error) {
+		value, err := spec.ParseValue(field, id)
+		if err != nil {
+			return 0, err
+		}
+		page, err := list(ctx, store.ListOptions{Limit: 1, Filters: []store.Filter{{Field: field, Value: value}}})
+		return page.Total, err
+	}}
+}
+
+// refuseReferenced returns a 409 naming the first referrer with live
+// records, so a record isn't trashed out from under the records using it.
+// Run it in the unit of work that trashes record, after the record is
+// locked
+func refuseReferenced(record string, referrers ...referrer) error {
+	for _, r := range referrers {
+		n, err := r.count()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return &rejection{status: http.StatusConflict, body: errorBody{
+				Code:    codeReferenced,
+				Message: fmt.Sprintf("%s is still used by %d %s; delete or correct them first", record, n, r.label),
+				Details: gin.H{"entity": r.entity, "count": n},
+			}}
+		}
+	}
+	return nil
+}
+
+// stockReferrers are the stage records kept against a lot
+func stockReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityHumidifier, "humidifier records", store.HumidifierList, tx.Humidifiers.List, "stock_id", id),
+		referencedBy(ctx, models.EntityPeelingMachine, "peeling machine records", store.PeelingMachineList, tx.PeelingMachines.List, "stock_id", id),
+		referencedBy(ctx, models.EntityColorSort, "color sort records", store.ColorSortList, tx.ColorSorts.List, "stock_id", id),
+		referencedBy(ctx, models.EntityMachineGrading, "machine grading records", store.MachineGradingList, tx.MachineGradings.List, "stock_id", id),
+		referencedBy(ctx, models.EntityManualGradingInput, "manual grading inputs", store.ManualGradingInputList, tx.ManualGradingInputs.List, "stock_id", id),
+		referencedBy(ctx, models.EntityManualGrading, "manual grading records", store.ManualGradingList, tx.ManualGradings.List, "stock_id", id),
+		referencedBy(ctx, models.EntityGradingSheet, "grading sheets", store.GradingSheetList, tx.GradingSheets.List, "stock_id", id),
+	}
+}
+
+// sellerReferrers are the lots bought from a seller
+func sellerReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityStock, "stock lots", store.StockList, tx.Stocks.List, "seller_id", id),
+	}
+}
+
+// pieceReferrers are the grading records of a piece
+func pieceReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityMachineGrading, "machine grading records", store.MachineGradingList, tx.MachineGradings.List, "pieces_id", id),
+		referencedBy(ctx, models.EntityManualGrading, "manual grading records", store.ManualGradingList, tx.ManualGradings.List, "piece_id", id),
+	}
+}
+
+// sizeVariationReferrers are the grading records of a size
+func sizeVariationReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityMachineGrading, "machine grading records", store.MachineGradingList, tx.MachineGradings.List, "size_variations_id", id),
+		referencedBy(ctx, models.EntityManualGradingInput, "manual grading inputs", store.ManualGradingInputList, tx.ManualGradingInputs.List, "size_variations_id", id),
+		referencedBy(ctx, models.EntityManualGrading, "manual grading records", store.ManualGradingList, tx.ManualGradings.List, "size_id", id),
+		referencedBy(ctx, models.EntityGradingSheet, "grading sheets", store.GradingSheetList, tx.GradingSheets.List, "size_variations_id", id),
+	}
+}
+
+// gradingCategoryReferrers are the manual grading records of a category
+func gradingCategoryReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityManualGrading, "manual grading records", store.ManualGradingList, tx.ManualGradings.List, "category_id", id),
+	}
+}
+
+// graderMachineOutputReferrers are the manual grading records of an output
+func graderMachineOutputReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityManualGrading, "manual grading records", store.ManualGradingList, tx.ManualGradings.List, "grader_machine_outputs_id", id),
+	}
+}
+
+// weightTypeReferrers are the stage records weighed as a weight type
+func weightTypeReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityPeelingMachine, "peeling machine records", store.PeelingMachineList, tx.PeelingMachines.List, "weight_type_id", id),
+		referencedBy(ctx, models.EntityColorSort, "color sort records", store.ColorSortList, tx.ColorSorts.List, "weight_type_id", id),
+	}
+}
+
+// workforceReferrers are the grading work a worker did
+func workforceReferrers(ctx context.Context, tx *store.Stores, id string) []referrer {
+	return []referrer{
+		referencedBy(ctx, models.EntityManualGradingInput, "manual grading inputs", store.ManualGradingInputList, tx.ManualGradingInputs.List, "worker_id", id),
+		referencedBy(ctx, models.EntityManualGrading, "manual grading records", store.ManualGradingList, tx.ManualGradings.List, "worker_id", id),
+		referencedBy(ctx, models.EntityGradingSheet, "grading sheets", store.GradingSheetList, tx.GradingSheets.List, "worker_id", id),
+	}
+}
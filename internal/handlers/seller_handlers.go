@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllSellers - Get all sellers
+func GetAllSellers(c *gin.Context, sellers store.SellerStore) {
+	opts, err := parseListOptions(c, store.SellerList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	page, err := sellers.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithPage(c, store.SellerList, page)
+}
+
+// GetSeller - Get single seller
+func GetSeller(c *gin.Context, sellers store.SellerStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	seller, err := sellers.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Seller not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithRecord(c, seller)
+}
+
+// CreateSeller - Create new seller
+func CreateSeller(c *gin.Context, sellers store.SellerStore) {
+	var seller models.Seller
+	if err := c.ShouldBindJSON(&seller); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if err := normalizeSeller(&seller); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !requireUniqueSeller(c, sellers, 0, seller) {
+		return
+	}
+
+	if err := sellers.Create(c.Request.Context(), &seller); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, seller)
+}
+
+// UpdateSeller - Update existing seller. Linked stock lots take the new name
+func UpdateSeller(c *gin.Context, sellers store.SellerStore, units store.UnitOfWork) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+	var seller models.Seller
+	if !bindUpdate(c, id, sellers.Get, "Seller not found", &seller) {
+		return
+	}
+	if err := normalizeSeller(&seller); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !requireUniqueSeller(c, sellers, id, seller) {
+		return
+	}
+
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Sellers.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.Sellers.Update(c.Request.Context(), id, seller)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Seller not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Seller updated successfully"})
+}
+
+// DeleteSeller - Delete a seller no stock lot refers to
+func DeleteSeller(c *gin.Context, sellers store.SellerStore, units store.UnitOfWork) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Sellers.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced(fmt.Sprintf("Seller %d", id), sellerReferrers(ctx, tx, strconv.FormatInt(id, 10))...); err != nil {
+			return err
+		}
+		return tx.Sellers.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Seller not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Seller moved to trash"})
+}
+
+// GetSellerScorecards - Get the scorecard of every seller. Stock filters in
+// the query, such as from and to, narrow the lots counted
+func GetSellerScorecards(c *gin.Context, sellers store.SellerStore, stocks store.StockStore, reportStore store.ReportStore) {
+	all, err := sellers.List(c.Request.Context(), store.All())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if scorecards, ok := sellerScorecards(c, all.Items, stocks, reportStore); ok {
+		c.JSON(http.StatusOK, gin.H{"data": scorecards})
+	}
+}
+
+// GetSellerScorecard - Get the scorecard of a single seller
+func GetSellerScorecard(c *gin.Context, sellers store.SellerStore, stocks store.StockStore, reportStore store.ReportStore) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
+
+	seller, err := sellers.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Seller not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if scorecards, ok := sellerScorecards(c, []models.Seller{seller}, stocks, reportStore); ok {
+		c.JSON(http.StatusOK, scorecards[0])
+	}
+}
+
+// sellerScorecards builds a scorecard for each seller from the lots matching
+// the stock filters in the query. On failure the error response is written
+// and false returned
+func sellerScorecards(c *gin.Context, sellers []models.Seller, stocks store.StockStore, reportStore store.ReportStore) ([]models.SellerScorecard, bool) {
+	opts, err := parseListOptions(c, store.StockList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return nil, false
+	}
+	// Scorecards cover every matching lot, so paging does not apply
+	opts.Limit, opts.After = 0, nil
+	if len(sellers) == 1 {
+		opts.Filters = append(opts.Filters, store.Filter{Field: "seller_id", Value: float64(sellers[0].ID)})
+	}
+
+	lots, err := stocks.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return nil, false
+	}
+	bySeller := make(map[int64][]models.Stock)
+	ids := make([]string, 0, len(lots.Items))
+	for _, stock := range lots.Items {
+		if stock.SellerID != nil {
+			bySeller[*stock.SellerID] = append(bySeller[*stock.SellerID], stock)
+			ids = append(ids, stock.StockID)
+		}
+	}
+
+	grades, err := reportStore.GradeWeights(c.Request.Context(), ids)
+	if err != nil {
+		respondWithError(c, err)
+		return nil, false
+	}
+
+	scorecards := make([]models.SellerScorecard, len(sellers))
+	for i, seller := range sellers {
+		scorecards[i] = reports.Scorecard(seller, bySeller[seller.ID], grades)
+	}
+	return scorecards, true
+}
+
+// normalizeSeller tidies the fields of a seller and checks the country and
+// GSTIN are well formed
+func normalizeSeller(seller *models.Seller) error {
+	seller.Name = models.SellerName(seller.Name)
+	if seller.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	seller.Country = strings.ToUpper(strings.TrimSpace(seller.Country))
+	if !models.ValidCountryCode(seller.Country) {
+		return fmt.Errorf("country must be an ISO 3166-1 alpha-2 code such as IN")
+	}
+
+	if seller.GSTIN != nil {
+		gstin := strings.ToUpper(strings.TrimSpace(*seller.GSTIN))
+		switch {
+		case gstin == "":
+			seller.GSTIN = nil
+		case !models.ValidGSTIN(gstin):
+			return fmt.Errorf("gstin %q is not a valid GST identification number", gstin)
+		default:
+			seller.GSTIN = &gstin
+		}
+	}
+	return nil
+}
+
+// requireUniqueSeller writes a 409 response when another seller already has
+// the name or GSTIN of seller. id is the seller being updated, or 0
+func requireUniqueSeller(c *gin.Context, sellers store.SellerStore, id int64, seller models.Seller) bool {
+	existing, err := sellers.GetByName(c.Request.Context(), seller.Name)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		respondWithError(c, err)
+		return false
+	}
+	if err == nil && existing.ID != id {
+		respondWithError(c, rejectField(http.StatusConflict, codeDuplicate, "name", "Seller %q already exists with ID %d", existing.Name, existing.ID))
+		return false
+	}
+
+	if seller.GSTIN == nil {
+		return true
+	}
+	matches, err := sellers.List(c.Request.Context(), store.All(store.Filter{Field: "gstin", Value: *seller.GSTIN}))
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	for _, other := range matches.Items {
+		if other.ID != id {
+			respondWithError(c, rejectField(http.StatusConflict, codeDuplicate, "gstin", "GSTIN %s is already used by seller %q", *seller.GSTIN, other.Name))
+			return false
+		}
+	}
+	return true
+}
+
+// resolveSeller links a stock lot to its seller, by seller_id or else by
+// seller_name, and copies the registered name onto the lot. When the seller
+// can't be found the error response is written and false returned
+func resolveSeller(c *gin.Context, sellers store.SellerStore, stock *models.Stock) bool {
+	var seller models.Seller
+	var err error
+	switch {
+	case stock.SellerID != nil:
+		seller, err = sellers.Get(c.Request.Context(), *stock.SellerID)
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithError(c, rejectField(http.StatusUnprocessableEntity, codeMissingReference, "seller_id", "Seller %d does not exist", *stock.SellerID))
+			return false
+		}
+	case strings.TrimSpace(stock.SellerName) != "":
+		seller, err = sellers.GetByName(c.Request.Context(), stock.SellerName)
+		if errors.Is(err, store.ErrNotFound) {
+			respondWithError(c, rejectField(http.StatusUnprocessableEntity, codeMissingReference, "seller_name", "Seller %q is not registered; add it under /sellers first", models.SellerName(stock.SellerName)))
+			return false
+		}
+	default:
+		respondWithStatus(c, http.StatusBadRequest, "seller_id or seller_name is required")
+		return false
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return false
+	}
+
+	stock.SellerID = &seller.ID
+	stock.SellerName = seller.Name
+	return true
+}
+
+// SetupSellerRoutes - Setup all routes for sellers
+func SetupSellerRoutes(router *gin.Engine, stores *store.Stores) {
+	sellers := stores.Sellers
+	units := stores.UnitOfWork
+	stocks := stores.Stocks
+	reportStore := stores.Reports
+	router.GET("/sellers", func(c *gin.Context) { GetAllSellers(c, sellers) })
+	router.GET("/sellers/scorecards", func(c *gin.Context) { GetSellerScorecards(c, sellers, stocks, reportStore) })
+	router.GET("/sellers/:id", func(c *gin.Context) { GetSeller(c, sellers) })
+	router.GET("/sellers/:id/scorecard", func(c *gin.Context) { GetSellerScorecard(c, sellers, stocks, reportStore) })
+	router.POST("/sellers", func(c *gin.Context) { CreateSeller(c, sellers) })
+	router.PUT("/sellers/:id", func(c *gin.Context) { UpdateSeller(c, sellers, units) })
+	router.PATCH("/sellers/:id", func(c *gin.Context) { UpdateSeller(c, sellers, units) })
+	router.DELETE("/sellers/:id", func(c *gin.Context) { DeleteSeller(c, sellers, units) })
+}
@@ -1,120 +1,92 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"fmt"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllSizeVariations - Get all size variations records
-func GetAllSizeVariations(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT size_id, size_value
-        FROM size_variations`)
+func GetAllSizeVariations(c *gin.Context, variations store.SizeVariationStore) {
+	opts, err := parseListOptions(c, store.SizeVariationList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var variationsList []models.SizeVariations
-	for rows.Next() {
-		var variation models.SizeVariations
-		if err := rows.Scan(
-			&variation.SizeID,
-			&variation.SizeValue,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		variationsList = append(variationsList, variation)
+
+	page, err := variations.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, variationsList)
+	respondWithPage(c, store.SizeVariationList, page)
 }
 
 // GetSizeVariation - Get single size variation record
-func GetSizeVariation(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
+func GetSizeVariation(c *gin.Context, variations store.SizeVariationStore) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
+		return
+	}
 
-	var variation models.SizeVariations
-	err := db.QueryRow(`
-        SELECT size_id, size_value
-        FROM size_variations WHERE size_id = ?`, id).Scan(
-		&variation.SizeID,
-		&variation.SizeValue,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	variation, err := variations.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, variation)
+	respondWithRecord(c, variation)
 }
 
 // CreateSizeVariation - Create new size variation record
-func CreateSizeVariation(c *gin.Context, db *sql.DB) {
+func CreateSizeVariation(c *gin.Context, variations store.SizeVariationStore) {
 	var variation models.SizeVariations
 	if err := c.ShouldBindJSON(&variation); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	result, err := db.Exec(`
-        INSERT INTO size_variations (
-            size_id, size_value
-        )
-        VALUES (?, ?)`,
-		variation.SizeID,
-		variation.SizeValue,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	lastID, err := result.LastInsertId()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := variations.Create(c.Request.Context(), &variation); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	variation.SizeID = int(lastID)
 	c.JSON(http.StatusCreated, variation)
 }
 
 // UpdateSizeVariation - Update existing size variation record
-func UpdateSizeVariation(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-	var variation models.SizeVariations
-	if err := c.ShouldBindJSON(&variation); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+func UpdateSizeVariation(c *gin.Context, variations store.SizeVariationStore, units store.UnitOfWork) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
-
-	result, err := db.Exec(`
-        UPDATE size_variations
-        SET size_value = ?
-        WHERE size_id = ?`,
-		variation.SizeValue,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	var variation models.SizeVariations
+	if !bindUpdate(c, id, variations.Get, "Record not found", &variation) {
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.SizeVariations.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.SizeVariations.Update(c.Request.Context(), id, variation)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
@@ -122,33 +94,42 @@ func UpdateSizeVariation(c *gin.Context, db *sql.DB) {
 }
 
 // DeleteSizeVariation - Delete size variation record
-func DeleteSizeVariation(c *gin.Context, db *sql.DB) {
-	id := c.Param("id")
-
-	result, err := db.Exec("DELETE FROM size_variations WHERE size_id = ?", id)
+func DeleteSizeVariation(c *gin.Context, variations store.SizeVariationStore, units store.UnitOfWork) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithStatus(c, http.StatusBadRequest, "Invalid ID")
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err = writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.SizeVariations.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced(fmt.Sprintf("Size variation %d", id), sizeVariationReferrers(ctx, tx, strconv.Itoa(id))...); err != nil {
+			return err
+		}
+		return tx.SizeVariations.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
 }
 
 // SetupSizeVariationsRoutes - Setup all routes for size variations
-func SetupSizeVariationsRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/size-variations", func(c *gin.Context) { GetAllSizeVariations(c, db) })
-	router.GET("/size-variations/:id", func(c *gin.Context) { GetSizeVariation(c, db) })
-	router.POST("/size-variations", func(c *gin.Context) { CreateSizeVariation(c, db) })
-	router.PUT("/size-variations/:id", func(c *gin.Context) { UpdateSizeVariation(c, db) })
-	router.DELETE("/size-variations/:id", func(c *gin.Context) { DeleteSizeVariation(c, db) })
-} 
\ No newline at end of file
+func SetupSizeVariationsRoutes(router *gin.Engine, stores *store.Stores) {
+	variations := stores.SizeVariations
+	units := stores.UnitOfWork
+	router.GET("/size-variations", func(c *gin.Context) { GetAllSizeVariations(c, variations) })
+	router.GET("/size-variations/:id", func(c *gin.Context) { GetSizeVariation(c, variations) })
+	router.POST("/size-variations", func(c *gin.Context) { CreateSizeVariation(c, variations) })
+	router.PUT("/size-variations/:id", func(c *gin.Context) { UpdateSizeVariation(c, variations, units) })
+	router.PATCH("/size-variations/:id", func(c *gin.Context) { UpdateSizeVariation(c, variations, units) })
+	router.DELETE("/size-variations/:id", func(c *gin.Context) { DeleteSizeVariation(c, variations, units) })
+}
@@ -1,172 +1,162 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"healing_photons/internal/ids"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // SetupRoutes configures the API routes
-func SetupRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/stocks", func(c *gin.Context) { GetAllStocks(c, db) })
-	router.GET("/stocks/:id", func(c *gin.Context) { GetStock(c, db) })
-	router.POST("/stocks", func(c *gin.Context) { CreateStock(c, db) })
-	router.PUT("/stocks/:id", func(c *gin.Context) { UpdateStock(c, db) })
-	router.DELETE("/stocks/:id", func(c *gin.Context) { DeleteStock(c, db) })
+func SetupRoutes(router *gin.Engine, stores *store.Stores) {
+	stocks := stores.Stocks
+	sellers := stores.Sellers
+	reportStore := stores.Reports
+	anomalies := stores.Anomalies
+	sequences := stores.Sequences
+	units := stores.UnitOfWork
+	trash := stores.Trash
+	router.GET("/stocks", func(c *gin.Context) { GetAllStocks(c, stocks) })
+	router.GET("/stocks/:id", func(c *gin.Context) { GetStock(c, stocks) })
+	router.POST("/stocks", func(c *gin.Context) { CreateStock(c, stocks, sellers, sequences, trash) })
+	router.PUT("/stocks/:id", func(c *gin.Context) { UpdateStock(c, stocks, units, sellers) })
+	router.PATCH("/stocks/:id", func(c *gin.Context) { UpdateStock(c, stocks, units, sellers) })
+	router.DELETE("/stocks/:id", func(c *gin.Context) { DeleteStock(c, stocks, units) })
+	router.GET("/stocks/:id/transitions", func(c *gin.Context) { GetStockTransitions(c, stocks) })
+	router.POST("/stocks/:id/transitions", func(c *gin.Context) { TransitionStock(c, units) })
+	router.POST("/stocks/:id/split", func(c *gin.Context) { SplitStock(c, units) })
+	router.GET("/stocks/:id/yield", func(c *gin.Context) { GetStockYield(c, stocks, reportStore) })
+	router.GET("/stocks/:id/outturn", func(c *gin.Context) { GetStockOutturn(c, stocks, reportStore) })
+	router.GET("/stocks/:id/anomalies", func(c *gin.Context) { GetStockAnomalies(c, stocks, anomalies) })
 }
 
 // GetStock - Get single stock
-func GetStock(c *gin.Context, db *sql.DB) {
+func GetStock(c *gin.Context, stocks store.StockStore) {
 	id := c.Param("id")
 
-	var stock models.Stock
-	err := db.QueryRow(`
-		SELECT stock_id, seller_name, origin_country, weight, date, created_at, updated_at 
-		FROM stocks WHERE stock_id = ?`, id).Scan(
-		&stock.StockID,
-		&stock.SellerName,
-		&stock.OriginCountry,
-		&stock.Weight,
-		&stock.Date,
-		&stock.CreatedAt,
-		&stock.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Stock not found"})
+	stock, err := stocks.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, stock)
+	respondWithRecord(c, stock)
 }
 
-// CreateStock - Create new stock
-func CreateStock(c *gin.Context, db *sql.DB) {
+// CreateStock - Create new stock. The server mints the stock ID, numbered
+// within the lot's year, unless the client sends one. A lot sent without a
+// date is dated now
+func CreateStock(c *gin.Context, stocks store.StockStore, sellers store.SellerStore, sequences store.SequenceStore, trash store.TrashStore) {
 	var stock models.Stock
 	if err := c.ShouldBindJSON(&stock); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !resolveSeller(c, sellers, &stock) {
+		return
+	}
+	if stock.Date.IsZero() {
+		// A lot sent without a date was received now
+		stock.Date = time.Now()
+	}
+	if err := assignID(c, sequences, models.EntityStock, ids.Fields{Day: plantCalendar(c).DayOf(stock.Date)}, &stock.StockID); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	err := db.QueryRow(`
-		INSERT INTO stock (
-			stock_id, seller_name, origin_country, weight, date, created_at, updated_at
-		)
-		VALUES (?, ?, ?, ?, ?, NOW(), NOW())
-		ON DUPLICATE KEY UPDATE
-			seller_name = VALUES(seller_name),
-			origin_country = VALUES(origin_country),
-			weight = VALUES(weight),
-			date = VALUES(date),
-			updated_at = NOW()`,
-		stock.StockID,
-		stock.SellerName,
-		stock.OriginCountry,
-		stock.Weight,
-		stock.Date,
-	).Scan(&stock.CreatedAt, &stock.UpdatedAt)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := stocks.Create(c.Request.Context(), &stock); err != nil {
+		respondWithError(c, stockConflict(c.Request.Context(), trash, stock.StockID, err))
 		return
 	}
 
 	c.JSON(http.StatusCreated, stock)
 }
 
+// stockConflict explains err, the failure to create the lot stockID. A lot
+// in the trash keeps its ID until it is restored or purged, so when the ID
+// is taken by one there the client is told to restore it instead
+func stockConflict(ctx context.Context, trash store.TrashStore, stockID string, err error) error {
+	var violation *store.ConstraintError
+	if errors.As(err, &violation) && violation.Constraint == store.ConstraintUnique {
+		if _, err := trash.Get(ctx, models.EntityStock, stockID); err == nil {
+			return rejectField(http.StatusConflict, codeDuplicate, "stock_id", "Stock %s is in the trash; restore it instead of creating it again", stockID)
+		}
+	}
+	return err
+}
+
 // UpdateStock - Update existing stock
-func UpdateStock(c *gin.Context, db *sql.DB) {
+func UpdateStock(c *gin.Context, stocks store.StockStore, units store.UnitOfWork, sellers store.SellerStore) {
 	id := c.Param("id")
 	var stock models.Stock
-	if err := c.ShouldBindJSON(&stock); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindUpdate(c, id, stocks.Get, "Stock not found", &stock) {
 		return
 	}
-
-	result, err := db.Exec(`
-		UPDATE stock
-		SET seller_name = ?, 
-			origin_country = ?, 
-			weight = ?, 
-			date = ?, 
-			updated_at = NOW()
-		WHERE stock_id = ?`,
-		stock.SellerName,
-		stock.OriginCountry,
-		stock.Weight,
-		stock.Date,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if !resolveSeller(c, sellers, &stock) {
 		return
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Stocks.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.Stocks.Update(c.Request.Context(), id, stock)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Stock not found"})
+	if err != nil {
+		respondWithError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Stock updated successfully"})
 }
 
-// DeleteStock - Delete stock
-func DeleteStock(c *gin.Context, db *sql.DB) {
+// DeleteStock - Delete a stock lot no stage record refers to
+func DeleteStock(c *gin.Context, stocks store.StockStore, units store.UnitOfWork) {
 	id := c.Param("id")
 
-	result, err := db.Exec("DELETE FROM stock WHERE stock_id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Stocks.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced("Stock "+id, stockReferrers(ctx, tx, id)...); err != nil {
+			return err
+		}
+		return tx.Stocks.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Stock not found"})
-		return
-	}
-	c.JSON(http.StatusOK, gin.H{"message": "Stock deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Stock moved to trash"})
 }
 
 // GetAllStocks - Get all stocks
-func GetAllStocks(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-		SELECT stock_id, seller_name, origin_country, weight, date, created_at, updated_at 
-		FROM stock`)
+func GetAllStocks(c *gin.Context, stocks store.StockStore) {
+	opts, err := parseListOptions(c, store.StockList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var stocks []models.Stock
-	for rows.Next() {
-		var stock models.Stock
-		if err := rows.Scan(
-			&stock.StockID,
-			&stock.SellerName,
-			&stock.OriginCountry,
-			&stock.Weight,
-			&stock.Date,
-			&stock.CreatedAt,
-			&stock.UpdatedAt,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		stocks = append(stocks, stock)
+
+	page, err := stocks.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, stocks)
+	respondWithPage(c, store.StockList, page)
 }
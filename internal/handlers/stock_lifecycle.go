@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// TransitionRequest is the body accepted by TransitionStock
+type TransitionRequest struct {
+	Status models.StockStatus `json:"status" binding:"required"`
+	Note   string             `json:"note"`
+	// Record, when sent, is the first record of the stage the lot moves
+	// into. It is stored along with the transition, or not at all
+	Record json.RawMessage `json:"record,omitempty"`
+}
+
+// transitionResponse is the transition made, along with the stage record
+// stored with it, if any
+type transitionResponse struct {
+	models.StockTransition
+	Record any `json:"record,omitempty"`
+}
+
+// TransitionStock - Move a stock lot to its next processing stage, such as
+// closing the peeling run and opening the color sort pass. A record sent
+// along is created in the new stage in the same unit of work
+func TransitionStock(c *gin.Context, units store.UnitOfWork) {
+	id := c.Param("id")
+	var request TransitionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if !request.Status.Valid() {
+		respondWithStatus(c, http.StatusBadRequest, "Unknown status %q", request.Status)
+		return
+	}
+
+	var response transitionResponse
+	err := units.Do(c.Request.Context(), func(tx *store.Stores) error {
+		stock, err := tx.Stocks.Get(c.Request.Context(), id)
+		if errors.Is(err, store.ErrNotFound) {
+			return reject(http.StatusNotFound, "Stock not found")
+		}
+		if err != nil {
+			return err
+		}
+
+		if !stock.Status.CanTransitionTo(request.Status) {
+			if next, ok := stock.Status.Next(); ok {
+				return reject(http.StatusConflict, "Stock %s is %s and can only move to %s", id, stock.Status, next)
+			}
+			return reject(http.StatusConflict, "Stock %s is closed", id)
+		}
+
+		transition, err := tx.Stocks.Transition(c.Request.Context(), id, stock.Status, request.Status, request.Note)
+		if errors.Is(err, store.ErrNotFound) {
+			return reject(http.StatusNotFound, "Stock not found")
+		}
+		if errors.Is(err, store.ErrConflict) {
+			return reject(http.StatusConflict, "Stock status changed while moving it, please retry")
+		}
+		if err != nil {
+			return err
+		}
+		response.StockTransition = transition
+
+		if len(request.Record) == 0 {
+			return nil
+		}
+		response.Record, err = openStage(c, tx, id, request.Status, request.Record)
+		return err
+	})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// openStage creates the first record of the stage status for the lot
+// stockID from raw, checked as its own create would check it
+func openStage(c *gin.Context, tx *store.Stores, stockID string, status models.StockStatus, raw json.RawMessage) (any, error) {
+	ctx := c.Request.Context()
+	switch status {
+	case models.StatusHumidifying:
+		return createOpening(raw, func(h *models.Humidifier) error {
+			h.StockID = stockID
+			if err := prepareHumidifier(c, tx, h); err != nil {
+				return err
+			}
+			if err := tx.Humidifiers.Create(ctx, h); err != nil {
+				return err
+			}
+			return checkPlausibility(c, tx, models.EntityHumidifier, stockID, h.ID)
+		})
+	case models.StatusPeeling:
+		return createOpening(raw, func(m *models.PeelingMachine) error {
+			m.StockID = &stockID
+			if err := preparePeelingMachine(c, tx, m); err != nil {
+				return err
+			}
+			if err := tx.PeelingMachines.Create(ctx, m); err != nil {
+				return err
+			}
+			return checkPlausibility(c, tx, models.EntityPeelingMachine, stockID, m.ID)
+		})
+	case models.StatusColorSorting:
+		return createOpening(raw, func(cs *models.ColorSort) error {
+			cs.StockID = &stockID
+			if err := prepareColorSort(c, tx, cs); err != nil {
+				return err
+			}
+			if err := tx.ColorSorts.Create(ctx, cs); err != nil {
+				return err
+			}
+			return checkPlausibility(c, tx, models.EntityColorSort, stockID, cs.ID)
+		})
+	case models.StatusMachineGrading:
+		return createOpening(raw, func(g *models.MachineGrading) error {
+			g.StockID = stockID
+			if err := prepareMachineGrading(c, tx, g); err != nil {
+				return err
+			}
+			if err := tx.MachineGradings.Create(ctx, g); err != nil {
+				return err
+			}
+			return checkPlausibility(c, tx, models.EntityMachineGrading, stockID, g.ID)
+		})
+	case models.StatusManualGrading:
+		return createOpening(raw, func(g *models.ManualGrading) error {
+			g.StockID = stockID
+			if err := checkManualGrading(c, tx, g); err != nil {
+				return err
+			}
+			if err := tx.ManualGradings.Create(ctx, g); err != nil {
+				return err
+			}
+			return checkPlausibility(c, tx, models.EntityManualGrading, stockID, g.ID)
+		})
+	}
+	return nil, reject(http.StatusBadRequest, "Stock moving to %s has no stage records; leave out record", status)
+}
+
+// createOpening decodes and validates a stage record, then creates it
+func createOpening[T any](raw json.RawMessage, create func(*T) error) (*T, error) {
+	record := new(T)
+	if err := json.Unmarshal(raw, record); err != nil {
+		return nil, reject(http.StatusBadRequest, "record: %s", err)
+	}
+	if err := binding.Validator.ValidateStruct(record); err != nil {
+		return nil, reject(http.StatusBadRequest, "record: %s", err)
+	}
+	if err := create(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// GetStockTransitions - Get the status history of a stock lot
+func GetStockTransitions(c *gin.Context, stocks store.StockStore) {
+	id := c.Param("id")
+
+	opts, err := parseListOptions(c, store.StockTransitionList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	opts.Filters = append(opts.Filters, store.Filter{Field: "stock_id", Value: id})
+
+	page, err := stocks.ListTransitions(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	respondWithPage(c, store.StockTransitionList, page)
+}
+
+// requireStockStatus checks that a stage record is being posted for a lot
+// in the matching stage. When it isn't, the error response is written and
+// false returned
+func requireStockStatus(c *gin.Context, stocks store.StockStore, stockID string, status models.StockStatus) bool {
+	if err := checkStockStatus(c.Request.Context(), stocks, stockID, status); err != nil {
+		respondWithError(c, err)
+		return false
+	}
+	return true
+}
+
+// checkStockStatus returns a rejection unless the lot stockID exists and is
+// in status
+func checkStockStatus(ctx context.Context, stocks store.StockStore, stockID string, status models.StockStatus) error {
+	stock, err := stocks.Get(ctx, stockID)
+	if errors.Is(err, store.ErrNotFound) {
+		return rejectField(http.StatusUnprocessableEntity, codeMissingReference, "stock_id", "Stock %s does not exist", stockID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if stock.Status != status {
+		return rejectField(http.StatusConflict, codeWrongStage, "stock_id", "Stock %s is %s; records for this stage need it to be %s", stockID, stock.Status, status)
+	}
+	return nil
+}
+
+// checkStockID returns a rejection when a stage record could not be tied
+// to a stock lot
+func checkStockID(stockID *string) error {
+	if stockID == nil || *stockID == "" {
+		return rejectField(http.StatusBadRequest, codeInvalidField, "stock_id", "stock_id is required")
+	}
+	return nil
+}
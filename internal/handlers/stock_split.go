@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"healing_photons/internal/ids"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SplitRequest is the body accepted by SplitStock
+type SplitRequest struct {
+	// StockID names the new lot; the server mints one when it is left out
+	StockID string  `json:"stock_id"`
+	Weight  float32 `json:"weight" binding:"required,gt=0"`
+}
+
+// splitResponse is the lot that was split and the lot split off it
+type splitResponse struct {
+	Source models.Stock `json:"source"`
+	Split  models.Stock `json:"split"`
+}
+
+// SplitStock - Split part of a received lot off into a lot of its own,
+// from the same seller and origin. The weight leaves the source lot and
+// the new lot is created in one unit of work, so neither happens alone
+func SplitStock(c *gin.Context, units store.UnitOfWork) {
+	id := c.Param("id")
+	var request SplitRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+
+	var response splitResponse
+	err := units.Do(c.Request.Context(), func(tx *store.Stores) error {
+		// Lock the source lot so two splits of it take their weight in turn
+		source, err := tx.Stocks.Get(store.ForUpdate(c.Request.Context()), id)
+		if errors.Is(err, store.ErrNotFound) {
+			return reject(http.StatusNotFound, "Stock not found")
+		}
+		if err != nil {
+			return err
+		}
+		if source.Status != models.StatusReceived {
+			return reject(http.StatusConflict, "Stock %s is %s; only received lots can be split", id, source.Status)
+		}
+		if request.Weight >= source.Weight {
+			return reject(http.StatusBadRequest, "weight must be less than the %v of stock %s", source.Weight, id)
+		}
+
+		split := source
+		split.StockID, split.Weight = request.StockID, request.Weight
+		received := source.Date
+		if received.IsZero() {
+			received = time.Now()
+		}
+		if err := assignID(c, tx.Sequences, models.EntityStock, ids.Fields{Day: plantCalendar(c).DayOf(received)}, &split.StockID); err != nil {
+			return err
+		}
+
+		source.Weight -= request.Weight
+		if err := tx.Stocks.Update(c.Request.Context(), id, source); err != nil {
+			return err
+		}
+		if err := tx.Stocks.Create(c.Request.Context(), &split); err != nil {
+			return stockConflict(c.Request.Context(), tx.Trash, split.StockID, err)
+		}
+		if source, err = tx.Stocks.Get(c.Request.Context(), id); err != nil {
+			return err
+		}
+		response = splitResponse{Source: source, Split: split}
+		return nil
+	})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+// receiveLot creates seller 1 and the received lot stockID
+func receiveLot(t *testing.T, router http.Handler, stockID string) {
+	t.Helper()
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"`+stockID+`","seller_id":1,"weight":1000,"date":"2026-03-02T09:00:00+05:30"}`), http.StatusCreated)
+}
+
+func TestSplitStock(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveLot(t, router, "L1")
+
+	w := send(router, http.MethodPost, "/stocks/L1/split", `{"stock_id":"L1-B","weight":400}`)
+	expect(t, w, http.StatusCreated)
+	got := decode[splitResponse](t, w)
+	if got.Source.Weight != 600 || got.Split.Weight != 400 {
+		t.Errorf("split into %v and %v, want 600 and 400", got.Source.Weight, got.Split.Weight)
+	}
+	if *got.Split.SellerID != *got.Source.SellerID || !got.Split.Date.Equal(got.Source.Date) || got.Split.Status != models.StatusReceived {
+		t.Errorf("split = %+v, want the source's seller and date, received", got.Split)
+	}
+
+	// The split minted an ID when none was sent
+	w = send(router, http.MethodPost, "/stocks/L1/split", `{"weight":100}`)
+	expect(t, w, http.StatusCreated)
+	if got := decode[splitResponse](t, w); got.Split.StockID == "" || got.Source.Weight != 500 {
+		t.Errorf("split = %+v, want a minted ID and 500 left on L1", got)
+	}
+}
+
+func TestSplitStockRefuses(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveLot(t, router, "L1")
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"L2","seller_id":1,"weight":500}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks", `{"stock_id":"L3","seller_id":1,"weight":500}`), http.StatusCreated)
+	tag := send(router, http.MethodGet, "/stocks/L3", "").Header().Get("ETag")
+	expect(t, send(router, http.MethodDelete, "/stocks/L3", "", "If-Match", tag), http.StatusOK)
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"unknown lot", "/stocks/L9/split", `{"weight":100}`, http.StatusNotFound, codeNotFound},
+		{"whole lot", "/stocks/L1/split", `{"weight":1000}`, http.StatusBadRequest, codeInvalidRequest},
+		{"live ID", "/stocks/L1/split", `{"stock_id":"L2","weight":100}`, http.StatusConflict, codeDuplicate},
+		{"trashed ID", "/stocks/L1/split", `{"stock_id":"L3","weight":100}`, http.StatusConflict, codeDuplicate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(router, http.MethodPost, tt.path, tt.body)
+			expect(t, w, tt.status)
+			if got := decode[errorResponse](t, w); got.Error.Code != tt.code {
+				t.Errorf("code = %q, want %q", got.Error.Code, tt.code)
+			}
+		})
+	}
+
+	// None of the refusals took weight off L1
+	if stock := decode[models.Stock](t, send(router, http.MethodGet, "/stocks/L1", "")); stock.Weight != 1000 {
+		t.Errorf("L1 weighs %v, want 1000", stock.Weight)
+	}
+
+	expect(t, send(router, http.MethodPost, "/stocks/L1/transitions", `{"status":"humidifying"}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/stocks/L1/split", `{"weight":100}`), http.StatusConflict)
+}
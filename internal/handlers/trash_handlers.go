@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash - Get the deleted stock lots, master records and stage records,
+// most recently deleted first. Filter with ?entity=, e.g.
+// /trash?entity=sellers
+func GetTrash(c *gin.Context, trash store.TrashStore) {
+	opts, err := parseListOptions(c, store.TrashList)
+	if err != nil {
+		respondWithError(c, invalid(err))
+		return
+	}
+	if entity, ok := c.GetQuery("entity"); ok && !requireTrashEntity(c, entity) {
+		return
+	}
+
+	page, err := trash.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	respondWithPage(c, store.TrashList, page)
+}
+
+// RestoreTrashItem - Take a deleted record out of the trash. A stage record
+// brings its weight back to its lot, so the lot's output at the stage is
+// checked again as it was when the record was created
+func RestoreTrashItem(c *gin.Context, stores *store.Stores) {
+	entity, id := c.Param("entity"), c.Param("id")
+	if !requireTrashEntity(c, entity) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := stores.UnitOfWork.Do(ctx, func(tx *store.Stores) error {
+		item, err := tx.Trash.Get(ctx, entity, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Trash.Restore(ctx, entity, id); err != nil {
+			return err
+		}
+		if !slices.Contains(stageEntities, entity) {
+			return nil
+		}
+
+		// A stage record is labelled with the lot it belongs to
+		if _, err := tx.Stocks.Get(ctx, item.Label); errors.Is(err, store.ErrNotFound) {
+			return reject(http.StatusConflict, "Stock %s is in the trash; restore it first", item.Label)
+		} else if err != nil {
+			return err
+		}
+		return checkPlausibility(c, tx, entity, item.Label, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found in trash")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Record restored successfully"})
+}
+
+// PurgeTrashItem - Delete a record in the trash for good
+func PurgeTrashItem(c *gin.Context, trash store.TrashStore) {
+	entity, id := c.Param("entity"), c.Param("id")
+	if !requireTrashEntity(c, entity) {
+		return
+	}
+
+	err := trash.Purge(c.Request.Context(), entity, id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found in trash")
+		return
+	}
+	if errors.Is(err, store.ErrConflict) {
+		respondWithStatus(c, http.StatusConflict, "Other records still refer to %s %s; it can only be restored", entity, id)
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Record purged successfully"})
+}
+
+// stageEntities are the trash entities whose records carry weight for a lot
+var stageEntities = []string{
+	models.EntityHumidifier,
+	models.EntityPeelingMachine,
+	models.EntityColorSort,
+	models.EntityMachineGrading,
+	models.EntityManualGradingInput,
+	models.EntityManualGrading,
+}
+
+// requireTrashEntity writes a 400 response unless entity goes to the trash
+// when deleted
+func requireTrashEntity(c *gin.Context, entity string) bool {
+	if !slices.Contains(models.TrashEntities, entity) {
+		respondWithStatus(c, http.StatusBadRequest, "Unknown entity %q; use one of %v", entity, models.TrashEntities)
+		return false
+	}
+	return true
+}
+
+// SetupTrashRoutes - Setup the routes for deleted records
+func SetupTrashRoutes(router *gin.Engine, stores *store.Stores) {
+	trash := stores.Trash
+	router.GET("/trash", func(c *gin.Context) { GetTrash(c, trash) })
+	router.POST("/trash/:entity/:id/restore", func(c *gin.Context) { RestoreTrashItem(c, stores) })
+	router.DELETE("/trash/:entity/:id", func(c *gin.Context) { PurgeTrashItem(c, trash) })
+}
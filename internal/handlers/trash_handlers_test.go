@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTrashRouter is newTestRouter with the trash routes
+func newTrashRouter() *gin.Engine {
+	router, stores := newTestRouter(reports.DefaultPlausibility())
+	SetupTrashRoutes(router, stores)
+	return router
+}
+
+// deleteRecord deletes the record at path with the ETag it reads it with
+func deleteRecord(t *testing.T, router http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	tag := send(router, http.MethodGet, path, "").Header().Get("ETag")
+	return send(router, http.MethodDelete, path, "", "If-Match", tag)
+}
+
+func TestDeleteReferencedStockConflicts(t *testing.T) {
+	router := newTrashRouter()
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+
+	w := deleteRecord(t, router, "/stocks/L1")
+	expect(t, w, http.StatusConflict)
+	body := decode[errorResponse](t, w).Error
+	if body.Code != codeReferenced || body.Details.(map[string]any)["entity"] != models.EntityHumidifier {
+		t.Errorf("error = %+v, want it to name the humidifier records", body)
+	}
+
+	// Once the stage record is gone the lot can follow it
+	expect(t, send(router, http.MethodDelete, "/humidifiers/H1", ""), http.StatusOK)
+	expect(t, deleteRecord(t, router, "/stocks/L1"), http.StatusOK)
+}
+
+func TestDeleteSellerWithLotsConflicts(t *testing.T) {
+	router := newTrashRouter()
+	receiveStock(t, router, "L1", "1000")
+
+	w := deleteRecord(t, router, "/sellers/1")
+	expect(t, w, http.StatusConflict)
+	if body := decode[errorResponse](t, w).Error; body.Code != codeReferenced {
+		t.Errorf("code = %s, want %s", body.Code, codeReferenced)
+	}
+}
+
+func TestTrashRestoreAndPurge(t *testing.T) {
+	router := newTrashRouter()
+	expect(t, send(router, http.MethodPost, "/sellers", sellerBody), http.StatusCreated)
+	expect(t, deleteRecord(t, router, "/sellers/1"), http.StatusOK)
+	expect(t, send(router, http.MethodGet, "/sellers/1", ""), http.StatusNotFound)
+
+	w := send(router, http.MethodGet, "/trash?entity="+models.EntitySeller, "")
+	expect(t, w, http.StatusOK)
+	if items := decode[struct{ Data []models.TrashItem }](t, w).Data; len(items) != 1 || items[0].ID != "1" {
+		t.Fatalf("trash = %+v, want seller 1", items)
+	}
+
+	expect(t, send(router, http.MethodPost, "/trash/sellers/1/restore", ""), http.StatusOK)
+	expect(t, send(router, http.MethodGet, "/sellers/1", ""), http.StatusOK)
+
+	expect(t, deleteRecord(t, router, "/sellers/1"), http.StatusOK)
+	expect(t, send(router, http.MethodDelete, "/trash/sellers/1", ""), http.StatusOK)
+	expect(t, send(router, http.MethodPost, "/trash/sellers/1/restore", ""), http.StatusNotFound)
+}
+
+func TestRestoreStageRecordOfTrashedLotConflicts(t *testing.T) {
+	router := newTrashRouter()
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":500}`), http.StatusCreated)
+	expect(t, send(router, http.MethodDelete, "/humidifiers/H1", ""), http.StatusOK)
+	expect(t, deleteRecord(t, router, "/stocks/L1"), http.StatusOK)
+
+	expect(t, send(router, http.MethodPost, "/trash/humidifier/H1/restore", ""), http.StatusConflict)
+}
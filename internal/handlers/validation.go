@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strconv"
+)
+
+// checkExists adds an "exists" violation of field to problems unless get
+// finds the record id, which it returns when it does
+func checkExists[K any, T any](ctx context.Context, problems *models.Violations, field string, id K, get func(context.Context, K) (T, error), what string) (T, bool, error) {
+	record, err := get(ctx, id)
+	if errors.Is(err, store.ErrNotFound) {
+		problems.Add(field, "exists", "%s %v does not exist", what, id)
+		return record, false, nil
+	}
+	if err != nil {
+		return record, false, err
+	}
+	return record, true, nil
+}
+
+// checkSameStock adds a "same_stock" violation of field unless the record
+// it refers to, parentID, belongs to the lot stockID too
+func checkSameStock(problems *models.Violations, field, stockID, parentStockID, what string, parentID any) {
+	if stockID != "" && parentStockID != "" && stockID != parentStockID {
+		problems.Add(field, "same_stock", "%s %v belongs to stock %s, not %s", what, parentID, parentStockID, stockID)
+	}
+}
+
+// checkCurrent adds a "current" violation of field unless the stage record
+// id it refers to still stands. A reversal only cancels another record, and
+// a record with corrections, listed by list, was voided or replaced
+func checkCurrent[T any](ctx context.Context, problems *models.Violations, field, id string, entryType models.EntryType, list func(context.Context, store.ListOptions) (store.Page[T], error), entry func(T) (models.EntryType, string), what string) error {
+	if entryType == models.EntryReversal {
+		problems.Add(field, "current", "%s %s is a reversal, which can't be referred to", what, id)
+		return nil
+	}
+
+	corrections, err := list(ctx, store.ListOptions{Limit: 2, Filters: []store.Filter{{Field: "corrects_id", Value: id}}})
+	if err != nil {
+		return err
+	}
+	if len(corrections.Items) == 0 {
+		return nil
+	}
+	for _, correction := range corrections.Items {
+		if entryType, replacementID := entry(correction); entryType == models.EntryReplacement {
+			problems.Add(field, "current", "%s %s has been corrected; refer to its replacement %s", what, id, replacementID)
+			return nil
+		}
+	}
+	problems.Add(field, "current", "%s %s has been voided", what, id)
+	return nil
+}
+
+// checkWeightType adds a violation unless the weight type id exists. IDs
+// below 1 are left to the record's own rules
+func checkWeightType(ctx context.Context, weightTypes store.WeightTypeStore, problems *models.Violations, id int) error {
+	if id < 1 {
+		return nil
+	}
+	_, _, err := checkExists(ctx, problems, "weight_type_id", strconv.Itoa(id), weightTypes.Get, "Weight type")
+	return err
+}
+
+// checkSize adds a violation of field unless size_variations has the size id
+func checkSize(ctx context.Context, sizes store.SizeVariationStore, problems *models.Violations, field string, id int64) error {
+	if id < 1 {
+		return nil
+	}
+	_, _, err := checkExists(ctx, problems, field, int(id), sizes.Get, "Size")
+	return err
+}
+
+// checkPiece adds a violation of field unless pieces has the piece id
+func checkPiece(ctx context.Context, pieces store.PieceStore, problems *models.Violations, field string, id int64) error {
+	_, _, err := checkExists(ctx, problems, field, int(id), pieces.Get, "Piece")
+	return err
+}
+
+// checkWorker adds a violation of field unless the worker id exists
+func checkWorker(ctx context.Context, workforce store.WorkforceStore, problems *models.Violations, field, id string) error {
+	if id == "" {
+		return nil
+	}
+	_, _, err := checkExists(ctx, problems, field, id, workforce.Get, "Worker")
+	return err
+}
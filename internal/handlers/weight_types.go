@@ -1,118 +1,82 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllWeightTypes - Get all weight type records
-func GetAllWeightTypes(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, type 
-        FROM weight_types`)
+func GetAllWeightTypes(c *gin.Context, weightTypes store.WeightTypeStore) {
+	opts, err := parseListOptions(c, store.WeightTypeList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var weightTypes []models.WeightTypes
-	for rows.Next() {
-		var weightType models.WeightTypes
-		if err := rows.Scan(
-			&weightType.ID,
-			&weightType.Type,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		weightTypes = append(weightTypes, weightType)
+
+	page, err := weightTypes.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, weightTypes)
+	respondWithPage(c, store.WeightTypeList, page)
 }
 
 // GetWeightType - Get single weight type record
-func GetWeightType(c *gin.Context, db *sql.DB) {
+func GetWeightType(c *gin.Context, weightTypes store.WeightTypeStore) {
 	id := c.Param("id")
 
-	var weightType models.WeightTypes
-	err := db.QueryRow(`
-        SELECT id, type 
-        FROM weight_types WHERE id = ?`, id).Scan(
-		&weightType.ID,
-		&weightType.Type,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	weightType, err := weightTypes.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, weightType)
+	respondWithRecord(c, weightType)
 }
 
 // CreateWeightType - Create new weight type record
-func CreateWeightType(c *gin.Context, db *sql.DB) {
+func CreateWeightType(c *gin.Context, weightTypes store.WeightTypeStore) {
 	var weightType models.WeightTypes
 	if err := c.ShouldBindJSON(&weightType); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	result, err := db.Exec(`
-        INSERT INTO weight_types (id, type)
-        VALUES (?, ?)`,
-		weightType.ID,
-		weightType.Type,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := weightTypes.Create(c.Request.Context(), &weightType); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	lastID, err := result.LastInsertId()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	weightType.ID = string(lastID)
 	c.JSON(http.StatusCreated, weightType)
 }
 
 // UpdateWeightType - Update existing weight type record
-func UpdateWeightType(c *gin.Context, db *sql.DB) {
+func UpdateWeightType(c *gin.Context, weightTypes store.WeightTypeStore, units store.UnitOfWork) {
 	id := c.Param("id")
 	var weightType models.WeightTypes
-	if err := c.ShouldBindJSON(&weightType); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindUpdate(c, id, weightTypes.Get, "Record not found", &weightType) {
 		return
 	}
 
-	result, err := db.Exec(`
-        UPDATE weight_types
-        SET type = ?
-        WHERE id = ?`,
-		weightType.Type,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.WeightTypes.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.WeightTypes.Update(c.Request.Context(), id, weightType)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithError(c, err)
 		return
 	}
 
@@ -120,77 +84,50 @@ func UpdateWeightType(c *gin.Context, db *sql.DB) {
 }
 
 // DeleteWeightType - Delete weight type record
-func DeleteWeightType(c *gin.Context, db *sql.DB) {
+func DeleteWeightType(c *gin.Context, weightTypes store.WeightTypeStore, units store.UnitOfWork) {
 	id := c.Param("id")
 
-	result, err := db.Exec("DELETE FROM weight_types WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.WeightTypes.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced("Weight type "+id, weightTypeReferrers(ctx, tx, id)...); err != nil {
+			return err
+		}
+		return tx.WeightTypes.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
 }
 
 // GetWeightTypesByUsage - Get weight types with their usage count
-func GetWeightTypesByUsage(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT wt.id, wt.type, COUNT(mg.id) as usage_count
-        FROM weight_types wt
-        LEFT JOIN machine_grading mg ON wt.id = mg.weight_type_id
-        GROUP BY wt.id, wt.type
-        ORDER BY usage_count DESC`)
+func GetWeightTypesByUsage(c *gin.Context, weightTypes store.WeightTypeStore) {
+	usage, err := weightTypes.Usage(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	defer rows.Close()
-
-	var weightTypes []struct {
-		models.WeightTypes
-		UsageCount int `json:"usage_count"`
-	}
-
-	for rows.Next() {
-		var wt struct {
-			models.WeightTypes
-			UsageCount int `json:"usage_count"`
-		}
-		if err := rows.Scan(
-			&wt.ID,
-			&wt.Type,
-			&wt.UsageCount,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		weightTypes = append(weightTypes, wt)
-	}
-
-	if len(weightTypes) == 0 {
-		c.JSON(http.StatusOK, []struct{}{}) // Return empty array instead of null
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, weightTypes)
+	c.JSON(http.StatusOK, usage)
 }
 
 // SetupWeightTypeRoutes - Setup all routes for weight types
-func SetupWeightTypeRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/weight-types", func(c *gin.Context) { GetAllWeightTypes(c, db) })
-	router.GET("/weight-types/:id", func(c *gin.Context) { GetWeightType(c, db) })
-	router.POST("/weight-types", func(c *gin.Context) { CreateWeightType(c, db) })
-	router.PUT("/weight-types/:id", func(c *gin.Context) { UpdateWeightType(c, db) })
-	router.DELETE("/weight-types/:id", func(c *gin.Context) { DeleteWeightType(c, db) })
-	router.GET("/weight-types/usage", func(c *gin.Context) { GetWeightTypesByUsage(c, db) })
+func SetupWeightTypeRoutes(router *gin.Engine, stores *store.Stores) {
+	weightTypes := stores.WeightTypes
+	units := stores.UnitOfWork
+	router.GET("/weight-types", func(c *gin.Context) { GetAllWeightTypes(c, weightTypes) })
+	router.GET("/weight-types/:id", func(c *gin.Context) { GetWeightType(c, weightTypes) })
+	router.POST("/weight-types", func(c *gin.Context) { CreateWeightType(c, weightTypes) })
+	router.PUT("/weight-types/:id", func(c *gin.Context) { UpdateWeightType(c, weightTypes, units) })
+	router.PATCH("/weight-types/:id", func(c *gin.Context) { UpdateWeightType(c, weightTypes, units) })
+	router.DELETE("/weight-types/:id", func(c *gin.Context) { DeleteWeightType(c, weightTypes, units) })
+	router.GET("/weight-types/usage", func(c *gin.Context) { GetWeightTypesByUsage(c, weightTypes) })
 }
@@ -1,130 +1,82 @@
 package handlers
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"healing_photons/internal/models"
+	"healing_photons/internal/store"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetAllWorkforce - Get all workforce records
-func GetAllWorkforce(c *gin.Context, db *sql.DB) {
-	rows, err := db.Query(`
-        SELECT id, name, aadhaar, address
-        FROM workforce`)
+func GetAllWorkforce(c *gin.Context, workforce store.WorkforceStore) {
+	opts, err := parseListOptions(c, store.WorkforceList)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, invalid(err))
 		return
 	}
-	defer rows.Close()
-
-	var workforceList []models.Workforce
-	for rows.Next() {
-		var workforce models.Workforce
-		if err := rows.Scan(
-			&workforce.ID,
-			&workforce.Name,
-			&workforce.Aadhaar,
-			&workforce.Addresss,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
-		workforceList = append(workforceList, workforce)
+
+	page, err := workforce.List(c.Request.Context(), opts)
+	if err != nil {
+		respondWithError(c, err)
+		return
 	}
-	c.JSON(http.StatusOK, workforceList)
+	respondWithPage(c, store.WorkforceList, page)
 }
 
 // GetWorkforce - Get single workforce record
-func GetWorkforce(c *gin.Context, db *sql.DB) {
+func GetWorkforce(c *gin.Context, workforce store.WorkforceStore) {
 	id := c.Param("id")
 
-	var workforce models.Workforce
-	err := db.QueryRow(`
-        SELECT id, name, aadhaar, address
-        FROM workforce WHERE id = ?`, id).Scan(
-		&workforce.ID,
-		&workforce.Name,
-		&workforce.Aadhaar,
-		&workforce.Addresss,
-	)
-	if err == sql.ErrNoRows {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+	worker, err := workforce.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, workforce)
+	respondWithRecord(c, worker)
 }
 
 // CreateWorkforce - Create new workforce record
-func CreateWorkforce(c *gin.Context, db *sql.DB) {
-	var workforce models.Workforce
-	if err := c.ShouldBindJSON(&workforce); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	result, err := db.Exec(`
-        INSERT INTO workforce (
-            id, name, aadhaar, address
-        )
-        VALUES (?, ?, ?, ?)`,
-		workforce.ID,
-		workforce.Name,
-		workforce.Aadhaar,
-		workforce.Addresss,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+func CreateWorkforce(c *gin.Context, workforce store.WorkforceStore) {
+	var worker models.Workforce
+	if err := c.ShouldBindJSON(&worker); err != nil {
+		respondWithError(c, invalid(err))
 		return
 	}
 
-	lastID, err := result.LastInsertId()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := workforce.Create(c.Request.Context(), &worker); err != nil {
+		respondWithError(c, err)
 		return
 	}
 
-	workforce.ID = string(lastID)
-	c.JSON(http.StatusCreated, workforce)
+	c.JSON(http.StatusCreated, worker)
 }
 
 // UpdateWorkforce - Update existing workforce record
-func UpdateWorkforce(c *gin.Context, db *sql.DB) {
+func UpdateWorkforce(c *gin.Context, workforce store.WorkforceStore, units store.UnitOfWork) {
 	id := c.Param("id")
-	var workforce models.Workforce
-	if err := c.ShouldBindJSON(&workforce); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var worker models.Workforce
+	if !bindUpdate(c, id, workforce.Get, "Record not found", &worker) {
 		return
 	}
 
-	result, err := db.Exec(`
-        UPDATE workforce
-        SET name = ?,
-            aadhaar = ?,
-            address = ?
-        WHERE id = ?`,
-		workforce.Name,
-		workforce.Aadhaar,
-		workforce.Addresss,
-		id,
-	)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Workforce.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		return tx.Workforce.Update(c.Request.Context(), id, worker)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithError(c, err)
 		return
 	}
 
@@ -132,33 +84,38 @@ func UpdateWorkforce(c *gin.Context, db *sql.DB) {
 }
 
 // DeleteWorkforce - Delete workforce record
-func DeleteWorkforce(c *gin.Context, db *sql.DB) {
+func DeleteWorkforce(c *gin.Context, workforce store.WorkforceStore, units store.UnitOfWork) {
 	id := c.Param("id")
 
-	result, err := db.Exec("DELETE FROM workforce WHERE id = ?", id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	err := writeUnchanged(c, units, func(tx *store.Stores, ctx context.Context) (any, error) {
+		return tx.Workforce.Get(ctx, id)
+	}, func(tx *store.Stores) error {
+		ctx := c.Request.Context()
+		if err := refuseReferenced("Worker "+id, workforceReferrers(ctx, tx, id)...); err != nil {
+			return err
+		}
+		return tx.Workforce.Delete(ctx, id)
+	})
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Record not found")
 		return
 	}
-
-	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Record not found"})
+		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Record deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Record moved to trash"})
 }
 
 // SetupWorkforceRoutes - Setup all routes for workforce
-func SetupWorkforceRoutes(router *gin.Engine, db *sql.DB) {
-	router.GET("/workforce", func(c *gin.Context) { GetAllWorkforce(c, db) })
-	router.GET("/workforce/:id", func(c *gin.Context) { GetWorkforce(c, db) })
-	router.POST("/workforce", func(c *gin.Context) { CreateWorkforce(c, db) })
-	router.PUT("/workforce/:id", func(c *gin.Context) { UpdateWorkforce(c, db) })
-	router.DELETE("/workforce/:id", func(c *gin.Context) { DeleteWorkforce(c, db) })
-} 
\ No newline at end of file
+func SetupWorkforceRoutes(router *gin.Engine, stores *store.Stores) {
+	workforce := stores.Workforce
+	units := stores.UnitOfWork
+	router.GET("/workforce", func(c *gin.Context) { GetAllWorkforce(c, workforce) })
+	router.GET("/workforce/:id", func(c *gin.Context) { GetWorkforce(c, workforce) })
+	router.POST("/workforce", func(c *gin.Context) { CreateWorkforce(c, workforce) })
+	router.PUT("/workforce/:id", func(c *gin.Context) { UpdateWorkforce(c, workforce, units) })
+	router.PATCH("/workforce/:id", func(c *gin.Context) { UpdateWorkforce(c, workforce, units) })
+	router.DELETE("/workforce/:id", func(c *gin.Context) { DeleteWorkforce(c, workforce, units) })
+}
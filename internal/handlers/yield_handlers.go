@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"healing_photons/internal/reports"
+	"healing_photons/internal/store"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStockYield - Get the mass balance of a stock lot across every stage
+func GetStockYield(c *gin.Context, stocks store.StockStore, reportStore store.ReportStore) {
+	id := c.Param("id")
+
+	stock, err := stocks.Get(c.Request.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		respondWithStatus(c, http.StatusNotFound, "Stock not found")
+		return
+	}
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	totals, err := reportStore.StageTotals(c.Request.Context(), id)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reports.Yield(stock, totals))
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"healing_photons/internal/models"
+	"healing_photons/internal/reports"
+	"net/http"
+	"testing"
+)
+
+func TestStockYield(t *testing.T) {
+	router, _ := newTestRouter(reports.DefaultPlausibility())
+	receiveStock(t, router, "L1", "1000")
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H1","stock_id":"L1","weight":600}`), http.StatusCreated)
+	expect(t, send(router, http.MethodPost, "/humidifiers", `{"id":"H2","stock_id":"L1","weight":420}`), http.StatusCreated)
+
+	w := send(router, http.MethodGet, "/stocks/L1/yield", "")
+	expect(t, w, http.StatusOK)
+	report := decode[models.YieldReport](t, w)
+	if report.ReceivedWeight != 1000 || report.FinalStage != models.EntityHumidifier || report.FinalWeight != 1020 ||
+		report.YieldPercent == nil || *report.YieldPercent != 102 {
+		t.Fatalf("report = %+v, want 1000 kg humidified to 1020 kg", report)
+	}
+
+	// Humidifying gains weight, and the stages not reached yet lose none
+	humidifier, peeling := report.Stages[0], report.Stages[1]
+	if humidifier.Records != 2 || humidifier.Loss == nil || *humidifier.Loss != -20 || *humidifier.LossPercent != -2 {
+		t.Errorf("humidifier = %+v, want a 20 kg gain over 2 records", humidifier)
+	}
+	if peeling.Records != 0 || peeling.WeightIn != 1020 || peeling.Loss != nil {
+		t.Errorf("peeling = %+v, want 1020 kg in and no loss", peeling)
+	}
+
+	expect(t, send(router, http.MethodGet, "/stocks/L9/yield", ""), http.StatusNotFound)
+}
@@ -0,0 +1,212 @@
+// Package ids mints the human-readable IDs of stock lots and stage records
+package ids
+
+import (
+	"fmt"
+	"healing_photons/internal/models"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entities are the records whose IDs the server can mint
+var Entities = []string{
+	models.EntityStock,
+	models.EntityHumidifier,
+	models.EntityPeelingMachine,
+	models.EntityColorSort,
+	models.EntityMachineGrading,
+}
+
+// defaultPatterns number lots within their year and stage records within
+// their lot, e.g. STK-2026-000123 and CS-STK-2026-000123-2-001
+var defaultPatterns = map[string]string{
+	models.EntityStock:          "STK-{yyyy}-{seq:6}",
+	models.EntityHumidifier:     "HUM-{stock}-{seq:3}",
+	models.EntityPeelingMachine: "PEL-{stock}-{seq:3}",
+	models.EntityColorSort:      "CS-{stock}-{counter}-{seq:3}",
+	models.EntityMachineGrading: "MG-{stock}-{seq:3}",
+}
+
+// recordFields lists the placeholders each entity can fill in besides the
+// date and the sequence number
+var recordFields = map[string][]string{
+	models.EntityHumidifier:     {"stock"},
+	models.EntityPeelingMachine: {"stock"},
+	models.EntityColorSort:      {"stock", "counter"},
+	models.EntityMachineGrading: {"stock"},
+}
+
+// Fields are the values a pattern is filled in with
+type Fields struct {
+	// Day is the plant working day the record belongs to
+	Day     time.Time
+	StockID string
+	// Counter is the color sort pass
+	Counter int
+}
+
+// Scheme says how the IDs of each entity are minted
+type Scheme struct {
+	Patterns map[string]Pattern
+	// ClientIDs accepts IDs chosen by the client, so devices that record
+	// offline can name their records before they reach the server
+	ClientIDs bool
+}
+
+// DefaultScheme returns the default patterns, accepting client IDs
+func DefaultScheme() Scheme {
+	scheme := Scheme{Patterns: map[string]Pattern{}, ClientIDs: true}
+	for _, entity := range Entities {
+		pattern, err := ParsePattern(entity, defaultPatterns[entity])
+		if err != nil {
+			panic(err)
+		}
+		scheme.Patterns[entity] = pattern
+	}
+	return scheme
+}
+
+// part is a piece of literal text or a placeholder
+type part struct {
+	literal string
+	field   string
+	width   int
+}
+
+// Pattern is a parsed ID pattern. Placeholders are written in braces:
+// {yyyy}, {yy}, {mm} and {dd} for the plant day, {stock} for the record's
+// lot, {counter} for the color sort pass and {seq} or {seq:N} for the
+// sequence number, zero-padded to N digits
+type Pattern struct {
+	entity  string
+	raw     string
+	parts   []part
+	matcher *regexp.Regexp
+}
+
+// ParsePattern parses the ID pattern of entity. The pattern must contain
+// {seq} exactly once and may only use the placeholders entity can fill in
+func ParsePattern(entity, raw string) (Pattern, error) {
+	pattern := Pattern{entity: entity, raw: raw}
+	expr := strings.Builder{}
+	expr.WriteString("^")
+	sequences := 0
+	for rest := raw; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			open = len(rest)
+		}
+		if open > 0 {
+			pattern.parts = append(pattern.parts, part{literal: rest[:open]})
+			expr.WriteString(regexp.QuoteMeta(rest[:open]))
+			rest = rest[open:]
+			continue
+		}
+
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return Pattern{}, fmt.Errorf("ID pattern %q has an unclosed {", raw)
+		}
+		field, width, _ := strings.Cut(rest[1:end], ":")
+		p := part{field: field}
+		if width != "" {
+			if field != "seq" {
+				return Pattern{}, fmt.Errorf("ID pattern %q: only {seq} takes a width", raw)
+			}
+			n, err := strconv.Atoi(width)
+			if err != nil || n < 1 || n > 12 {
+				return Pattern{}, fmt.Errorf("ID pattern %q: {seq:%s} needs a width between 1 and 12", raw, width)
+			}
+			p.width = n
+		}
+		switch {
+		case field == "yyyy":
+			expr.WriteString(`\d{4}`)
+		case field == "yy" || field == "mm" || field == "dd":
+			expr.WriteString(`\d{2}`)
+		case field == "seq":
+			sequences++
+			expr.WriteString(`\d{` + strconv.Itoa(max(p.width, 1)) + `,}`)
+		case field == "stock" && pattern.fills(field):
+			expr.WriteString(`.+`)
+		case field == "counter" && pattern.fills(field):
+			expr.WriteString(`\d+`)
+		default:
+			return Pattern{}, fmt.Errorf("ID pattern %q: %s IDs can't use {%s}", raw, entity, field)
+		}
+		pattern.parts = append(pattern.parts, p)
+		rest = rest[end+1:]
+	}
+	if sequences != 1 {
+		return Pattern{}, fmt.Errorf("ID pattern %q must contain {seq} exactly once", raw)
+	}
+	expr.WriteString("$")
+	pattern.matcher = regexp.MustCompile(expr.String())
+	return pattern, nil
+}
+
+// fills reports whether records of the pattern's entity have field
+func (p Pattern) fills(field string) bool {
+	return slices.Contains(recordFields[p.entity], field)
+}
+
+// String returns the pattern as it was written
+func (p Pattern) String() string {
+	return p.raw
+}
+
+// Sequence names the sequence an ID for fields is numbered in: the entity
+// and the pattern filled in except for the sequence number. A new year or
+// lot therefore starts its own count
+func (p Pattern) Sequence(fields Fields) string {
+	return p.entity + ":" + p.fill(fields, "{seq}")
+}
+
+// Format returns the ID numbered seq for fields
+func (p Pattern) Format(fields Fields, seq int64) string {
+	return p.fill(fields, fmt.Sprintf("%0*d", p.sequenceWidth(), seq))
+}
+
+// Matches reports whether id has the shape of an ID the pattern mints.
+// Client IDs of that shape are refused, since they could take a number the
+// sequence later hands out
+func (p Pattern) Matches(id string) bool {
+	return p.matcher.MatchString(id)
+}
+
+func (p Pattern) sequenceWidth() int {
+	for _, part := range p.parts {
+		if part.field == "seq" {
+			return part.width
+		}
+	}
+	return 0
+}
+
+func (p Pattern) fill(fields Fields, seq string) string {
+	id := strings.Builder{}
+	for _, part := range p.parts {
+		switch part.field {
+		case "":
+			id.WriteString(part.literal)
+		case "yyyy":
+			fmt.Fprintf(&id, "%04d", fields.Day.Year())
+		case "yy":
+			fmt.Fprintf(&id, "%02d", fields.Day.Year()%100)
+		case "mm":
+			fmt.Fprintf(&id, "%02d", int(fields.Day.Month()))
+		case "dd":
+			fmt.Fprintf(&id, "%02d", fields.Day.Day())
+		case "stock":
+			id.WriteString(fields.StockID)
+		case "counter":
+			id.WriteString(strconv.Itoa(fields.Counter))
+		case "seq":
+			id.WriteString(seq)
+		}
+	}
+	return id.String()
+}
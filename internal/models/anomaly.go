@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// StageReceived stands for the weight a lot was received at, the input of
+// the first stage that has records
+const StageReceived = "received"
+
+// Anomaly represents a row of the weight_anomalies table: a stage record
+// that took its stage's output for a lot past what the stage before it
+// allows. It is kept when plausibility checks flag writes rather than
+// block them
+type Anomaly struct {
+	ID       int64  `json:"id"`
+	StockID  string `json:"stock_id"`
+	Stage    string `json:"stage"`
+	RecordID string `json:"record_id"` // The record that crossed the limit
+	// Weight is the stage's output for the lot once the record was written
+	Weight float64 `json:"weight"`
+	// UpstreamStage is the last stage before it with records, or received
+	UpstreamStage  string    `json:"upstream_stage"`
+	UpstreamWeight float64   `json:"upstream_weight"`
+	AllowedWeight  float64   `json:"allowed_weight"`
+	CreatedAt      time.Time `json:"created_at"`
+}
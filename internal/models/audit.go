@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the kind of change an audit entry records
+type AuditAction string
+
+const (
+	AuditCreate     AuditAction = "create"
+	AuditUpdate     AuditAction = "update"
+	AuditDelete     AuditAction = "delete"
+	AuditTransition AuditAction = "transition"
+	AuditRotateKey  AuditAction = "rotate_key"
+	AuditRevoke     AuditAction = "revoke"
+	AuditCorrect    AuditAction = "correct"
+	AuditRestore    AuditAction = "restore"
+	AuditPurge      AuditAction = "purge"
+)
+
+// Audited entities, named after their tables
+const (
+	EntityStock               = "stock"
+	EntityHumidifier          = "humidifier"
+	EntityPeelingMachine      = "peeling_machine"
+	EntityColorSort           = "color_sort"
+	EntityMachineGrading      = "machine_grading"
+	EntityManualGrading       = "manual_grading"
+	EntityManualGradingInput  = "machine_grading_inputs"
+	EntityGradingSheet        = "grading_sheets"
+	EntityGraderMachineOutput = "grader_machine_outputs"
+	EntityGradingCategory     = "grading_categories"
+	EntityPiece               = "pieces"
+	EntitySizeVariation       = "size_variations"
+	EntityWeightType          = "weight_types"
+	EntityWorkforce           = "workforce"
+	EntityUser                = "users"
+	EntitySeller              = "sellers"
+	EntityDevice              = "devices"
+)
+
+// AuditEntities lists every entity whose changes are audited
+var AuditEntities = []string{
+	EntityStock,
+	EntityHumidifier,
+	EntityPeelingMachine,
+	EntityColorSort,
+	EntityMachineGrading,
+	EntityManualGrading,
+	EntityManualGradingInput,
+	EntityGradingSheet,
+	EntityGraderMachineOutput,
+	EntityGradingCategory,
+	EntityPiece,
+	EntitySizeVariation,
+	EntityWeightType,
+	EntityWorkforce,
+	EntityUser,
+	EntitySeller,
+	EntityDevice,
+}
+
+// Actor is whoever made a change: a signed in user, a device, someone
+// running an admin command or the server itself
+type Actor struct {
+	Type string `json:"type"` // user, device, cli or system
+	ID   *int64 `json:"id"`   // User or device ID
+	Name string `json:"name"`
+}
+
+// Actor types
+const (
+	ActorUser   = "user"
+	ActorDevice = "device"
+	ActorCLI    = "cli"
+	ActorSystem = "system"
+)
+
+// AuditEntry represents a row of the audit_log table. Before is null for
+// a create and After for a delete
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Actor     Actor           `json:"actor"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	Action    AuditAction     `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
@@ -9,6 +9,47 @@ type ColorSort struct {
 	WeightTypeID   int       `json:"weight_type_id" db:"weight_type_id"`
 	AcceptedWeight float64   `json:"accepted_weight" db:"accepted_weight"`
 	SortCounter    int       `json:"sort_counter" db:"sort_counter"`
+	DeviceID       *int64    `json:"device_id" db:"device_id"` // Set when a device posted the record
+	EntryType      EntryType `json:"entry_type" db:"entry_type"`
+	CorrectsID     *string   `json:"corrects_id" db:"corrects_id"`
+	Reason         string    `json:"reason,omitempty" db:"reason"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Reversal returns the entry that cancels s when it is corrected
+func (s ColorSort) Reversal(reason string) ColorSort {
+	reversal := s
+	reversal.ID = ReversalID(s.ID)
+	reversal.AcceptedWeight = -s.AcceptedWeight
+	reversal.DeviceID = nil
+	reversal.EntryType, reversal.CorrectsID, reversal.Reason = EntryReversal, &s.ID, reason
+	return reversal
+}
+
+// Replaces turns s into the replacement of original. The replacement
+// stays with the original's lot and is named after it, whatever ID was sent
+func (s *ColorSort) Replaces(original ColorSort, reason string) {
+	s.ID = ReplacementID(original.ID)
+	s.StockID = original.StockID
+	s.DeviceID = nil
+	s.EntryType, s.CorrectsID, s.Reason = EntryReplacement, &original.ID, reason
+}
+
+// Validate checks the rules s must meet to be stored as a new record or a
+// replacement. Reversals cancel a record and are not checked
+func (s ColorSort) Validate() error {
+	var problems Violations
+	problems.AtLeast("weight_type_id", int64(s.WeightTypeID), 1)
+	problems.Positive("accepted_weight", s.AcceptedWeight)
+	problems.AtLeast("sort_counter", int64(s.SortCounter), 1)
+	return problems.Err()
+}
+
+// ColorSortSummary represents the accepted weight totals for a stock and sort counter
+type ColorSortSummary struct {
+	StockID       string  `json:"stock_id"`
+	SortCounter   int     `json:"sort_counter"`
+	TotalAccepted float64 `json:"total_accepted_weight"`
+	RecordCount   int     `json:"record_count"`
+}
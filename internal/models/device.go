@@ -0,0 +1,43 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// DeviceStages are the stages a device can be allowed to record. Hand
+// grading is always recorded by a signed in grader
+var DeviceStages = []StockStatus{
+	StatusHumidifying,
+	StatusPeeling,
+	StatusColorSorting,
+	StatusMachineGrading,
+}
+
+// Device represents a shop-floor scale or tablet that posts weighings with
+// an API key instead of a user login
+type Device struct {
+	ID     int64         `json:"id"`
+	Name   string        `json:"name" binding:"required"`
+	Stages []StockStatus `json:"stages" binding:"required"`
+	// KeyPrefix is the start of the current key, enough to tell which key
+	// a device has been configured with
+	KeyPrefix            string     `json:"key_prefix"`
+	KeyHash              string     `json:"-"`
+	PreviousKeyHash      *string    `json:"-"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at"`
+	KeyRotatedAt         time.Time  `json:"key_rotated_at"`
+	RevokedAt            *time.Time `json:"revoked_at"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// CanRecord reports whether the device may post records for a stage
+func (d Device) CanRecord(stage StockStatus) bool {
+	return d.RevokedAt == nil && slices.Contains(d.Stages, stage)
+}
+
+// ValidDeviceStage reports whether devices can be allowed to record stage
+func ValidDeviceStage(stage StockStatus) bool {
+	return slices.Contains(DeviceStages, stage)
+}
@@ -33,4 +33,16 @@ func (g *GradingCategory) UnmarshalJSON(data []byte) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
+
+// MarshalJSON writes the nullable columns of GradingCategory as a value or null
+func (g GradingCategory) MarshalJSON() ([]byte, error) {
+	type Alias GradingCategory
+	return json.Marshal(&struct {
+		Alias
+		Description *string `json:"description"`
+	}{
+		Alias:       Alias(g),
+		Description: nullString(g.Description),
+	})
+}
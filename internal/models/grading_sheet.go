@@ -0,0 +1,87 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// GradingSheet represents the grading_sheets table: the grade weights one
+// worker sorted a size category of a lot into
+type GradingSheet struct {
+	ID               int64         `json:"id"`
+	StockID          string        `json:"stock_id" binding:"required"`
+	WorkerID         string        `json:"worker_id" binding:"required"`
+	SizeVariationsID int64         `json:"size_variations_id" binding:"required"`
+	Grades           GradeCategory `json:"grades"`
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// Total returns the combined weight of every grade
+func (g GradeCategory) Total() float64 {
+	return g.Whole + g.A + g.SW + g.SSW + g.TW + g.JB + g.KW
+}
+
+// Add returns the grade-by-grade sum of g and other
+func (g GradeCategory) Add(other GradeCategory) GradeCategory {
+	return GradeCategory{
+		Whole: g.Whole + other.Whole,
+		A:     g.A + other.A,
+		SW:    g.SW + other.SW,
+		SSW:   g.SSW + other.SSW,
+		TW:    g.TW + other.TW,
+		JB:    g.JB + other.JB,
+		KW:    g.KW + other.KW,
+	}
+}
+
+// Map returns g with fn applied to every grade
+func (g GradeCategory) Map(fn func(float64) float64) GradeCategory {
+	return GradeCategory{
+		Whole: fn(g.Whole),
+		A:     fn(g.A),
+		SW:    fn(g.SW),
+		SSW:   fn(g.SSW),
+		TW:    fn(g.TW),
+		JB:    fn(g.JB),
+		KW:    fn(g.KW),
+	}
+}
+
+// Validate checks that no grade is negative and that something was weighed
+func (g GradeCategory) Validate() error {
+	grades := []struct {
+		name   string
+		weight float64
+	}{
+		{"whole", g.Whole}, {"a", g.A}, {"sw", g.SW}, {"ssw", g.SSW},
+		{"tw", g.TW}, {"jb", g.JB}, {"kw", g.KW},
+	}
+	for _, grade := range grades {
+		if grade.weight < 0 {
+			return fmt.Errorf("grades.%s cannot be negative", grade.name)
+		}
+	}
+	if g.Total() == 0 {
+		return fmt.Errorf("a grading sheet needs at least one grade weight")
+	}
+	return nil
+}
+
+// GradeBreakdown is the weight and share of each grade across a set of
+// grading sheets
+type GradeBreakdown struct {
+	SizeVariationsID *int64        `json:"size_variations_id,omitempty"` // Absent on the lot total
+	Sheets           int           `json:"sheets"`
+	TotalWeight      float64       `json:"total_weight"`
+	Weights          GradeCategory `json:"weights"`
+	Percent          GradeCategory `json:"percent"`
+}
+
+// GradeDistribution is how a lot's graded weight split across the grades,
+// overall and per size category
+type GradeDistribution struct {
+	StockID string           `json:"stock_id"`
+	Total   GradeBreakdown   `json:"total"`
+	Sizes   []GradeBreakdown `json:"sizes"`
+}
@@ -4,9 +4,41 @@ import "time"
 
 // User represents the user data model
 type Humidifier struct {
-	ID        string    `json:"id"`
-	StockID   string    `json:"stock_id"`
-	Weight    float32   `json:"weight"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         string    `json:"id"`
+	StockID    string    `json:"stock_id"`
+	Weight     float32   `json:"weight"`
+	DeviceID   *int64    `json:"device_id"` // Set when a device posted the record
+	EntryType  EntryType `json:"entry_type"`
+	CorrectsID *string   `json:"corrects_id"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Reversal returns the entry that cancels h when it is corrected
+func (h Humidifier) Reversal(reason string) Humidifier {
+	reversal := h
+	reversal.ID = ReversalID(h.ID)
+	reversal.Weight = -h.Weight
+	reversal.DeviceID = nil
+	reversal.EntryType, reversal.CorrectsID, reversal.Reason = EntryReversal, &h.ID, reason
+	return reversal
+}
+
+// Replaces turns h into the replacement of original. The replacement
+// stays with the original's lot and is named after it, whatever ID was sent
+func (h *Humidifier) Replaces(original Humidifier, reason string) {
+	h.ID = ReplacementID(original.ID)
+	h.StockID = original.StockID
+	h.DeviceID = nil
+	h.EntryType, h.CorrectsID, h.Reason = EntryReplacement, &original.ID, reason
+}
+
+// Validate checks the rules h must meet to be stored as a new record or a
+// replacement. Reversals cancel a record and are not checked
+func (h Humidifier) Validate() error {
+	var problems Violations
+	problems.Required("stock_id", h.StockID)
+	problems.Positive("weight", float64(h.Weight))
+	return problems.Err()
 }
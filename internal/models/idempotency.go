@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// IdempotencyKey is a create request sent with an Idempotency-Key header,
+// kept with its response so a retry can be answered without creating the
+// record again
+type IdempotencyKey struct {
+	// Caller is who sent the request, e.g. user:4 or device:2. Keys are only
+	// unique per caller
+	Caller      string
+	Key         string
+	RequestHash string
+	// StatusCode is 0 while the request is still being handled
+	StatusCode int
+	Response   []byte
+	CreatedAt  time.Time
+}
+
+// Completed reports whether the request finished and its response is kept
+func (k IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,43 @@
+package models
+
+// EntryType tells a stage weighing as first recorded from the entries
+// posted to correct it. Stage records are never edited: a correction adds
+// a reversal that cancels the record and, unless the record is only being
+// voided, a replacement that takes its place
+type EntryType string
+
+const (
+	EntryOriginal    EntryType = "original"
+	EntryReversal    EntryType = "reversal"
+	EntryReplacement EntryType = "replacement"
+)
+
+// MaxCorrectionReason is the longest reason a correction may carry
+const MaxCorrectionReason = 255
+
+// Correctable reports whether an entry of type t can be corrected. Only a
+// reversal can't; to undo one, correct the replacement instead
+func (t EntryType) Correctable() bool {
+	return t != EntryReversal
+}
+
+// Sign is what an entry of type t adds to a record count, so counts cancel
+// out the same way the weights of a reversal do
+func (t EntryType) Sign() int {
+	if t == EntryReversal {
+		return -1
+	}
+	return 1
+}
+
+// ReversalID returns the ID of the entry reversing the record id
+func ReversalID(id string) string {
+	return id + "-R"
+}
+
+// ReplacementID returns the ID given to the replacement of the record id.
+// Replacements can't be named by the client, so they never take an ID the
+// server will mint later and always lead back to the record they correct
+func ReplacementID(id string) string {
+	return id + "-C"
+}
@@ -14,6 +14,10 @@ type MachineGrading struct {
 	SizeVariationsID  sql.NullInt64  `json:"size_variations_id,omitempty"`
 	PiecesID          sql.NullInt64  `json:"pieces_id,omitempty"`
 	Weight            float64        `json:"weight"`
+	DeviceID          *int64         `json:"device_id"` // Set when a device posted the record
+	EntryType         EntryType      `json:"entry_type"`
+	CorrectsID        *string        `json:"corrects_id"`
+	Reason            string         `json:"reason,omitempty"`
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 }
@@ -39,3 +43,52 @@ func (m *MachineGrading) UnmarshalJSON(data []byte) error {
 	}
 	return nil
 }
+
+// MarshalJSON writes the nullable columns of MachineGrading as a value or null
+func (m MachineGrading) MarshalJSON() ([]byte, error) {
+	type Alias MachineGrading
+	return json.Marshal(&struct {
+		Alias
+		SizeVariationsID *int64 `json:"size_variations_id,omitempty"`
+		PiecesID         *int64 `json:"pieces_id,omitempty"`
+	}{
+		Alias:            Alias(m),
+		SizeVariationsID: nullInt64(m.SizeVariationsID),
+		PiecesID:         nullInt64(m.PiecesID),
+	})
+}
+
+// Reversal returns the entry that cancels m when it is corrected
+func (m MachineGrading) Reversal(reason string) MachineGrading {
+	reversal := m
+	reversal.ID = ReversalID(m.ID)
+	reversal.Weight = -m.Weight
+	reversal.DeviceID = nil
+	reversal.EntryType, reversal.CorrectsID, reversal.Reason = EntryReversal, &m.ID, reason
+	return reversal
+}
+
+// Replaces turns m into the replacement of original. The replacement
+// stays with the original's lot and is named after it, whatever ID was sent
+func (m *MachineGrading) Replaces(original MachineGrading, reason string) {
+	m.ID = ReplacementID(original.ID)
+	m.StockID = original.StockID
+	m.DeviceID = nil
+	m.EntryType, m.CorrectsID, m.Reason = EntryReplacement, &original.ID, reason
+}
+
+// Validate checks the rules m must meet to be stored as a new record or a
+// replacement. Reversals cancel a record and are not checked
+func (m MachineGrading) Validate() error {
+	var problems Violations
+	problems.Required("color_sort_id", m.ColorSortID)
+	problems.Positive("weight", m.Weight)
+	return problems.Err()
+}
+
+// MachineGradingSummary represents the graded weight totals for a stock
+type MachineGradingSummary struct {
+	StockID     string  `json:"stock_id"`
+	TotalWeight float64 `json:"total_weight"`
+	RecordCount int     `json:"record_count"`
+}
@@ -27,6 +27,9 @@ type ManualGrading struct {
 	PieceID              sql.NullInt64  `json:"piece_id"`
 	Weight               int64          `json:"weight"`
 	WorkerID             string         `json:"worker_id"`
+	EntryType            EntryType      `json:"entry_type"`
+	CorrectsID           *string        `json:"corrects_id"`
+	Reason               string         `json:"reason,omitempty"`
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
 }
@@ -60,3 +63,46 @@ func (m *ManualGrading) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// MarshalJSON writes the nullable columns of ManualGrading as a value or null
+func (m ManualGrading) MarshalJSON() ([]byte, error) {
+	type Alias ManualGrading
+	return json.Marshal(&struct {
+		Alias
+		CategoryID *int64 `json:"category_id"`
+		PieceID    *int64 `json:"piece_id"`
+	}{
+		Alias:      Alias(m),
+		CategoryID: nullInt64(m.CategoryID),
+		PieceID:    nullInt64(m.PieceID),
+	})
+}
+
+// Reversal returns the entry that cancels m when it is corrected
+func (m ManualGrading) Reversal(reason string) ManualGrading {
+	reversal := m
+	reversal.ID = ReversalID(m.ID)
+	reversal.Weight = -m.Weight
+	reversal.EntryType, reversal.CorrectsID, reversal.Reason = EntryReversal, &m.ID, reason
+	return reversal
+}
+
+// Replaces turns m into the replacement of original. The replacement
+// stays with the original's lot and is named after it, whatever ID was sent
+func (m *ManualGrading) Replaces(original ManualGrading, reason string) {
+	m.ID = ReplacementID(original.ID)
+	m.StockID = original.StockID
+	m.EntryType, m.CorrectsID, m.Reason = EntryReplacement, &original.ID, reason
+}
+
+// Validate checks the rules m must meet to be stored as a new record or a
+// replacement. Reversals cancel a record and are not checked
+func (m ManualGrading) Validate() error {
+	var problems Violations
+	problems.Required("grader_machine_outputs_id", m.GraderMachineOutputsID)
+	problems.Required("stock_id", m.StockID)
+	problems.Required("worker_id", m.WorkerID)
+	problems.AtLeast("size_id", m.SizeID, 1)
+	problems.Positive("weight", float64(m.Weight))
+	return problems.Err()
+}
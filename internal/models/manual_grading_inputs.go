@@ -13,6 +13,9 @@ type ManualGradingInput struct {
 	WorkerID         string         `json:"worker_id"`
 	SizeVariationsID sql.NullInt64  `json:"size_variations_id,omitempty"`
 	Weight           float64        `json:"weight"`
+	EntryType        EntryType      `json:"entry_type"`
+	CorrectsID       *int           `json:"corrects_id"`
+	Reason           string         `json:"reason,omitempty"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 }
@@ -33,4 +36,44 @@ func (m *ManualGradingInput) UnmarshalJSON(data []byte) error {
 		m.SizeVariationsID = sql.NullInt64{Int64: *aux.SizeVariationsID, Valid: true}
 	}
 	return nil
-} 
\ No newline at end of file
+}
+
+// MarshalJSON writes the nullable columns of ManualGradingInput as a value or null
+func (m ManualGradingInput) MarshalJSON() ([]byte, error) {
+	type Alias ManualGradingInput
+	return json.Marshal(&struct {
+		Alias
+		SizeVariationsID *int64 `json:"size_variations_id,omitempty"`
+	}{
+		Alias:            Alias(m),
+		SizeVariationsID: nullInt64(m.SizeVariationsID),
+	})
+}
+
+// Reversal returns the entry that cancels m when it is corrected. Its ID
+// is assigned when it is stored
+func (m ManualGradingInput) Reversal(reason string) ManualGradingInput {
+	reversal := m
+	reversal.ID = 0
+	reversal.Weight = -m.Weight
+	reversal.EntryType, reversal.CorrectsID, reversal.Reason = EntryReversal, &m.ID, reason
+	return reversal
+}
+
+// Replaces turns m into the replacement of original. The replacement
+// stays with the original's lot and is given a new ID when it is stored
+func (m *ManualGradingInput) Replaces(original ManualGradingInput, reason string) {
+	m.ID = 0
+	m.StockID = original.StockID
+	m.EntryType, m.CorrectsID, m.Reason = EntryReplacement, &original.ID, reason
+}
+
+// Validate checks the rules m must meet to be stored as a new record or a
+// replacement. Reversals cancel a record and are not checked
+func (m ManualGradingInput) Validate() error {
+	var problems Violations
+	problems.Required("stock_id", m.StockID)
+	problems.Required("worker_id", m.WorkerID)
+	problems.Positive("weight", m.Weight)
+	return problems.Err()
+}
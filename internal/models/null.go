@@ -0,0 +1,20 @@
+package models
+
+import "database/sql"
+
+// nullInt64 returns v as a pointer, nil when it is NULL, so it is written
+// to JSON as a number or null
+func nullInt64(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+// nullString returns v as a pointer, nil when it is NULL
+func nullString(v sql.NullString) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
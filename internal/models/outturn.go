@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// GradeWeight is the manual grading weight of one lot in one grading
+// category. CategoryCode is empty for records graded without a category
+type GradeWeight struct {
+	StockID      string
+	CategoryCode string
+	Weight       float64
+	Records      int
+}
+
+// LotOutturn compares the outturn a seller promised for a lot with the one
+// its graded kernels realised. Outturns are lbs of kernel per 80 kg bag
+type LotOutturn struct {
+	StockID         string      `json:"stock_id"`
+	SellerName      string      `json:"seller_name"`
+	OriginCountry   string      `json:"origin_country"`
+	Status          StockStatus `json:"status"`
+	Date            time.Time   `json:"date"`
+	RawWeight       float64     `json:"raw_weight"`
+	KernelWeight    float64     `json:"kernel_weight"`
+	GradingRecords  int         `json:"grading_records"`
+	PromisedOutturn *float64    `json:"promised_outturn"`
+	ActualOutturn   *float64    `json:"actual_outturn"` // Null until the lot has been graded
+	Variance        *float64    `json:"variance"`       // Actual minus promised
+}
+
+// OutturnGroup totals the graded lots that share a seller or origin country.
+// Outturns are weighted by raw weight, and the promise is compared only with
+// the lots that carried one
+type OutturnGroup struct {
+	Key             string   `json:"key"`
+	Lots            int      `json:"lots"`
+	RawWeight       float64  `json:"raw_weight"`
+	KernelWeight    float64  `json:"kernel_weight"`
+	PromisedOutturn *float64 `json:"promised_outturn"`
+	ActualOutturn   *float64 `json:"actual_outturn"`
+	Variance        *float64 `json:"variance"`
+}
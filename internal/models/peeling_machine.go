@@ -8,6 +8,39 @@ type PeelingMachine struct {
 	StockID      *string   `json:"stock_id,omitempty" db:"stock_id"`
 	WeightTypeID int       `json:"weight_type_id" db:"weight_type_id"`
 	Weight       float64   `json:"weight" db:"weight"`
+	DeviceID     *int64    `json:"device_id" db:"device_id"` // Set when a device posted the record
+	EntryType    EntryType `json:"entry_type" db:"entry_type"`
+	CorrectsID   *string   `json:"corrects_id" db:"corrects_id"`
+	Reason       string    `json:"reason,omitempty" db:"reason"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// Reversal returns the entry that cancels m when it is corrected
+func (m PeelingMachine) Reversal(reason string) PeelingMachine {
+	reversal := m
+	reversal.ID = ReversalID(m.ID)
+	reversal.Weight = -m.Weight
+	reversal.DeviceID = nil
+	reversal.EntryType, reversal.CorrectsID, reversal.Reason = EntryReversal, &m.ID, reason
+	return reversal
+}
+
+// Replaces turns m into the replacement of original. The replacement
+// stays with the original's lot and is named after it, whatever ID was sent
+func (m *PeelingMachine) Replaces(original PeelingMachine, reason string) {
+	m.ID = ReplacementID(original.ID)
+	m.StockID = original.StockID
+	m.DeviceID = nil
+	m.EntryType, m.CorrectsID, m.Reason = EntryReplacement, &original.ID, reason
+}
+
+// Validate checks the rules m must meet to be stored as a new record or a
+// replacement. Reversals cancel a record and are not checked
+func (m PeelingMachine) Validate() error {
+	var problems Violations
+	problems.Required("humidifier_id", m.HumidifierID)
+	problems.AtLeast("weight_type_id", int64(m.WeightTypeID), 1)
+	problems.Positive("weight", m.Weight)
+	return problems.Err()
+}
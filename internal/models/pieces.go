@@ -1,10 +1,45 @@
 package models
 
-import "database/sql"
+import (
+	"database/sql"
+	"encoding/json"
+)
 
 // MachineGrading represents the machine_grading table in the database
 type Pieces struct {
 	PieceID                int    	 `json:"piece_id"`
 	PieceCode              string    `json:"piece_code"`
 	Description			   sql.NullString 	 `json:"description,omitempty"`
-}
\ No newline at end of file
+}
+
+// UnmarshalJSON reads the description as a string or null
+func (p *Pieces) UnmarshalJSON(data []byte) error {
+	type Alias Pieces
+	aux := &struct {
+		Description *string `json:"description,omitempty"`
+		*Alias
+	}{
+		Alias: (*Alias)(p),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	p.Description = sql.NullString{}
+	if aux.Description != nil {
+		p.Description = sql.NullString{String: *aux.Description, Valid: true}
+	}
+	return nil
+}
+
+// MarshalJSON writes the description as a string, leaving it out when it
+// is NULL
+func (p Pieces) MarshalJSON() ([]byte, error) {
+	type Alias Pieces
+	return json.Marshal(&struct {
+		Alias
+		Description *string `json:"description,omitempty"`
+	}{
+		Alias:       Alias(p),
+		Description: nullString(p.Description),
+	})
+}
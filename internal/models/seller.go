@@ -0,0 +1,66 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Seller represents a supplier of raw nut lots
+type Seller struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name" binding:"required"`
+	ContactName string    `json:"contact_name"`
+	Phone       string    `json:"phone"`
+	Email       string    `json:"email"`
+	GSTIN       *string   `json:"gstin"`   // Null for sellers outside India
+	Country     string    `json:"country"` // ISO 3166-1 alpha-2 code
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// gstinPattern matches a GST identification number: state code, PAN,
+// entity number, the letter Z and a check character
+var gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+// SellerName trims a seller name and collapses the whitespace inside it
+func SellerName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// SellerNameKey returns the form seller names are compared in, so names
+// that differ only in case or spacing match
+func SellerNameKey(name string) string {
+	return strings.ToLower(SellerName(name))
+}
+
+// ValidGSTIN reports whether gstin is a well-formed GST identification number
+func ValidGSTIN(gstin string) bool {
+	return gstinPattern.MatchString(gstin)
+}
+
+// SellerScorecard summarises the lots bought from a seller. Yield and
+// outturn cover only the lots that have reached manual grading
+type SellerScorecard struct {
+	SellerID           int64        `json:"seller_id"`
+	SellerName         string       `json:"seller_name"`
+	Country            string       `json:"country"`
+	Lots               int          `json:"lots"`
+	WeightReceived     float64      `json:"weight_received"`
+	GradedLots         int          `json:"graded_lots"`
+	GradedWeight       float64      `json:"graded_weight"` // Raw weight of the graded lots
+	KernelWeight       float64      `json:"kernel_weight"`
+	KernelYieldPercent *float64     `json:"kernel_yield_percent"`
+	PromisedOutturn    *float64     `json:"promised_outturn"`
+	ActualOutturn      *float64     `json:"actual_outturn"`
+	Variance           *float64     `json:"variance"`
+	GradeMix           []GradeShare `json:"grade_mix"`
+}
+
+// GradeShare is the part of a seller's manual grading weight that fell in
+// one grading category
+type GradeShare struct {
+	Category string   `json:"category"` // Empty for records graded without a category
+	Weight   float64  `json:"weight"`
+	Percent  *float64 `json:"percent"`
+}
@@ -4,11 +4,14 @@ import "time"
 
 // User represents the user data model
 type Stock struct {
-	StockID       string    `json:"stock_id"`
-	SellerName    string    `json:"seller_name"`
-	OriginCountry string    `json:"origin_country"`
-	Weight        float32   `json:"weight"`
-	Date          time.Time `json:"date"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	StockID         string      `json:"stock_id"`
+	SellerID        *int64      `json:"seller_id"`
+	SellerName      string      `json:"seller_name"` // Copied from the seller record
+	OriginCountry   string      `json:"origin_country"`
+	Weight          float32     `json:"weight"`
+	PromisedOutturn *float64    `json:"promised_outturn"` // lbs of kernel per 80 kg bag
+	Date            time.Time   `json:"date"`
+	Status          StockStatus `json:"status"` // Changed through transitions only
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
 }
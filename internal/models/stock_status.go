@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// StockStatus is the processing stage a stock lot has reached
+type StockStatus string
+
+const (
+	StatusReceived       StockStatus = "received"
+	StatusHumidifying    StockStatus = "humidifying"
+	StatusPeeling        StockStatus = "peeling"
+	StatusColorSorting   StockStatus = "color_sorting"
+	StatusMachineGrading StockStatus = "machine_grading"
+	StatusManualGrading  StockStatus = "manual_grading"
+	StatusPacked         StockStatus = "packed"
+	StatusClosed         StockStatus = "closed"
+)
+
+// StockLifecycle lists the statuses in the order a lot moves through them
+var StockLifecycle = []StockStatus{
+	StatusReceived,
+	StatusHumidifying,
+	StatusPeeling,
+	StatusColorSorting,
+	StatusMachineGrading,
+	StatusManualGrading,
+	StatusPacked,
+	StatusClosed,
+}
+
+// Valid reports whether s is a known status
+func (s StockStatus) Valid() bool {
+	for _, status := range StockLifecycle {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the status that follows s, or false once a lot is closed
+func (s StockStatus) Next() (StockStatus, bool) {
+	for i, status := range StockLifecycle {
+		if status == s && i+1 < len(StockLifecycle) {
+			return StockLifecycle[i+1], true
+		}
+	}
+	return "", false
+}
+
+// CanTransitionTo reports whether a lot may move from s to next. Lots only
+// ever advance one stage at a time
+func (s StockStatus) CanTransitionTo(next StockStatus) bool {
+	following, ok := s.Next()
+	return ok && following == next
+}
+
+// StockTransition represents a row of the stock_transitions history table
+type StockTransition struct {
+	ID         int64       `json:"id"`
+	StockID    string      `json:"stock_id"`
+	FromStatus StockStatus `json:"from_status"`
+	ToStatus   StockStatus `json:"to_status"`
+	Note       string      `json:"note,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// TrashItem is a deleted record waiting in the trash. Deleting a stock lot,
+// master record or stage record only marks it; it can be restored until it
+// is purged
+type TrashItem struct {
+	Entity    string    `json:"entity"`
+	ID        string    `json:"id"`
+	Label     string    `json:"label"` // Name or code to recognise the record by
+	DeletedAt time.Time `json:"deleted_at"`
+	DeletedBy string    `json:"deleted_by"`
+}
+
+// TrashEntities lists the entities that go to the trash when deleted
+var TrashEntities = []string{
+	EntityStock,
+	EntitySeller,
+	EntityGradingSheet,
+	EntityPiece,
+	EntitySizeVariation,
+	EntityGraderMachineOutput,
+	EntityWeightType,
+	EntityWorkforce,
+	EntityGradingCategory,
+	EntityHumidifier,
+	EntityPeelingMachine,
+	EntityColorSort,
+	EntityMachineGrading,
+	EntityManualGradingInput,
+	EntityManualGrading,
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Role decides which routes a user may call
+type Role string
+
+const (
+	// RoleOperator runs the machines and records their weighings
+	RoleOperator Role = "operator"
+	// RoleGrader records hand grading on the grading tables
+	RoleGrader Role = "grader"
+	// RoleSupervisor corrects and removes what operators and graders record
+	RoleSupervisor Role = "supervisor"
+	// RoleAdmin also maintains the reference tables and sellers
+	RoleAdmin Role = "admin"
+)
+
+// Roles lists every role from least to most privileged
+var Roles = []Role{RoleOperator, RoleGrader, RoleSupervisor, RoleAdmin}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	for _, role := range Roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// User represents an account that can sign in to manage the plant data
+type User struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         Role      `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
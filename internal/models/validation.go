@@ -0,0 +1,75 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FieldError is a field of a record that breaks one of its rules
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every field of a record that breaks a rule, so
+// they can all be fixed at once
+type ValidationError []FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e))
+	for i, field := range e {
+		messages[i] = field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Violations collects the rules a record breaks
+type Violations struct {
+	errs ValidationError
+}
+
+// Add records that field breaks rule
+func (v *Violations) Add(field, rule, format string, args ...any) {
+	v.errs = append(v.errs, FieldError{Field: field, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// Required checks that field was given
+func (v *Violations) Required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, "required", "%s is required", field)
+	}
+}
+
+// Positive checks that the weight in field is more than zero
+func (v *Violations) Positive(field string, value float64) {
+	if value <= 0 {
+		v.Add(field, "positive", "%s must be greater than 0", field)
+	}
+}
+
+// AtLeast checks that field is min or more
+func (v *Violations) AtLeast(field string, value, min int64) {
+	if value < min {
+		v.Add(field, "min", "%s must be at least %d", field, min)
+	}
+}
+
+// Include adds the rules listed by err, a ValidationError returned by a
+// model's Validate
+func (v *Violations) Include(err error) {
+	var fields ValidationError
+	if errors.As(err, &fields) {
+		v.errs = append(v.errs, fields...)
+	}
+}
+
+// Err returns the rules broken as a ValidationError, or nil when there are
+// none
+func (v *Violations) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
@@ -4,3 +4,9 @@ type WeightTypes struct {
 	ID   string `json:"id"`
 	Type string `json:"type"`
 }
+
+// WeightTypeUsage represents a weight type with the number of records using it
+type WeightTypeUsage struct {
+	WeightTypes
+	UsageCount int `json:"usage_count"`
+}
@@ -0,0 +1,42 @@
+package models
+
+// StageTotal is the summed weight of one stage's records for a lot
+type StageTotal struct {
+	Weight  float64 `json:"weight"`
+	Records int     `json:"records"`
+}
+
+// StockStageTotals holds the totals of every processing stage for a lot
+type StockStageTotals struct {
+	StockID              string
+	Humidifier           StageTotal
+	PeelingMachine       StageTotal
+	ColorSort            StageTotal // accepted weight over every sort counter
+	MachineGrading       StageTotal
+	MachineGradingInputs StageTotal
+	ManualGrading        StageTotal
+}
+
+// YieldStage is one step of a mass-balance report. Loss is negative when a
+// stage gains weight, as humidifying does, and null while the stage has no
+// records
+type YieldStage struct {
+	Stage       string   `json:"stage"`
+	Records     int      `json:"records"`
+	WeightIn    float64  `json:"weight_in"`
+	WeightOut   float64  `json:"weight_out"`
+	Loss        *float64 `json:"loss"`
+	LossPercent *float64 `json:"loss_percent"`
+}
+
+// YieldReport is the mass balance of a stock lot from receipt to manual
+// grading
+type YieldReport struct {
+	StockID        string       `json:"stock_id"`
+	Status         StockStatus  `json:"status"`
+	ReceivedWeight float64      `json:"received_weight"`
+	FinalStage     string       `json:"final_stage,omitempty"` // Last stage with records
+	FinalWeight    float64      `json:"final_weight"`
+	YieldPercent   *float64     `json:"yield_percent"`
+	Stages         []YieldStage `json:"stages"`
+}
@@ -0,0 +1,56 @@
+// Package plant describes how the processing plant keeps time
+package plant
+
+import (
+	"fmt"
+	"time"
+)
+
+// Calendar maps instants onto the plant's working days. A working day runs
+// from DayStart on one calendar date to DayStart on the next, in Location
+type Calendar struct {
+	Location *time.Location
+	DayStart time.Duration
+}
+
+// UTC is the calendar used when no plant time zone has been configured
+var UTC = Calendar{Location: time.UTC}
+
+// StartOf returns the instant the working day on the given date begins
+func (c Calendar) StartOf(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, c.location()).Add(c.DayStart)
+}
+
+// DayOf returns the start of the working day containing t
+func (c Calendar) DayOf(t time.Time) time.Time {
+	local := t.In(c.location()).Add(-c.DayStart)
+	return c.StartOf(local.Year(), local.Month(), local.Day())
+}
+
+// Day returns the bounds of a working day, start inclusive and end exclusive.
+// The value may be a YYYY-MM-DD date, "today" or "yesterday"
+func (c Calendar) Day(value string, now time.Time) (time.Time, time.Time, error) {
+	var start time.Time
+	switch value {
+	case "today":
+		start = c.DayOf(now)
+	case "yesterday":
+		start = c.DayOf(now)
+		start = c.StartOf(start.Year(), start.Month(), start.Day()-1)
+	default:
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("expected YYYY-MM-DD, today or yesterday")
+		}
+		start = c.StartOf(date.Year(), date.Month(), date.Day())
+	}
+	local := start.In(c.location())
+	return start, c.StartOf(local.Year(), local.Month(), local.Day()+1), nil
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
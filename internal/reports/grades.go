@@ -0,0 +1,49 @@
+package reports
+
+import (
+	"healing_photons/internal/models"
+	"sort"
+)
+
+// GradeDistribution splits a lot's grading sheets into the share of each
+// grade, for the whole lot and for each size category
+func GradeDistribution(stockID string, sheets []models.GradingSheet) models.GradeDistribution {
+	distribution := models.GradeDistribution{StockID: stockID, Sizes: []models.GradeBreakdown{}}
+
+	bySize := make(map[int64]*models.GradeBreakdown)
+	for _, sheet := range sheets {
+		size, ok := bySize[sheet.SizeVariationsID]
+		if !ok {
+			sizeID := sheet.SizeVariationsID
+			size = &models.GradeBreakdown{SizeVariationsID: &sizeID}
+			bySize[sizeID] = size
+		}
+		size.Sheets++
+		size.Weights = size.Weights.Add(sheet.Grades)
+		distribution.Total.Sheets++
+		distribution.Total.Weights = distribution.Total.Weights.Add(sheet.Grades)
+	}
+
+	for _, size := range bySize {
+		distribution.Sizes = append(distribution.Sizes, breakdown(*size))
+	}
+	sort.Slice(distribution.Sizes, func(i, j int) bool {
+		return *distribution.Sizes[i].SizeVariationsID < *distribution.Sizes[j].SizeVariationsID
+	})
+	distribution.Total = breakdown(distribution.Total)
+	return distribution
+}
+
+// breakdown rounds the summed weights and works out each grade's share
+func breakdown(b models.GradeBreakdown) models.GradeBreakdown {
+	total := b.Weights.Total()
+	b.TotalWeight = roundWeight(total)
+	b.Weights = b.Weights.Map(roundWeight)
+	b.Percent = b.Weights.Map(func(weight float64) float64 {
+		if share := percent(weight, total); share != nil {
+			return *share
+		}
+		return 0
+	})
+	return b
+}
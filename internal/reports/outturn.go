@@ -0,0 +1,112 @@
+package reports
+
+import (
+	"healing_photons/internal/models"
+	"sort"
+)
+
+const (
+	// BagWeight is the weight in kg of the raw nut bag outturn is quoted on
+	BagWeight = 80.0
+	// HuskCategory is the grading category for husk, which is weighed at
+	// manual grading but is not kernel
+	HuskCategory = "KW"
+	// poundsPerKilogram converts kernel weight into the lbs outturn uses
+	poundsPerKilogram = 2.20462262
+)
+
+// KernelWeights totals the graded kernel of each lot, leaving out husk.
+// Lots with no grading records are absent from the map
+func KernelWeights(grades []models.GradeWeight) map[string]models.StageTotal {
+	kernels := make(map[string]models.StageTotal)
+	for _, grade := range grades {
+		if grade.CategoryCode == HuskCategory {
+			continue
+		}
+		total := kernels[grade.StockID]
+		total.Weight += grade.Weight
+		total.Records += grade.Records
+		kernels[grade.StockID] = total
+	}
+	return kernels
+}
+
+// KernelOutturn returns the lbs of kernel a bag of raw nuts yields, or nil
+// when there is no raw weight to measure against
+func KernelOutturn(kernelWeight, rawWeight float64) *float64 {
+	if rawWeight <= 0 {
+		return nil
+	}
+	return round2(kernelWeight / rawWeight * BagWeight * poundsPerKilogram)
+}
+
+// Outturn works out the realised outturn of a lot from its graded kernels.
+// Lots without grading records have no actual outturn yet
+func Outturn(stock models.Stock, kernel models.StageTotal) models.LotOutturn {
+	lot := models.LotOutturn{
+		StockID:         stock.StockID,
+		SellerName:      stock.SellerName,
+		OriginCountry:   stock.OriginCountry,
+		Status:          stock.Status,
+		Date:            stock.Date,
+		RawWeight:       float32Weight(stock.Weight),
+		KernelWeight:    roundWeight(kernel.Weight),
+		GradingRecords:  kernel.Records,
+		PromisedOutturn: stock.PromisedOutturn,
+	}
+	if kernel.Records > 0 {
+		lot.ActualOutturn = KernelOutturn(lot.KernelWeight, lot.RawWeight)
+	}
+	if lot.ActualOutturn != nil && lot.PromisedOutturn != nil {
+		lot.Variance = round2(*lot.ActualOutturn - *lot.PromisedOutturn)
+	}
+	return lot
+}
+
+// GroupOutturns totals the graded lots by the key each lot maps to, ordered
+// by key
+func GroupOutturns(lots []models.LotOutturn, key func(models.LotOutturn) string) []models.OutturnGroup {
+	type totals struct {
+		group models.OutturnGroup
+		// Raw and kernel weight of the lots that carried a promise, and the
+		// promise weighted by raw weight
+		promisedRaw, promisedKernel, promisedBags float64
+	}
+
+	byKey := make(map[string]*totals)
+	for _, lot := range lots {
+		if lot.ActualOutturn == nil {
+			continue
+		}
+		k := key(lot)
+		t, ok := byKey[k]
+		if !ok {
+			t = &totals{group: models.OutturnGroup{Key: k}}
+			byKey[k] = t
+		}
+		t.group.Lots++
+		t.group.RawWeight += lot.RawWeight
+		t.group.KernelWeight += lot.KernelWeight
+		if lot.PromisedOutturn != nil {
+			t.promisedRaw += lot.RawWeight
+			t.promisedKernel += lot.KernelWeight
+			t.promisedBags += *lot.PromisedOutturn * lot.RawWeight
+		}
+	}
+
+	groups := make([]models.OutturnGroup, 0, len(byKey))
+	for _, t := range byKey {
+		group := t.group
+		group.RawWeight = roundWeight(group.RawWeight)
+		group.KernelWeight = roundWeight(group.KernelWeight)
+		group.ActualOutturn = KernelOutturn(group.KernelWeight, group.RawWeight)
+		if t.promisedRaw > 0 {
+			group.PromisedOutturn = round2(t.promisedBags / t.promisedRaw)
+			realised := KernelOutturn(t.promisedKernel, t.promisedRaw)
+			group.Variance = round2(*realised - *group.PromisedOutturn)
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups
+}
@@ -0,0 +1,79 @@
+package reports
+
+import (
+	"fmt"
+	"healing_photons/internal/models"
+)
+
+// What plausibility checks do with a write that fails them
+const (
+	// PlausibilityOff skips the checks
+	PlausibilityOff = "off"
+	// PlausibilityFlag keeps the write and records an anomaly for it
+	PlausibilityFlag = "flag"
+	// PlausibilityBlock refuses the write
+	PlausibilityBlock = "block"
+)
+
+// Plausibility says how far a stage's output for a lot may run above its
+// input. A stage's output is the total of its records; its input is the
+// output of the last stage before it with records, or the weight the lot
+// was received at
+type Plausibility struct {
+	Mode string
+	// MoistureGain is the percentage the first stage with records may weigh
+	// above the received weight, since humidifying adds water to the nuts
+	MoistureGain float64
+	// Tolerance is the percentage any later stage may weigh above its input,
+	// allowing for the scales
+	Tolerance float64
+}
+
+// DefaultPlausibility flags writes, allowing for 10% moisture gain and 1%
+// between scales
+func DefaultPlausibility() Plausibility {
+	return Plausibility{Mode: PlausibilityFlag, MoistureGain: 10, Tolerance: 1}
+}
+
+// ParsePlausibilityMode checks that mode is one the checks understand
+func ParsePlausibilityMode(mode string) (string, error) {
+	switch mode {
+	case PlausibilityOff, PlausibilityFlag, PlausibilityBlock:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown mode %q, expected %s, %s or %s", mode, PlausibilityOff, PlausibilityFlag, PlausibilityBlock)
+}
+
+// Check compares stage's output for the lot with its input, returning the
+// anomaly when the output is more than the input allows. Stages with no
+// records, and stages whose input is zero, always pass
+func (p Plausibility) Check(stock models.Stock, totals models.StockStageTotals, stage string) (models.Anomaly, bool) {
+	upstream, in := models.StageReceived, float32Weight(stock.Weight)
+	for _, step := range stageChain(totals) {
+		if step.stage != stage {
+			if step.total.Records > 0 {
+				upstream, in = step.stage, roundWeight(step.total.Weight)
+			}
+			continue
+		}
+
+		out := roundWeight(step.total.Weight)
+		margin := p.Tolerance
+		if upstream == models.StageReceived {
+			margin = p.MoistureGain
+		}
+		allowed := roundWeight(in * (1 + margin/100))
+		if step.total.Records <= 0 || in <= 0 || out <= allowed {
+			return models.Anomaly{}, false
+		}
+		return models.Anomaly{
+			StockID:        stock.StockID,
+			Stage:          stage,
+			Weight:         out,
+			UpstreamStage:  upstream,
+			UpstreamWeight: in,
+			AllowedWeight:  allowed,
+		}, true
+	}
+	return models.Anomaly{}, false
+}
@@ -0,0 +1,61 @@
+package reports
+
+import (
+	"healing_photons/internal/models"
+	"sort"
+)
+
+// Scorecard summarises a seller's lots. grades may cover other sellers'
+// lots too; only those of stocks are counted
+func Scorecard(seller models.Seller, stocks []models.Stock, grades []models.GradeWeight) models.SellerScorecard {
+	card := models.SellerScorecard{
+		SellerID:   seller.ID,
+		SellerName: seller.Name,
+		Country:    seller.Country,
+		Lots:       len(stocks),
+		GradeMix:   []models.GradeShare{},
+	}
+
+	owned := make(map[string]bool, len(stocks))
+	for _, stock := range stocks {
+		owned[stock.StockID] = true
+		card.WeightReceived += float32Weight(stock.Weight)
+	}
+	card.WeightReceived = roundWeight(card.WeightReceived)
+
+	kernels := KernelWeights(grades)
+	lots := make([]models.LotOutturn, len(stocks))
+	for i, stock := range stocks {
+		lots[i] = Outturn(stock, kernels[stock.StockID])
+	}
+	// Every lot shares one key, so there is at most a single group
+	for _, group := range GroupOutturns(lots, func(models.LotOutturn) string { return "" }) {
+		card.GradedLots = group.Lots
+		card.GradedWeight = group.RawWeight
+		card.KernelWeight = group.KernelWeight
+		card.KernelYieldPercent = percent(group.KernelWeight, group.RawWeight)
+		card.PromisedOutturn = group.PromisedOutturn
+		card.ActualOutturn = group.ActualOutturn
+		card.Variance = group.Variance
+	}
+
+	byCategory := make(map[string]float64)
+	var graded float64
+	for _, grade := range grades {
+		if owned[grade.StockID] {
+			byCategory[grade.CategoryCode] += grade.Weight
+			graded += grade.Weight
+		}
+	}
+	for category, weight := range byCategory {
+		card.GradeMix = append(card.GradeMix, models.GradeShare{
+			Category: category,
+			Weight:   roundWeight(weight),
+			Percent:  percent(weight, graded),
+		})
+	}
+	sort.Slice(card.GradeMix, func(i, j int) bool {
+		return card.GradeMix[i].Category < card.GradeMix[j].Category
+	})
+	return card
+}
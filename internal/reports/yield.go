@@ -0,0 +1,87 @@
+// Package reports derives production figures from the recorded weights
+package reports
+
+import (
+	"healing_photons/internal/models"
+	"math"
+	"strconv"
+)
+
+// Yield walks a lot through the processing chain. Each stage's input is the
+// output of the last stage that has records, starting from the weight the
+// lot was received at, so a lot part way through the chain shows no loss at
+// the stages it hasn't reached yet
+func Yield(stock models.Stock, totals models.StockStageTotals) models.YieldReport {
+	received := float32Weight(stock.Weight)
+	chain := stageChain(totals)
+
+	report := models.YieldReport{
+		StockID:        stock.StockID,
+		Status:         stock.Status,
+		ReceivedWeight: received,
+		Stages:         make([]models.YieldStage, 0, len(chain)),
+	}
+
+	in := received
+	for _, step := range chain {
+		stage := models.YieldStage{Stage: step.stage, Records: step.total.Records, WeightIn: in}
+		if step.total.Records > 0 {
+			out := roundWeight(step.total.Weight)
+			loss := roundWeight(in - out)
+			stage.WeightOut = out
+			stage.Loss = &loss
+			stage.LossPercent = percent(in-out, in)
+			report.FinalStage = step.stage
+			in = out
+		}
+		report.Stages = append(report.Stages, stage)
+	}
+
+	report.FinalWeight = in
+	report.YieldPercent = percent(in, received)
+	return report
+}
+
+// stageStep is one stage of the processing chain and its totals for a lot
+type stageStep struct {
+	stage string
+	total models.StageTotal
+}
+
+// stageChain lists the stages a lot passes through, in order
+func stageChain(totals models.StockStageTotals) []stageStep {
+	return []stageStep{
+		{models.EntityHumidifier, totals.Humidifier},
+		{models.EntityPeelingMachine, totals.PeelingMachine},
+		{models.EntityColorSort, totals.ColorSort},
+		{models.EntityMachineGrading, totals.MachineGrading},
+		{models.EntityManualGradingInput, totals.MachineGradingInputs},
+		{models.EntityManualGrading, totals.ManualGrading},
+	}
+}
+
+// percent returns part as a percentage of whole, or nil when whole is zero
+func percent(part, whole float64) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	return round2(part / whole * 100)
+}
+
+// round2 rounds to two decimals, the precision percentages and outturns are
+// reported at
+func round2(value float64) *float64 {
+	value = math.Round(value*100) / 100
+	return &value
+}
+
+// roundWeight rounds to the gram, the precision weights are stored at
+func roundWeight(weight float64) float64 {
+	return math.Round(weight*1000) / 1000
+}
+
+// float32Weight widens a float32 weight without picking up binary noise
+func float32Weight(weight float32) float64 {
+	value, _ := strconv.ParseFloat(strconv.FormatFloat(float64(weight), 'f', -1, 32), 64)
+	return value
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// AnomalyStore keeps the stage records flagged by the weight plausibility
+// checks
+type AnomalyStore interface {
+	// Record appends an anomaly, assigning its ID and timestamp
+	Record(ctx context.Context, anomaly *models.Anomaly) error
+	// List returns a page of anomalies matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Anomaly], error)
+}
+
+// AnomalyList describes how anomalies can be listed. They come oldest
+// first, in the order the records were written
+var AnomalyList = ListSpec[models.Anomaly]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "id"},
+	TimeField:   "created_at",
+	Sortable:    []string{"id", "created_at"},
+	Filterable:  []string{"stock_id", "stage", "record_id"},
+	Fields: map[string]Field[models.Anomaly]{
+		"id":         Number(func(a models.Anomaly) int64 { return a.ID }),
+		"stock_id":   Text(func(a models.Anomaly) string { return a.StockID }),
+		"stage":      Text(func(a models.Anomaly) string { return a.Stage }),
+		"record_id":  Text(func(a models.Anomaly) string { return a.RecordID }),
+		"created_at": Timestamp(func(a models.Anomaly) time.Time { return a.CreatedAt }),
+	},
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// AuditStore keeps the append-only log of changes to every other entity
+type AuditStore interface {
+	// Record appends an entry, assigning its ID and timestamp
+	Record(ctx context.Context, entry *models.AuditEntry) error
+	// List returns a page of entries matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.AuditEntry], error)
+}
+
+// AuditList describes how audit entries can be listed. They come oldest
+// first so a record's history reads in order
+var AuditList = ListSpec[models.AuditEntry]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "id"},
+	TimeField:   "created_at",
+	Sortable:    []string{"id", "created_at"},
+	Filterable:  []string{"entity", "entity_id", "action", "actor_type", "actor_id"},
+	Fields: map[string]Field[models.AuditEntry]{
+		"id":         Number(func(e models.AuditEntry) int64 { return e.ID }),
+		"entity":     Text(func(e models.AuditEntry) string { return e.Entity }),
+		"entity_id":  Text(func(e models.AuditEntry) string { return e.EntityID }),
+		"action":     Text(func(e models.AuditEntry) string { return string(e.Action) }),
+		"actor_type": Text(func(e models.AuditEntry) string { return e.Actor.Type }),
+		"actor_id":   OptionalInt64(func(e models.AuditEntry) *int64 { return e.Actor.ID }),
+		"created_at": Timestamp(func(e models.AuditEntry) time.Time { return e.CreatedAt }),
+	},
+}
+
+type actorKey struct{}
+
+// WithActor returns a context carrying who is making changes, for the
+// audit log
+func WithActor(ctx context.Context, actor models.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor
+func ActorFrom(ctx context.Context) (models.Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(models.Actor)
+	return actor, ok
+}
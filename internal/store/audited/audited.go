@@ -0,0 +1,178 @@
+// Package audited wraps the storage interfaces so every change made through
+// them is written to the audit log, along with who made it and the record
+// as it was before and after
+package audited
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"slices"
+)
+
+// NewStores returns stores that write every change to inner.Audit in the
+// same unit of work as the change itself, so neither is kept without the
+// other. Sessions, reports and the log itself are left as is
+func NewStores(inner *store.Stores) *store.Stores {
+	stores := *inner
+	stores.Stocks = &StockStore{inner.Stocks, inner.UnitOfWork}
+	stores.Humidifiers = &HumidifierStore{inner.Humidifiers, inner.UnitOfWork}
+	stores.PeelingMachines = &PeelingMachineStore{inner.PeelingMachines, inner.UnitOfWork}
+	stores.ColorSorts = &ColorSortStore{inner.ColorSorts, inner.UnitOfWork}
+	stores.MachineGradings = &MachineGradingStore{inner.MachineGradings, inner.UnitOfWork}
+	stores.ManualGradings = &ManualGradingStore{inner.ManualGradings, inner.UnitOfWork}
+	stores.ManualGradingInputs = &ManualGradingInputStore{inner.ManualGradingInputs, inner.UnitOfWork}
+	stores.GradingSheets = &GradingSheetStore{inner.GradingSheets, inner.UnitOfWork}
+	stores.GraderMachineOutputs = &GraderMachineOutputStore{inner.GraderMachineOutputs, inner.UnitOfWork}
+	stores.GradingCategories = &GradingCategoryStore{inner.GradingCategories, inner.UnitOfWork}
+	stores.Pieces = &PieceStore{inner.Pieces, inner.UnitOfWork}
+	stores.SizeVariations = &SizeVariationStore{inner.SizeVariations, inner.UnitOfWork}
+	stores.WeightTypes = &WeightTypeStore{inner.WeightTypes, inner.UnitOfWork}
+	stores.Workforce = &WorkforceStore{inner.Workforce, inner.UnitOfWork}
+	stores.Users = &UserStore{inner.Users, inner.UnitOfWork}
+	stores.Sellers = &SellerStore{inner.Sellers, inner.UnitOfWork}
+	stores.Devices = &DeviceStore{inner.Devices, inner.UnitOfWork}
+	stores.Trash = &TrashStore{inner.Trash, inner.UnitOfWork}
+	stores.UnitOfWork = &UnitOfWork{inner.UnitOfWork}
+	return &stores
+}
+
+// UnitOfWork wraps the stores of each unit as well, so the audit entries for
+// a unit's changes are kept or rolled back along with them
+type UnitOfWork struct {
+	store.UnitOfWork
+}
+
+// Do runs work with audited stores bound to the inner unit
+func (u *UnitOfWork) Do(ctx context.Context, work func(stores *store.Stores) error) error {
+	return u.UnitOfWork.Do(ctx, func(stores *store.Stores) error {
+		return work(NewStores(stores))
+	})
+}
+
+// record appends an entry for a change to the log. A nil before or after
+// is stored as null
+func record[T any](ctx context.Context, log store.AuditStore, entity, id string, action models.AuditAction, before, after *T) error {
+	actor, ok := store.ActorFrom(ctx)
+	if !ok {
+		actor = models.Actor{Type: models.ActorSystem}
+	}
+	entry := models.AuditEntry{Actor: actor, Entity: entity, EntityID: id, Action: action}
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return err
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return err
+		}
+	}
+	if err := log.Record(ctx, &entry); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// created runs a create and records the new record
+func created[T any](ctx context.Context, log store.AuditStore, entity string, row *T, id func(T) any, create func() error) error {
+	if err := create(); err != nil {
+		return err
+	}
+	return record(ctx, log, entity, fmt.Sprint(id(*row)), models.AuditCreate, nil, row)
+}
+
+// createdMany runs a batch create and records each record it stored
+func createdMany[T any](ctx context.Context, log store.AuditStore, entity string, rows []*T, atomic bool, id func(T) any, create func() ([]error, error)) ([]error, error) {
+	errs, err := create()
+	if err != nil {
+		return errs, err
+	}
+	if atomic && slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+		return errs, nil
+	}
+	for i, row := range rows {
+		if errs[i] != nil {
+			continue
+		}
+		if err := record(ctx, log, entity, fmt.Sprint(id(*row)), models.AuditCreate, nil, row); err != nil {
+			return errs, err
+		}
+	}
+	return errs, nil
+}
+
+// corrected runs a correction and records the reversal and replacement it
+// stored, each under its own ID
+func corrected[T any](ctx context.Context, log store.AuditStore, entity string, reversal, replacement *T, id func(T) any, correct func() error) error {
+	if err := correct(); err != nil {
+		return err
+	}
+	if err := record(ctx, log, entity, fmt.Sprint(id(*reversal)), models.AuditCorrect, nil, reversal); err != nil {
+		return err
+	}
+	if replacement == nil {
+		return nil
+	}
+	return record(ctx, log, entity, fmt.Sprint(id(*replacement)), models.AuditCorrect, nil, replacement)
+}
+
+// changed runs a change to an existing record and records it as it was
+// before and after. The record is locked as it is read, so no other change
+// lands between the before and the change
+func changed[K comparable, T any](ctx context.Context, log store.AuditStore, entity string, id K, action models.AuditAction, get func(context.Context, K) (T, error), change func() error) error {
+	before, err := get(store.ForUpdate(ctx), id)
+	if errors.Is(err, store.ErrNotFound) {
+		// Let the store report the missing record the way it always does
+		return change()
+	}
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+
+	after, err := get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return record(ctx, log, entity, fmt.Sprint(id), action, &before, &after)
+}
+
+// deleted runs a delete and records the record as it was, locked as
+// changed locks it
+func deleted[K comparable, T any](ctx context.Context, log store.AuditStore, entity string, id K, get func(context.Context, K) (T, error), remove func() error) error {
+	before, err := get(store.ForUpdate(ctx), id)
+	if errors.Is(err, store.ErrNotFound) {
+		return remove()
+	}
+	if err != nil {
+		return err
+	}
+	if err := remove(); err != nil {
+		return err
+	}
+	return record[T](ctx, log, entity, fmt.Sprint(id), models.AuditDelete, &before, nil)
+}
+
+// trashed runs a change to a record in the trash and records the trash
+// item as it was
+func trashed(ctx context.Context, log store.AuditStore, trash store.TrashStore, entity, id string, action models.AuditAction, change func() error) error {
+	before, err := trash.Get(store.ForUpdate(ctx), entity, id)
+	if errors.Is(err, store.ErrNotFound) {
+		return change()
+	}
+	if err != nil {
+		return err
+	}
+	if err := change(); err != nil {
+		return err
+	}
+	return record[models.TrashItem](ctx, log, entity, id, action, &before, nil)
+}
@@ -0,0 +1,686 @@
+package audited
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// StockStore audits a store.StockStore
+type StockStore struct {
+	store.StockStore
+	units store.UnitOfWork
+}
+
+// Create inserts a lot and logs it
+func (s *StockStore) Create(ctx context.Context, stock *models.Stock) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityStock, stock, func(lot models.Stock) any { return lot.StockID }, func() error {
+			return tx.Stocks.Create(ctx, stock)
+		})
+	})
+}
+
+// Update overwrites a stock lot and logs the change
+func (s *StockStore) Update(ctx context.Context, id string, stock models.Stock) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityStock, id, models.AuditUpdate, tx.Stocks.Get, func() error {
+			return tx.Stocks.Update(ctx, id, stock)
+		})
+	})
+}
+
+// Delete moves a stock lot to the trash and logs what it was
+func (s *StockStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityStock, id, tx.Stocks.Get, func() error {
+			return tx.Stocks.Delete(ctx, id)
+		})
+	})
+}
+
+// Transition moves a lot to another status and logs the change
+func (s *StockStore) Transition(ctx context.Context, id string, from, to models.StockStatus, note string) (models.StockTransition, error) {
+	var transition models.StockTransition
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityStock, id, models.AuditTransition, tx.Stocks.Get, func() error {
+			var err error
+			transition, err = tx.Stocks.Transition(ctx, id, from, to, note)
+			return err
+		})
+	})
+	return transition, err
+}
+
+// HumidifierStore audits a store.HumidifierStore
+type HumidifierStore struct {
+	store.HumidifierStore
+	units store.UnitOfWork
+}
+
+// Create inserts a humidifier record and logs it
+func (s *HumidifierStore) Create(ctx context.Context, humidifier *models.Humidifier) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityHumidifier, humidifier, func(h models.Humidifier) any { return h.ID }, func() error {
+			return tx.Humidifiers.Create(ctx, humidifier)
+		})
+	})
+}
+
+// CreateMany inserts a batch of humidifier records and logs each one created
+func (s *HumidifierStore) CreateMany(ctx context.Context, humidifiers []*models.Humidifier, atomic bool) ([]error, error) {
+	var errs []error
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		var err error
+		errs, err = createdMany(ctx, tx.Audit, models.EntityHumidifier, humidifiers, atomic, func(h models.Humidifier) any { return h.ID }, func() ([]error, error) {
+			return tx.Humidifiers.CreateMany(ctx, humidifiers, atomic)
+		})
+		return err
+	})
+	return errs, err
+}
+
+// Correct stores the entries correcting a humidifier record and logs each
+func (s *HumidifierStore) Correct(ctx context.Context, reversal, replacement *models.Humidifier) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return corrected(ctx, tx.Audit, models.EntityHumidifier, reversal, replacement, func(h models.Humidifier) any { return h.ID }, func() error {
+			return tx.Humidifiers.Correct(ctx, reversal, replacement)
+		})
+	})
+}
+
+// Delete moves a humidifier record to the trash and logs what it was
+func (s *HumidifierStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityHumidifier, id, tx.Humidifiers.Get, func() error {
+			return tx.Humidifiers.Delete(ctx, id)
+		})
+	})
+}
+
+// PeelingMachineStore audits a store.PeelingMachineStore
+type PeelingMachineStore struct {
+	store.PeelingMachineStore
+	units store.UnitOfWork
+}
+
+// Create inserts a peeling machine record and logs it
+func (s *PeelingMachineStore) Create(ctx context.Context, machine *models.PeelingMachine) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityPeelingMachine, machine, func(m models.PeelingMachine) any { return m.ID }, func() error {
+			return tx.PeelingMachines.Create(ctx, machine)
+		})
+	})
+}
+
+// CreateMany inserts a batch of peeling machine records and logs each one created
+func (s *PeelingMachineStore) CreateMany(ctx context.Context, machines []*models.PeelingMachine, atomic bool) ([]error, error) {
+	var errs []error
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		var err error
+		errs, err = createdMany(ctx, tx.Audit, models.EntityPeelingMachine, machines, atomic, func(m models.PeelingMachine) any { return m.ID }, func() ([]error, error) {
+			return tx.PeelingMachines.CreateMany(ctx, machines, atomic)
+		})
+		return err
+	})
+	return errs, err
+}
+
+// Correct stores the entries correcting a peeling machine record and logs each
+func (s *PeelingMachineStore) Correct(ctx context.Context, reversal, replacement *models.PeelingMachine) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return corrected(ctx, tx.Audit, models.EntityPeelingMachine, reversal, replacement, func(m models.PeelingMachine) any { return m.ID }, func() error {
+			return tx.PeelingMachines.Correct(ctx, reversal, replacement)
+		})
+	})
+}
+
+// Delete moves a peeling machine record to the trash and logs what it was
+func (s *PeelingMachineStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityPeelingMachine, id, tx.PeelingMachines.Get, func() error {
+			return tx.PeelingMachines.Delete(ctx, id)
+		})
+	})
+}
+
+// ColorSortStore audits a store.ColorSortStore
+type ColorSortStore struct {
+	store.ColorSortStore
+	units store.UnitOfWork
+}
+
+// Create inserts a color sort record and logs it
+func (s *ColorSortStore) Create(ctx context.Context, colorSort *models.ColorSort) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityColorSort, colorSort, func(c models.ColorSort) any { return c.ID }, func() error {
+			return tx.ColorSorts.Create(ctx, colorSort)
+		})
+	})
+}
+
+// CreateMany inserts a batch of color sort records and logs each one created
+func (s *ColorSortStore) CreateMany(ctx context.Context, colorSorts []*models.ColorSort, atomic bool) ([]error, error) {
+	var errs []error
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		var err error
+		errs, err = createdMany(ctx, tx.Audit, models.EntityColorSort, colorSorts, atomic, func(c models.ColorSort) any { return c.ID }, func() ([]error, error) {
+			return tx.ColorSorts.CreateMany(ctx, colorSorts, atomic)
+		})
+		return err
+	})
+	return errs, err
+}
+
+// Correct stores the entries correcting a color sort record and logs each
+func (s *ColorSortStore) Correct(ctx context.Context, reversal, replacement *models.ColorSort) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return corrected(ctx, tx.Audit, models.EntityColorSort, reversal, replacement, func(c models.ColorSort) any { return c.ID }, func() error {
+			return tx.ColorSorts.Correct(ctx, reversal, replacement)
+		})
+	})
+}
+
+// Delete moves a color sort record to the trash and logs what it was
+func (s *ColorSortStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityColorSort, id, tx.ColorSorts.Get, func() error {
+			return tx.ColorSorts.Delete(ctx, id)
+		})
+	})
+}
+
+// MachineGradingStore audits a store.MachineGradingStore
+type MachineGradingStore struct {
+	store.MachineGradingStore
+	units store.UnitOfWork
+}
+
+// Create inserts a machine grading record and logs it
+func (s *MachineGradingStore) Create(ctx context.Context, grading *models.MachineGrading) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityMachineGrading, grading, func(g models.MachineGrading) any { return g.ID }, func() error {
+			return tx.MachineGradings.Create(ctx, grading)
+		})
+	})
+}
+
+// CreateMany inserts a batch of machine grading records and logs each one created
+func (s *MachineGradingStore) CreateMany(ctx context.Context, gradings []*models.MachineGrading, atomic bool) ([]error, error) {
+	var errs []error
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		var err error
+		errs, err = createdMany(ctx, tx.Audit, models.EntityMachineGrading, gradings, atomic, func(g models.MachineGrading) any { return g.ID }, func() ([]error, error) {
+			return tx.MachineGradings.CreateMany(ctx, gradings, atomic)
+		})
+		return err
+	})
+	return errs, err
+}
+
+// Correct stores the entries correcting a machine grading record and logs each
+func (s *MachineGradingStore) Correct(ctx context.Context, reversal, replacement *models.MachineGrading) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return corrected(ctx, tx.Audit, models.EntityMachineGrading, reversal, replacement, func(g models.MachineGrading) any { return g.ID }, func() error {
+			return tx.MachineGradings.Correct(ctx, reversal, replacement)
+		})
+	})
+}
+
+// Delete moves a machine grading record to the trash and logs what it was
+func (s *MachineGradingStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityMachineGrading, id, tx.MachineGradings.Get, func() error {
+			return tx.MachineGradings.Delete(ctx, id)
+		})
+	})
+}
+
+// ManualGradingStore audits a store.ManualGradingStore
+type ManualGradingStore struct {
+	store.ManualGradingStore
+	units store.UnitOfWork
+}
+
+// Create inserts a manual grading record and logs it
+func (s *ManualGradingStore) Create(ctx context.Context, grading *models.ManualGrading) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityManualGrading, grading, func(g models.ManualGrading) any { return g.ID }, func() error {
+			return tx.ManualGradings.Create(ctx, grading)
+		})
+	})
+}
+
+// CreateMany inserts a batch of manual grading records and logs each one created
+func (s *ManualGradingStore) CreateMany(ctx context.Context, gradings []*models.ManualGrading, atomic bool) ([]error, error) {
+	var errs []error
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		var err error
+		errs, err = createdMany(ctx, tx.Audit, models.EntityManualGrading, gradings, atomic, func(g models.ManualGrading) any { return g.ID }, func() ([]error, error) {
+			return tx.ManualGradings.CreateMany(ctx, gradings, atomic)
+		})
+		return err
+	})
+	return errs, err
+}
+
+// Correct stores the entries correcting a manual grading record and logs each
+func (s *ManualGradingStore) Correct(ctx context.Context, reversal, replacement *models.ManualGrading) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return corrected(ctx, tx.Audit, models.EntityManualGrading, reversal, replacement, func(g models.ManualGrading) any { return g.ID }, func() error {
+			return tx.ManualGradings.Correct(ctx, reversal, replacement)
+		})
+	})
+}
+
+// Delete moves a manual grading record to the trash and logs what it was
+func (s *ManualGradingStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityManualGrading, id, tx.ManualGradings.Get, func() error {
+			return tx.ManualGradings.Delete(ctx, id)
+		})
+	})
+}
+
+// ManualGradingInputStore audits a store.ManualGradingInputStore
+type ManualGradingInputStore struct {
+	store.ManualGradingInputStore
+	units store.UnitOfWork
+}
+
+// Create inserts a manual grading input and logs it
+func (s *ManualGradingInputStore) Create(ctx context.Context, input *models.ManualGradingInput) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityManualGradingInput, input, func(i models.ManualGradingInput) any { return i.ID }, func() error {
+			return tx.ManualGradingInputs.Create(ctx, input)
+		})
+	})
+}
+
+// CreateMany inserts a batch of manual grading inputs and logs each one created
+func (s *ManualGradingInputStore) CreateMany(ctx context.Context, inputs []*models.ManualGradingInput, atomic bool) ([]error, error) {
+	var errs []error
+	err := s.units.Do(ctx, func(tx *store.Stores) error {
+		var err error
+		errs, err = createdMany(ctx, tx.Audit, models.EntityManualGradingInput, inputs, atomic, func(i models.ManualGradingInput) any { return i.ID }, func() ([]error, error) {
+			return tx.ManualGradingInputs.CreateMany(ctx, inputs, atomic)
+		})
+		return err
+	})
+	return errs, err
+}
+
+// Correct stores the entries correcting a manual grading input record and logs each
+func (s *ManualGradingInputStore) Correct(ctx context.Context, reversal, replacement *models.ManualGradingInput) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return corrected(ctx, tx.Audit, models.EntityManualGradingInput, reversal, replacement, func(i models.ManualGradingInput) any { return i.ID }, func() error {
+			return tx.ManualGradingInputs.Correct(ctx, reversal, replacement)
+		})
+	})
+}
+
+// Delete moves a manual grading input record to the trash and logs what it was
+func (s *ManualGradingInputStore) Delete(ctx context.Context, id int) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityManualGradingInput, id, tx.ManualGradingInputs.Get, func() error {
+			return tx.ManualGradingInputs.Delete(ctx, id)
+		})
+	})
+}
+
+// GradingSheetStore audits a store.GradingSheetStore
+type GradingSheetStore struct {
+	store.GradingSheetStore
+	units store.UnitOfWork
+}
+
+// Create inserts a grading sheet and logs it
+func (s *GradingSheetStore) Create(ctx context.Context, sheet *models.GradingSheet) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityGradingSheet, sheet, func(g models.GradingSheet) any { return g.ID }, func() error {
+			return tx.GradingSheets.Create(ctx, sheet)
+		})
+	})
+}
+
+// Update overwrites a grading sheet and logs the change
+func (s *GradingSheetStore) Update(ctx context.Context, id int64, sheet models.GradingSheet) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityGradingSheet, id, models.AuditUpdate, tx.GradingSheets.Get, func() error {
+			return tx.GradingSheets.Update(ctx, id, sheet)
+		})
+	})
+}
+
+// Delete moves a grading sheet to the trash and logs what it was
+func (s *GradingSheetStore) Delete(ctx context.Context, id int64) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityGradingSheet, id, tx.GradingSheets.Get, func() error {
+			return tx.GradingSheets.Delete(ctx, id)
+		})
+	})
+}
+
+// GraderMachineOutputStore audits a store.GraderMachineOutputStore
+type GraderMachineOutputStore struct {
+	store.GraderMachineOutputStore
+	units store.UnitOfWork
+}
+
+// Create inserts a grader machine output and logs it
+func (s *GraderMachineOutputStore) Create(ctx context.Context, output *models.GraderMachineOutputs) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityGraderMachineOutput, output, func(o models.GraderMachineOutputs) any { return o.ID }, func() error {
+			return tx.GraderMachineOutputs.Create(ctx, output)
+		})
+	})
+}
+
+// Update overwrites a grader machine output and logs the change
+func (s *GraderMachineOutputStore) Update(ctx context.Context, id string, output models.GraderMachineOutputs) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityGraderMachineOutput, id, models.AuditUpdate, tx.GraderMachineOutputs.Get, func() error {
+			return tx.GraderMachineOutputs.Update(ctx, id, output)
+		})
+	})
+}
+
+// Delete moves a grader machine output to the trash and logs what it was
+func (s *GraderMachineOutputStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityGraderMachineOutput, id, tx.GraderMachineOutputs.Get, func() error {
+			return tx.GraderMachineOutputs.Delete(ctx, id)
+		})
+	})
+}
+
+// GradingCategoryStore audits a store.GradingCategoryStore
+type GradingCategoryStore struct {
+	store.GradingCategoryStore
+	units store.UnitOfWork
+}
+
+// Create inserts a grading category and logs it
+func (s *GradingCategoryStore) Create(ctx context.Context, category *models.GradingCategory) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityGradingCategory, category, func(c models.GradingCategory) any { return c.CategoryID }, func() error {
+			return tx.GradingCategories.Create(ctx, category)
+		})
+	})
+}
+
+// Update overwrites a grading category and logs the change
+func (s *GradingCategoryStore) Update(ctx context.Context, id int64, category models.GradingCategory) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityGradingCategory, id, models.AuditUpdate, tx.GradingCategories.Get, func() error {
+			return tx.GradingCategories.Update(ctx, id, category)
+		})
+	})
+}
+
+// Delete moves a grading category to the trash and logs what it was
+func (s *GradingCategoryStore) Delete(ctx context.Context, id int64) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityGradingCategory, id, tx.GradingCategories.Get, func() error {
+			return tx.GradingCategories.Delete(ctx, id)
+		})
+	})
+}
+
+// PieceStore audits a store.PieceStore
+type PieceStore struct {
+	store.PieceStore
+	units store.UnitOfWork
+}
+
+// Create inserts a piece and logs it
+func (s *PieceStore) Create(ctx context.Context, piece *models.Pieces) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityPiece, piece, func(p models.Pieces) any { return p.PieceID }, func() error {
+			return tx.Pieces.Create(ctx, piece)
+		})
+	})
+}
+
+// Update overwrites a piece and logs the change
+func (s *PieceStore) Update(ctx context.Context, id int, piece models.Pieces) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityPiece, id, models.AuditUpdate, tx.Pieces.Get, func() error {
+			return tx.Pieces.Update(ctx, id, piece)
+		})
+	})
+}
+
+// Delete moves a piece to the trash and logs what it was
+func (s *PieceStore) Delete(ctx context.Context, id int) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityPiece, id, tx.Pieces.Get, func() error {
+			return tx.Pieces.Delete(ctx, id)
+		})
+	})
+}
+
+// SizeVariationStore audits a store.SizeVariationStore
+type SizeVariationStore struct {
+	store.SizeVariationStore
+	units store.UnitOfWork
+}
+
+// Create inserts a size variation and logs it
+func (s *SizeVariationStore) Create(ctx context.Context, variation *models.SizeVariations) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntitySizeVariation, variation, func(v models.SizeVariations) any { return v.SizeID }, func() error {
+			return tx.SizeVariations.Create(ctx, variation)
+		})
+	})
+}
+
+// Update overwrites a size variation and logs the change
+func (s *SizeVariationStore) Update(ctx context.Context, id int, variation models.SizeVariations) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntitySizeVariation, id, models.AuditUpdate, tx.SizeVariations.Get, func() error {
+			return tx.SizeVariations.Update(ctx, id, variation)
+		})
+	})
+}
+
+// Delete moves a size variation to the trash and logs what it was
+func (s *SizeVariationStore) Delete(ctx context.Context, id int) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntitySizeVariation, id, tx.SizeVariations.Get, func() error {
+			return tx.SizeVariations.Delete(ctx, id)
+		})
+	})
+}
+
+// WeightTypeStore audits a store.WeightTypeStore
+type WeightTypeStore struct {
+	store.WeightTypeStore
+	units store.UnitOfWork
+}
+
+// Create inserts a weight type and logs it
+func (s *WeightTypeStore) Create(ctx context.Context, weightType *models.WeightTypes) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityWeightType, weightType, func(w models.WeightTypes) any { return w.ID }, func() error {
+			return tx.WeightTypes.Create(ctx, weightType)
+		})
+	})
+}
+
+// Update overwrites a weight type and logs the change
+func (s *WeightTypeStore) Update(ctx context.Context, id string, weightType models.WeightTypes) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityWeightType, id, models.AuditUpdate, tx.WeightTypes.Get, func() error {
+			return tx.WeightTypes.Update(ctx, id, weightType)
+		})
+	})
+}
+
+// Delete moves a weight type to the trash and logs what it was
+func (s *WeightTypeStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityWeightType, id, tx.WeightTypes.Get, func() error {
+			return tx.WeightTypes.Delete(ctx, id)
+		})
+	})
+}
+
+// WorkforceStore audits a store.WorkforceStore
+type WorkforceStore struct {
+	store.WorkforceStore
+	units store.UnitOfWork
+}
+
+// Create inserts a worker and logs it
+func (s *WorkforceStore) Create(ctx context.Context, workforce *models.Workforce) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityWorkforce, workforce, func(w models.Workforce) any { return w.ID }, func() error {
+			return tx.Workforce.Create(ctx, workforce)
+		})
+	})
+}
+
+// Update overwrites a worker and logs the change
+func (s *WorkforceStore) Update(ctx context.Context, id string, workforce models.Workforce) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityWorkforce, id, models.AuditUpdate, tx.Workforce.Get, func() error {
+			return tx.Workforce.Update(ctx, id, workforce)
+		})
+	})
+}
+
+// Delete moves a worker to the trash and logs what it was
+func (s *WorkforceStore) Delete(ctx context.Context, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntityWorkforce, id, tx.Workforce.Get, func() error {
+			return tx.Workforce.Delete(ctx, id)
+		})
+	})
+}
+
+// UserStore audits a store.UserStore. Password hashes never reach the log
+type UserStore struct {
+	store.UserStore
+	units store.UnitOfWork
+}
+
+// Create inserts an user and logs it
+func (s *UserStore) Create(ctx context.Context, user *models.User) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityUser, user, func(u models.User) any { return u.ID }, func() error {
+			return tx.Users.Create(ctx, user)
+		})
+	})
+}
+
+// Update overwrites an user and logs the change
+func (s *UserStore) Update(ctx context.Context, id int64, user models.User) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityUser, id, models.AuditUpdate, tx.Users.Get, func() error {
+			return tx.Users.Update(ctx, id, user)
+		})
+	})
+}
+
+// SellerStore audits a store.SellerStore. The lots renamed along with a
+// seller are covered by the seller's entry
+type SellerStore struct {
+	store.SellerStore
+	units store.UnitOfWork
+}
+
+// Create inserts a seller and logs it
+func (s *SellerStore) Create(ctx context.Context, seller *models.Seller) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntitySeller, seller, func(seller models.Seller) any { return seller.ID }, func() error {
+			return tx.Sellers.Create(ctx, seller)
+		})
+	})
+}
+
+// Update overwrites a seller and logs the change
+func (s *SellerStore) Update(ctx context.Context, id int64, seller models.Seller) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntitySeller, id, models.AuditUpdate, tx.Sellers.Get, func() error {
+			return tx.Sellers.Update(ctx, id, seller)
+		})
+	})
+}
+
+// Delete moves a seller to the trash and logs what it was
+func (s *SellerStore) Delete(ctx context.Context, id int64) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return deleted(ctx, tx.Audit, models.EntitySeller, id, tx.Sellers.Get, func() error {
+			return tx.Sellers.Delete(ctx, id)
+		})
+	})
+}
+
+// DeviceStore audits a store.DeviceStore. Key hashes never reach the log
+type DeviceStore struct {
+	store.DeviceStore
+	units store.UnitOfWork
+}
+
+// Create inserts a device and logs it
+func (s *DeviceStore) Create(ctx context.Context, device *models.Device) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return created(ctx, tx.Audit, models.EntityDevice, device, func(d models.Device) any { return d.ID }, func() error {
+			return tx.Devices.Create(ctx, device)
+		})
+	})
+}
+
+// Update overwrites a device and logs the change
+func (s *DeviceStore) Update(ctx context.Context, id int64, device models.Device) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityDevice, id, models.AuditUpdate, tx.Devices.Get, func() error {
+			return tx.Devices.Update(ctx, id, device)
+		})
+	})
+}
+
+// RotateKey replaces a device's key and logs the rotation
+func (s *DeviceStore) RotateKey(ctx context.Context, id int64, keyHash, keyPrefix string, grace time.Duration) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityDevice, id, models.AuditRotateKey, tx.Devices.Get, func() error {
+			return tx.Devices.RotateKey(ctx, id, keyHash, keyPrefix, grace)
+		})
+	})
+}
+
+// Revoke disables a device and logs it
+func (s *DeviceStore) Revoke(ctx context.Context, id int64) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return changed(ctx, tx.Audit, models.EntityDevice, id, models.AuditRevoke, tx.Devices.Get, func() error {
+			return tx.Devices.Revoke(ctx, id)
+		})
+	})
+}
+
+// TrashStore audits a store.TrashStore. Entries are logged against the
+// restored or purged record with the trash item as it was
+type TrashStore struct {
+	store.TrashStore
+	units store.UnitOfWork
+}
+
+// Restore takes a record out of the trash and logs it
+func (s *TrashStore) Restore(ctx context.Context, entity, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return trashed(ctx, tx.Audit, tx.Trash, entity, id, models.AuditRestore, func() error {
+			return tx.Trash.Restore(ctx, entity, id)
+		})
+	})
+}
+
+// Purge removes a deleted record for good and logs it
+func (s *TrashStore) Purge(ctx context.Context, entity, id string) error {
+	return s.units.Do(ctx, func(tx *store.Stores) error {
+		return trashed(ctx, tx.Audit, tx.Trash, entity, id, models.AuditPurge, func() error {
+			return tx.Trash.Purge(ctx, entity, id)
+		})
+	})
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// ColorSortStore persists color sorter weighings
+type ColorSortStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.ColorSort], error)
+	Get(ctx context.Context, id string) (models.ColorSort, error)
+	Create(ctx context.Context, colorSort *models.ColorSort) error
+	// CreateMany inserts original records in one transaction. errs holds,
+	// at each record's index, why it couldn't be inserted. When atomic is
+	// set, any such failure rolls the whole batch back
+	CreateMany(ctx context.Context, colorSorts []*models.ColorSort, atomic bool) (errs []error, err error)
+	// Correct stores the reversal of a record and, unless the record is
+	// only being voided, its replacement. It returns ErrNotFound when the
+	// reversed record doesn't exist and ErrConflict when it is a reversal or
+	// has already been corrected
+	Correct(ctx context.Context, reversal, replacement *models.ColorSort) error
+	// Delete moves an original record to the trash, see TrashStore. It
+	// returns ErrConflict when the record is a reversal or replacement, or
+	// has been corrected; those stay on the ledger
+	Delete(ctx context.Context, id string) error
+	AcceptedWeightSummary(ctx context.Context, stockID string, counter int) (models.ColorSortSummary, error)
+}
+
+// ColorSortList describes how color sort records can be listed
+var ColorSortList = ListSpec[models.ColorSort]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "accepted_weight", "sort_counter", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "peel_id", "weight_type_id", "sort_counter", "device_id", "entry_type", "corrects_id"},
+	Fields: map[string]Field[models.ColorSort]{
+		"id":              Text(func(c models.ColorSort) string { return c.ID }),
+		"peel_id":         OptionalText(func(c models.ColorSort) *string { return c.PeelID }),
+		"stock_id":        OptionalText(func(c models.ColorSort) *string { return c.StockID }),
+		"weight_type_id":  Number(func(c models.ColorSort) int { return c.WeightTypeID }),
+		"accepted_weight": Number(func(c models.ColorSort) float64 { return c.AcceptedWeight }),
+		"sort_counter":    Number(func(c models.ColorSort) int { return c.SortCounter }),
+		"device_id":       OptionalInt64(func(c models.ColorSort) *int64 { return c.DeviceID }),
+		"entry_type":      Text(func(c models.ColorSort) string { return string(c.EntryType) }),
+		"corrects_id":     OptionalText(func(c models.ColorSort) *string { return c.CorrectsID }),
+		"created_at":      Timestamp(func(c models.ColorSort) time.Time { return c.CreatedAt }),
+		"updated_at":      Timestamp(func(c models.ColorSort) time.Time { return c.UpdatedAt }),
+	},
+}
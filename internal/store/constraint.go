@@ -0,0 +1,47 @@
+package store
+
+import "fmt"
+
+// Constraint names the kind of rule a write broke
+type Constraint string
+
+const (
+	// ConstraintUnique is broken by a write repeating a primary or unique key
+	ConstraintUnique Constraint = "unique"
+	// ConstraintForeignKey is broken by a write referring to a parent
+	// record that does not exist
+	ConstraintForeignKey Constraint = "foreign_key"
+	// ConstraintLength is broken by a value too long for its column
+	ConstraintLength Constraint = "length"
+)
+
+// ConstraintError is returned when the database refuses a write because it
+// breaks one of the schema's constraints. It unwraps to the driver's error
+type ConstraintError struct {
+	Constraint Constraint
+	// Column is the column at fault, when the database names it
+	Column string
+	// Key is the unique key clashed with
+	Key string
+	// Value is the value that clashed with Key
+	Value string
+	// Parent is the table a foreign key refers to
+	Parent string
+	Err    error
+}
+
+func (e *ConstraintError) Error() string {
+	switch e.Constraint {
+	case ConstraintUnique:
+		return fmt.Sprintf("duplicate entry '%s' for key '%s'", e.Value, e.Key)
+	case ConstraintForeignKey:
+		return fmt.Sprintf("%s refers to a %s that does not exist", e.Column, e.Parent)
+	case ConstraintLength:
+		return fmt.Sprintf("data too long for column '%s'", e.Column)
+	}
+	return fmt.Sprintf("%s constraint failed", e.Constraint)
+}
+
+func (e *ConstraintError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,46 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// DeviceStore persists the shop-floor devices and their API keys
+type DeviceStore interface {
+	// List returns a page of devices matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Device], error)
+	Get(ctx context.Context, id int64) (models.Device, error)
+	// GetByKeyHash returns the device a key belongs to. A rotated out key
+	// matches until its grace period ends
+	GetByKeyHash(ctx context.Context, keyHash string) (models.Device, error)
+	// Create inserts the device, assigning an ID
+	Create(ctx context.Context, device *models.Device) error
+	// Update changes a device's name and stages, leaving its key alone
+	Update(ctx context.Context, id int64, device models.Device) error
+	// RotateKey replaces a device's key. The old key keeps working for
+	// grace, or stops at once when grace is zero. It returns ErrConflict
+	// for a revoked device
+	RotateKey(ctx context.Context, id int64, keyHash, keyPrefix string, grace time.Duration) error
+	// Revoke disables a device and all of its keys for good. It returns
+	// ErrConflict when the device was already revoked
+	Revoke(ctx context.Context, id int64) error
+}
+
+// DeviceList describes how devices can be listed. Revoked devices are kept
+// and listed along with the rest
+var DeviceList = ListSpec[models.Device]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "name"},
+	TimeField:   "created_at",
+	Sortable:    []string{"id", "name", "key_rotated_at", "created_at", "updated_at"},
+	Filterable:  []string{"name", "key_prefix"},
+	Fields: map[string]Field[models.Device]{
+		"id":             Number(func(d models.Device) int64 { return d.ID }),
+		"name":           Text(func(d models.Device) string { return d.Name }),
+		"key_prefix":     Text(func(d models.Device) string { return d.KeyPrefix }),
+		"key_rotated_at": Timestamp(func(d models.Device) time.Time { return d.KeyRotatedAt }),
+		"created_at":     Timestamp(func(d models.Device) time.Time { return d.CreatedAt }),
+		"updated_at":     Timestamp(func(d models.Device) time.Time { return d.UpdatedAt }),
+	},
+}
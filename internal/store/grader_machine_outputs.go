@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// GraderMachineOutputStore persists the grader machine output types
+type GraderMachineOutputStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.GraderMachineOutputs], error)
+	Get(ctx context.Context, id string) (models.GraderMachineOutputs, error)
+	Create(ctx context.Context, output *models.GraderMachineOutputs) error
+	Update(ctx context.Context, id string, output models.GraderMachineOutputs) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id string) error
+}
+
+// GraderMachineOutputList describes how grader machine outputs can be listed
+var GraderMachineOutputList = ListSpec[models.GraderMachineOutputs]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "id"},
+	Trashable:   true,
+	Sortable:    []string{"id", "type"},
+	Filterable:  []string{"type"},
+	Fields: map[string]Field[models.GraderMachineOutputs]{
+		"id":   Text(func(o models.GraderMachineOutputs) string { return o.ID }),
+		"type": Text(func(o models.GraderMachineOutputs) string { return o.Type }),
+	},
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// GradingCategoryStore persists the grading categories
+type GradingCategoryStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.GradingCategory], error)
+	Get(ctx context.Context, id int64) (models.GradingCategory, error)
+	Create(ctx context.Context, category *models.GradingCategory) error
+	Update(ctx context.Context, id int64, category models.GradingCategory) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id int64) error
+}
+
+// GradingCategoryList describes how grading categories can be listed
+var GradingCategoryList = ListSpec[models.GradingCategory]{
+	Key:         "category_id",
+	DefaultSort: Sort{Field: "category_code"},
+	Trashable:   true,
+	Sortable:    []string{"category_id", "category_code"},
+	Filterable:  []string{"category_code"},
+	Fields: map[string]Field[models.GradingCategory]{
+		"category_id":   Number(func(g models.GradingCategory) int64 { return g.CategoryID }),
+		"category_code": Text(func(g models.GradingCategory) string { return g.CategoryCode }),
+	},
+}
@@ -0,0 +1,37 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// GradingSheetStore persists the grade sheets handed in by manual graders
+type GradingSheetStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.GradingSheet], error)
+	Get(ctx context.Context, id int64) (models.GradingSheet, error)
+	// Create inserts the sheet, assigning an ID when none is set
+	Create(ctx context.Context, sheet *models.GradingSheet) error
+	Update(ctx context.Context, id int64, sheet models.GradingSheet) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id int64) error
+}
+
+// GradingSheetList describes how grading sheets can be listed
+var GradingSheetList = ListSpec[models.GradingSheet]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "worker_id", "size_variations_id"},
+	Fields: map[string]Field[models.GradingSheet]{
+		"id":                 Number(func(g models.GradingSheet) int64 { return g.ID }),
+		"stock_id":           Text(func(g models.GradingSheet) string { return g.StockID }),
+		"worker_id":          Text(func(g models.GradingSheet) string { return g.WorkerID }),
+		"size_variations_id": Number(func(g models.GradingSheet) int64 { return g.SizeVariationsID }),
+		"created_at":         Timestamp(func(g models.GradingSheet) time.Time { return g.CreatedAt }),
+		"updated_at":         Timestamp(func(g models.GradingSheet) time.Time { return g.UpdatedAt }),
+	},
+}
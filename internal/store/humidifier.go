@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// HumidifierStore persists humidifier weighings
+type HumidifierStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Humidifier], error)
+	Get(ctx context.Context, id string) (models.Humidifier, error)
+	Create(ctx context.Context, humidifier *models.Humidifier) error
+	// CreateMany inserts original records in one transaction. errs holds,
+	// at each record's index, why it couldn't be inserted. When atomic is
+	// set, any such failure rolls the whole batch back
+	CreateMany(ctx context.Context, humidifiers []*models.Humidifier, atomic bool) (errs []error, err error)
+	// Correct stores the reversal of a record and, unless the record is
+	// only being voided, its replacement. It returns ErrNotFound when the
+	// reversed record doesn't exist and ErrConflict when it is a reversal or
+	// has already been corrected
+	Correct(ctx context.Context, reversal, replacement *models.Humidifier) error
+	// Delete moves an original record to the trash, see TrashStore. It
+	// returns ErrConflict when the record is a reversal or replacement, or
+	// has been corrected; those stay on the ledger
+	Delete(ctx context.Context, id string) error
+}
+
+// HumidifierList describes how humidifier records can be listed
+var HumidifierList = ListSpec[models.Humidifier]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "weight", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "device_id", "entry_type", "corrects_id"},
+	Fields: map[string]Field[models.Humidifier]{
+		"id":          Text(func(h models.Humidifier) string { return h.ID }),
+		"stock_id":    Text(func(h models.Humidifier) string { return h.StockID }),
+		"weight":      Float32(func(h models.Humidifier) float32 { return h.Weight }),
+		"device_id":   OptionalInt64(func(h models.Humidifier) *int64 { return h.DeviceID }),
+		"entry_type":  Text(func(h models.Humidifier) string { return string(h.EntryType) }),
+		"corrects_id": OptionalText(func(h models.Humidifier) *string { return h.CorrectsID }),
+		"created_at":  Timestamp(func(h models.Humidifier) time.Time { return h.CreatedAt }),
+		"updated_at":  Timestamp(func(h models.Humidifier) time.Time { return h.UpdatedAt }),
+	},
+}
@@ -0,0 +1,27 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// IdempotencyStore remembers the Idempotency-Key headers of create
+// requests and the responses they got
+type IdempotencyStore interface {
+	// Claim stores key as in progress unless the caller already used it.
+	// A completed use is kept until expired and an unfinished one until
+	// abandoned, after which the request that claimed it is taken to have
+	// died and another may take the key over. When the key is held, Claim
+	// returns it as stored and false; otherwise it returns the new claim
+	Claim(ctx context.Context, key models.IdempotencyKey, expired, abandoned time.Time) (models.IdempotencyKey, bool, error)
+	// Complete stores the response to the request claim was made for. It
+	// returns ErrNotFound when the claim was taken over in the meantime
+	Complete(ctx context.Context, claim models.IdempotencyKey, statusCode int, response []byte) error
+	// Release forgets claim, so the request can be retried. A claim taken
+	// over in the meantime is left alone
+	Release(ctx context.Context, claim models.IdempotencyKey) error
+	// Sweep forgets every key claimed before expired, whether or not its
+	// request finished, and returns how many it forgot
+	Sweep(ctx context.Context, expired time.Time) (int64, error)
+}
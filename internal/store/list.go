@@ -0,0 +1,251 @@
+package store
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldKind is the type of value a list field holds
+type FieldKind int
+
+const (
+	StringField FieldKind = iota
+	NumberField
+	TimeField
+)
+
+// Field describes a column a list can be filtered or sorted on. Value
+// returns the column of a record as a string, float64 or time.Time to match
+// Kind, or nil when the column is NULL
+type Field[T any] struct {
+	Kind  FieldKind
+	Value func(T) any
+}
+
+// ListSpec describes how a resource can be listed. Field names are both the
+// JSON names and the database columns
+type ListSpec[T any] struct {
+	// Key is a unique field used to order records with equal sort values
+	Key         string
+	DefaultSort Sort
+	// TimeField is the field from/to apply to, empty when the resource has
+	// no timestamps
+	TimeField string
+	// Trashable is set for resources whose deleted records stay in the
+	// table with deleted_at set; lists leave those records out
+	Trashable  bool
+	Sortable   []string
+	Filterable []string
+	Fields     map[string]Field[T]
+}
+
+// Sort orders a list by a single field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// String returns the sort in query form, e.g. "-created_at"
+func (s Sort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// Filter keeps the records whose field equals Value
+type Filter struct {
+	Field string
+	Value any
+}
+
+// Cursor marks the last record of a page so the next page starts after it
+type Cursor struct {
+	Sort  Sort
+	Value any
+	Key   any
+}
+
+// ListOptions narrows, orders and pages a list
+type ListOptions struct {
+	// Limit caps the page size; zero or less returns every match
+	Limit   int
+	Sort    Sort
+	Filters []Filter
+	// From and To bound the spec's time field, From inclusive and To
+	// exclusive; zero values leave that end open
+	From  time.Time
+	To    time.Time
+	After *Cursor
+}
+
+// Page is one slice of a list together with the total number of matches
+type Page[T any] struct {
+	Items []T
+	// Next is nil on the last page
+	Next  *Cursor
+	Total int
+}
+
+// All returns options that list every record in the default order
+func All(filters ...Filter) ListOptions {
+	return ListOptions{Filters: filters}
+}
+
+// SortOrDefault returns the requested sort, falling back to the spec default
+func (spec ListSpec[T]) SortOrDefault(opts ListOptions) Sort {
+	if opts.Sort.Field == "" {
+		return spec.DefaultSort
+	}
+	return opts.Sort
+}
+
+// ParseSort parses "field" or "-field" and checks the field is sortable
+func (spec ListSpec[T]) ParseSort(raw string) (Sort, error) {
+	sort := Sort{Field: strings.TrimPrefix(raw, "-"), Desc: strings.HasPrefix(raw, "-")}
+	if !slices.Contains(spec.Sortable, sort.Field) {
+		return Sort{}, fmt.Errorf("cannot sort by %q; sortable fields are %s", sort.Field, strings.Join(spec.Sortable, ", "))
+	}
+	return sort, nil
+}
+
+// ParseValue converts a raw query value into the type of the named field
+func (spec ListSpec[T]) ParseValue(field, raw string) (any, error) {
+	return parseValue(spec.Fields[field].Kind, raw)
+}
+
+// CursorAfter returns the cursor pointing just past record
+func (spec ListSpec[T]) CursorAfter(record T, sort Sort) *Cursor {
+	return &Cursor{
+		Sort:  sort,
+		Value: spec.Fields[sort.Field].Value(record),
+		Key:   spec.Fields[spec.Key].Value(record),
+	}
+}
+
+type encodedCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	Key   string `json:"k"`
+}
+
+// EncodeCursor returns the opaque form of a cursor handed to clients
+func (spec ListSpec[T]) EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(encodedCursor{
+		Sort:  cursor.Sort.String(),
+		Value: formatValue(cursor.Value),
+		Key:   formatValue(cursor.Key),
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor
+func (spec ListSpec[T]) DecodeCursor(raw string) (Cursor, error) {
+	invalid := fmt.Errorf("invalid cursor")
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	var encoded encodedCursor
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return Cursor{}, invalid
+	}
+
+	sort, err := spec.ParseSort(encoded.Sort)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	value, err := spec.ParseValue(sort.Field, encoded.Value)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	key, err := spec.ParseValue(spec.Key, encoded.Key)
+	if err != nil {
+		return Cursor{}, invalid
+	}
+	return Cursor{Sort: sort, Value: value, Key: key}, nil
+}
+
+func parseValue(kind FieldKind, raw string) (any, error) {
+	switch kind {
+	case NumberField:
+		return strconv.ParseFloat(raw, 64)
+	case TimeField:
+		return time.Parse(time.RFC3339Nano, raw)
+	}
+	return raw, nil
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case string:
+		return v
+	}
+	return ""
+}
+
+// Accessors for building Fields
+
+// Text exposes a string field
+func Text[T any](value func(T) string) Field[T] {
+	return Field[T]{Kind: StringField, Value: func(record T) any { return value(record) }}
+}
+
+// OptionalText exposes a nullable string field
+func OptionalText[T any](value func(T) *string) Field[T] {
+	return Field[T]{Kind: StringField, Value: func(record T) any {
+		if v := value(record); v != nil {
+			return *v
+		}
+		return nil
+	}}
+}
+
+// Number exposes a numeric field
+func Number[T any, N ~int | ~int64 | ~float64](value func(T) N) Field[T] {
+	return Field[T]{Kind: NumberField, Value: func(record T) any { return float64(value(record)) }}
+}
+
+// Float32 exposes a float32 field, keeping the shortest decimal form so it
+// compares equal to the DECIMAL column it was read from
+func Float32[T any](value func(T) float32) Field[T] {
+	return Field[T]{Kind: NumberField, Value: func(record T) any {
+		v, _ := strconv.ParseFloat(strconv.FormatFloat(float64(value(record)), 'f', -1, 32), 64)
+		return v
+	}}
+}
+
+// OptionalNumber exposes a nullable integer field
+func OptionalNumber[T any](value func(T) (int64, bool)) Field[T] {
+	return Field[T]{Kind: NumberField, Value: func(record T) any {
+		if v, ok := value(record); ok {
+			return float64(v)
+		}
+		return nil
+	}}
+}
+
+// OptionalInt64 exposes a nullable integer field held as a pointer
+func OptionalInt64[T any](value func(T) *int64) Field[T] {
+	return OptionalNumber(func(record T) (int64, bool) {
+		if v := value(record); v != nil {
+			return *v, true
+		}
+		return 0, false
+	})
+}
+
+// Timestamp exposes a time field
+func Timestamp[T any](value func(T) time.Time) Field[T] {
+	return Field[T]{Kind: TimeField, Value: func(record T) any { return value(record) }}
+}
@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// MachineGradingStore persists grader machine weighings
+type MachineGradingStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.MachineGrading], error)
+	Get(ctx context.Context, id string) (models.MachineGrading, error)
+	Create(ctx context.Context, grading *models.MachineGrading) error
+	// CreateMany inserts original records in one transaction. errs holds,
+	// at each record's index, why it couldn't be inserted. When atomic is
+	// set, any such failure rolls the whole batch back
+	CreateMany(ctx context.Context, gradings []*models.MachineGrading, atomic bool) (errs []error, err error)
+	// Correct stores the reversal of a record and, unless the record is
+	// only being voided, its replacement. It returns ErrNotFound when the
+	// reversed record doesn't exist and ErrConflict when it is a reversal or
+	// has already been corrected
+	Correct(ctx context.Context, reversal, replacement *models.MachineGrading) error
+	// Delete moves an original record to the trash, see TrashStore. It
+	// returns ErrConflict when the record is a reversal or replacement, or
+	// has been corrected; those stay on the ledger
+	Delete(ctx context.Context, id string) error
+	WeightSummary(ctx context.Context, stockID string) (models.MachineGradingSummary, error)
+}
+
+// MachineGradingList describes how machine grading records can be listed
+var MachineGradingList = ListSpec[models.MachineGrading]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "weight", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "color_sort_id", "size_variations_id", "pieces_id", "device_id", "entry_type", "corrects_id"},
+	Fields: map[string]Field[models.MachineGrading]{
+		"id":                 Text(func(g models.MachineGrading) string { return g.ID }),
+		"color_sort_id":      Text(func(g models.MachineGrading) string { return g.ColorSortID }),
+		"stock_id":           Text(func(g models.MachineGrading) string { return g.StockID }),
+		"size_variations_id": OptionalNumber(func(g models.MachineGrading) (int64, bool) { return g.SizeVariationsID.Int64, g.SizeVariationsID.Valid }),
+		"pieces_id":          OptionalNumber(func(g models.MachineGrading) (int64, bool) { return g.PiecesID.Int64, g.PiecesID.Valid }),
+		"weight":             Number(func(g models.MachineGrading) float64 { return g.Weight }),
+		"device_id":          OptionalInt64(func(g models.MachineGrading) *int64 { return g.DeviceID }),
+		"entry_type":         Text(func(g models.MachineGrading) string { return string(g.EntryType) }),
+		"corrects_id":        OptionalText(func(g models.MachineGrading) *string { return g.CorrectsID }),
+		"created_at":         Timestamp(func(g models.MachineGrading) time.Time { return g.CreatedAt }),
+		"updated_at":         Timestamp(func(g models.MachineGrading) time.Time { return g.UpdatedAt }),
+	},
+}
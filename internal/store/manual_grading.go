@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// ManualGradingStore persists manual grading weighings
+type ManualGradingStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.ManualGrading], error)
+	Get(ctx context.Context, id string) (models.ManualGrading, error)
+	Create(ctx context.Context, grading *models.ManualGrading) error
+	// CreateMany inserts original records in one transaction. errs holds,
+	// at each record's index, why it couldn't be inserted. When atomic is
+	// set, any such failure rolls the whole batch back
+	CreateMany(ctx context.Context, gradings []*models.ManualGrading, atomic bool) (errs []error, err error)
+	// Correct stores the reversal of a record and, unless the record is
+	// only being voided, its replacement. It returns ErrNotFound when the
+	// reversed record doesn't exist and ErrConflict when it is a reversal or
+	// has already been corrected
+	Correct(ctx context.Context, reversal, replacement *models.ManualGrading) error
+	// Delete moves an original record to the trash, see TrashStore. It
+	// returns ErrConflict when the record is a reversal or replacement, or
+	// has been corrected; those stay on the ledger
+	Delete(ctx context.Context, id string) error
+}
+
+// ManualGradingList describes how manual grading records can be listed
+var ManualGradingList = ListSpec[models.ManualGrading]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "weight", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "grader_machine_outputs_id", "category_id", "size_id", "piece_id", "worker_id", "entry_type", "corrects_id"},
+	Fields: map[string]Field[models.ManualGrading]{
+		"id":                        Text(func(g models.ManualGrading) string { return g.ID }),
+		"grader_machine_outputs_id": Text(func(g models.ManualGrading) string { return g.GraderMachineOutputsID }),
+		"stock_id":                  Text(func(g models.ManualGrading) string { return g.StockID }),
+		"category_id":               OptionalNumber(func(g models.ManualGrading) (int64, bool) { return g.CategoryID.Int64, g.CategoryID.Valid }),
+		"size_id":                   Number(func(g models.ManualGrading) int64 { return g.SizeID }),
+		"piece_id":                  OptionalNumber(func(g models.ManualGrading) (int64, bool) { return g.PieceID.Int64, g.PieceID.Valid }),
+		"weight":                    Number(func(g models.ManualGrading) int64 { return g.Weight }),
+		"worker_id":                 Text(func(g models.ManualGrading) string { return g.WorkerID }),
+		"entry_type":                Text(func(g models.ManualGrading) string { return string(g.EntryType) }),
+		"corrects_id":               OptionalText(func(g models.ManualGrading) *string { return g.CorrectsID }),
+		"created_at":                Timestamp(func(g models.ManualGrading) time.Time { return g.CreatedAt }),
+		"updated_at":                Timestamp(func(g models.ManualGrading) time.Time { return g.UpdatedAt }),
+	},
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// ManualGradingInputStore persists the weight handed to each manual grader
+type ManualGradingInputStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.ManualGradingInput], error)
+	Get(ctx context.Context, id int) (models.ManualGradingInput, error)
+	// Create inserts the record, assigning an ID when none is set
+	Create(ctx context.Context, input *models.ManualGradingInput) error
+	// CreateMany inserts original records in one transaction, assigning IDs
+	// as Create does. errs holds, at each record's index, why it couldn't be
+	// inserted. When atomic is set, any such failure rolls the whole batch
+	// back
+	CreateMany(ctx context.Context, inputs []*models.ManualGradingInput, atomic bool) (errs []error, err error)
+	// Correct stores the reversal of a record and, unless the record is
+	// only being voided, its replacement. It returns ErrNotFound when the
+	// reversed record doesn't exist and ErrConflict when it is a reversal or
+	// has already been corrected
+	Correct(ctx context.Context, reversal, replacement *models.ManualGradingInput) error
+	// Delete moves an original record to the trash, see TrashStore. It
+	// returns ErrConflict when the record is a reversal or replacement, or
+	// has been corrected; those stay on the ledger
+	Delete(ctx context.Context, id int) error
+}
+
+// ManualGradingInputList describes how manual grading inputs can be listed
+var ManualGradingInputList = ListSpec[models.ManualGradingInput]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "weight", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "worker_id", "size_variations_id", "entry_type", "corrects_id"},
+	Fields: map[string]Field[models.ManualGradingInput]{
+		"id":        Number(func(i models.ManualGradingInput) int { return i.ID }),
+		"stock_id":  Text(func(i models.ManualGradingInput) string { return i.StockID }),
+		"worker_id": Text(func(i models.ManualGradingInput) string { return i.WorkerID }),
+		"size_variations_id": OptionalNumber(func(i models.ManualGradingInput) (int64, bool) {
+			return i.SizeVariationsID.Int64, i.SizeVariationsID.Valid
+		}),
+		"weight":     Number(func(i models.ManualGradingInput) float64 { return i.Weight }),
+		"entry_type": Text(func(i models.ManualGradingInput) string { return string(i.EntryType) }),
+		"corrects_id": OptionalNumber(func(i models.ManualGradingInput) (int64, bool) {
+			if i.CorrectsID == nil {
+				return 0, false
+			}
+			return int64(*i.CorrectsID), true
+		}),
+		"created_at": Timestamp(func(i models.ManualGradingInput) time.Time { return i.CreatedAt }),
+		"updated_at": Timestamp(func(i models.ManualGradingInput) time.Time { return i.UpdatedAt }),
+	},
+}
@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// AnomalyStore implements store.AnomalyStore
+type AnomalyStore struct {
+	db *database
+}
+
+// Record appends an anomaly
+func (s *AnomalyStore) Record(ctx context.Context, anomaly *models.Anomaly) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	anomaly.ID = nextID(s.db.anomalies)
+	anomaly.CreatedAt = time.Now()
+	s.db.anomalies[anomaly.ID] = *anomaly
+	return nil
+}
+
+// List returns a page of anomalies matching opts
+func (s *AnomalyStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Anomaly], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(values(s.db.anomalies), store.AnomalyList, opts)
+}
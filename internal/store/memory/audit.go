@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"slices"
+	"time"
+)
+
+// AuditStore implements store.AuditStore
+type AuditStore struct {
+	db *database
+}
+
+// Record appends an audit entry
+func (s *AuditStore) Record(ctx context.Context, entry *models.AuditEntry) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	entry.ID = nextID(s.db.auditLog)
+	entry.Before = slices.Clone(entry.Before)
+	entry.After = slices.Clone(entry.After)
+	entry.CreatedAt = time.Now()
+	s.db.auditLog[entry.ID] = *entry
+	return nil
+}
+
+// List returns a page of audit entries matching opts
+func (s *AuditStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.AuditEntry], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(values(s.db.auditLog), store.AuditList, opts)
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// ColorSortStore implements store.ColorSortStore
+type ColorSortStore struct {
+	db *database
+}
+
+// List returns a page of color sort records matching opts
+func (s *ColorSortStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.ColorSort], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityColorSort, s.db.colorSorts), store.ColorSortList, opts)
+}
+
+// Get returns a single color sort record
+func (s *ColorSortStore) Get(ctx context.Context, id string) (models.ColorSort, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	colorSort, ok := s.db.colorSorts[id]
+	if !ok || s.db.trashed(models.EntityColorSort, id) {
+		return models.ColorSort{}, store.ErrNotFound
+	}
+	return colorSort, nil
+}
+
+// Create inserts an original color sort record
+func (s *ColorSortStore) Create(ctx context.Context, colorSort *models.ColorSort) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.colorSorts[colorSort.ID]; ok {
+		return duplicateKey(colorSort.ID)
+	}
+	colorSort.EntryType, colorSort.CorrectsID, colorSort.Reason = models.EntryOriginal, nil, ""
+	colorSort.CreatedAt = time.Now()
+	colorSort.UpdatedAt = colorSort.CreatedAt
+	s.db.colorSorts[colorSort.ID] = *colorSort
+	return nil
+}
+
+// CreateMany inserts original color sort records
+func (s *ColorSortStore) CreateMany(ctx context.Context, colorSorts []*models.ColorSort, atomic bool) ([]error, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return insertMany(s.db.colorSorts, colorSorts, atomic, func(colorSort *models.ColorSort, now time.Time) string {
+		colorSort.EntryType, colorSort.CorrectsID, colorSort.Reason = models.EntryOriginal, nil, ""
+		colorSort.CreatedAt, colorSort.UpdatedAt = now, now
+		return colorSort.ID
+	}), nil
+}
+
+// Correct stores the reversal of a color sort record and its replacement, if
+// any
+func (s *ColorSortStore) Correct(ctx context.Context, reversal, replacement *models.ColorSort) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	err := correctable(s.db, models.EntityColorSort, s.db.colorSorts, *reversal.CorrectsID, func(c models.ColorSort) (models.EntryType, *string) {
+		return c.EntryType, c.CorrectsID
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := s.db.colorSorts[reversal.ID]; ok {
+		return duplicateKey(reversal.ID)
+	}
+	if replacement != nil {
+		if _, ok := s.db.colorSorts[replacement.ID]; ok || replacement.ID == reversal.ID {
+			return duplicateKey(replacement.ID)
+		}
+	}
+
+	reversal.CreatedAt = time.Now()
+	reversal.UpdatedAt = reversal.CreatedAt
+	s.db.colorSorts[reversal.ID] = *reversal
+	if replacement != nil {
+		replacement.CreatedAt, replacement.UpdatedAt = reversal.CreatedAt, reversal.CreatedAt
+		s.db.colorSorts[replacement.ID] = *replacement
+	}
+	return nil
+}
+
+// Delete moves a color sort record to the trash
+func (s *ColorSortStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveEntryToTrash(ctx, s.db, models.EntityColorSort, s.db.colorSorts, id, func(c models.ColorSort) (models.EntryType, *string) {
+		return c.EntryType, c.CorrectsID
+	}, func(c models.ColorSort) string {
+		if c.StockID == nil {
+			return ""
+		}
+		return *c.StockID
+	})
+}
+
+// AcceptedWeightSummary totals the accepted weight for a stock and sort counter
+func (s *ColorSortStore) AcceptedWeightSummary(ctx context.Context, stockID string, counter int) (models.ColorSortSummary, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	summary := models.ColorSortSummary{StockID: stockID, SortCounter: counter}
+	for _, colorSort := range live(s.db, models.EntityColorSort, s.db.colorSorts) {
+		if colorSort.StockID != nil && *colorSort.StockID == stockID && colorSort.SortCounter == counter {
+			summary.TotalAccepted += colorSort.AcceptedWeight
+			summary.RecordCount += colorSort.EntryType.Sign()
+		}
+	}
+	return summary, nil
+}
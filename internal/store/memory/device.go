@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"slices"
+	"time"
+)
+
+// DeviceStore implements store.DeviceStore
+type DeviceStore struct {
+	db *database
+}
+
+// List returns a page of devices matching opts
+func (s *DeviceStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Device], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(values(s.db.devices), store.DeviceList, opts)
+}
+
+// Get returns a single device
+func (s *DeviceStore) Get(ctx context.Context, id int64) (models.Device, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	device, ok := s.db.devices[id]
+	if !ok {
+		return models.Device{}, store.ErrNotFound
+	}
+	return device, nil
+}
+
+// GetByKeyHash returns the device a current or rotated out key belongs to
+func (s *DeviceStore) GetByKeyHash(ctx context.Context, keyHash string) (models.Device, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	now := time.Now()
+	for _, device := range s.db.devices {
+		if device.KeyHash == keyHash {
+			return device, nil
+		}
+		if device.PreviousKeyHash != nil && *device.PreviousKeyHash == keyHash &&
+			device.PreviousKeyExpiresAt != nil && now.Before(*device.PreviousKeyExpiresAt) {
+			return device, nil
+		}
+	}
+	return models.Device{}, store.ErrNotFound
+}
+
+// Create inserts a device
+func (s *DeviceStore) Create(ctx context.Context, device *models.Device) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if err := s.checkUnique(0, *device); err != nil {
+		return err
+	}
+	for _, existing := range s.db.devices {
+		if existing.KeyHash == device.KeyHash {
+			return duplicateUnique(device.KeyHash, "uq_devices_key_hash")
+		}
+	}
+	device.ID = nextID(s.db.devices)
+	now := time.Now()
+	device.Stages = slices.Clone(device.Stages)
+	device.PreviousKeyHash, device.PreviousKeyExpiresAt, device.RevokedAt = nil, nil, nil
+	device.KeyRotatedAt, device.CreatedAt, device.UpdatedAt = now, now, now
+	s.db.devices[device.ID] = *device
+	return nil
+}
+
+// Update changes a device's name and stages
+func (s *DeviceStore) Update(ctx context.Context, id int64, device models.Device) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.devices[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if err := s.checkUnique(id, device); err != nil {
+		return err
+	}
+	existing.Name = device.Name
+	existing.Stages = slices.Clone(device.Stages)
+	existing.UpdatedAt = time.Now()
+	s.db.devices[id] = existing
+	return nil
+}
+
+// RotateKey replaces the key of a device that hasn't been revoked
+func (s *DeviceStore) RotateKey(ctx context.Context, id int64, keyHash, keyPrefix string, grace time.Duration) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	device, ok := s.db.devices[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if device.RevokedAt != nil {
+		return store.ErrConflict
+	}
+	now := time.Now()
+	device.PreviousKeyHash, device.PreviousKeyExpiresAt = nil, nil
+	if grace > 0 {
+		previous, expires := device.KeyHash, now.Add(grace)
+		device.PreviousKeyHash, device.PreviousKeyExpiresAt = &previous, &expires
+	}
+	device.KeyHash, device.KeyPrefix = keyHash, keyPrefix
+	device.KeyRotatedAt, device.UpdatedAt = now, now
+	s.db.devices[id] = device
+	return nil
+}
+
+// Revoke disables a device unless it has already been revoked
+func (s *DeviceStore) Revoke(ctx context.Context, id int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	device, ok := s.db.devices[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if device.RevokedAt != nil {
+		return store.ErrConflict
+	}
+	now := time.Now()
+	device.RevokedAt = &now
+	device.PreviousKeyHash, device.PreviousKeyExpiresAt = nil, nil
+	device.UpdatedAt = now
+	s.db.devices[id] = device
+	return nil
+}
+
+// checkUnique mirrors the unique key on devices.name
+func (s *DeviceStore) checkUnique(id int64, device models.Device) error {
+	for otherID, other := range s.db.devices {
+		if otherID != id && other.Name == device.Name {
+			return duplicateUnique(device.Name, "uq_devices_name")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// GraderMachineOutputStore implements store.GraderMachineOutputStore
+type GraderMachineOutputStore struct {
+	db *database
+}
+
+// List returns a page of grader machine outputs matching opts
+func (s *GraderMachineOutputStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.GraderMachineOutputs], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityGraderMachineOutput, s.db.graderMachineOutputs), store.GraderMachineOutputList, opts)
+}
+
+// Get returns a single grader machine output
+func (s *GraderMachineOutputStore) Get(ctx context.Context, id string) (models.GraderMachineOutputs, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	output, ok := s.db.graderMachineOutputs[id]
+	if !ok || s.db.trashed(models.EntityGraderMachineOutput, id) {
+		return models.GraderMachineOutputs{}, store.ErrNotFound
+	}
+	return output, nil
+}
+
+// Create inserts a grader machine output
+func (s *GraderMachineOutputStore) Create(ctx context.Context, output *models.GraderMachineOutputs) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.graderMachineOutputs[output.ID]; ok {
+		return duplicateKey(output.ID)
+	}
+	s.db.graderMachineOutputs[output.ID] = *output
+	return nil
+}
+
+// Update overwrites an existing grader machine output
+func (s *GraderMachineOutputStore) Update(ctx context.Context, id string, output models.GraderMachineOutputs) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.graderMachineOutputs[id]
+	if !ok || s.db.trashed(models.EntityGraderMachineOutput, id) {
+		return store.ErrNotFound
+	}
+	existing.Type = output.Type
+	s.db.graderMachineOutputs[id] = existing
+	return nil
+}
+
+// Delete moves a grader machine output to the trash
+func (s *GraderMachineOutputStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityGraderMachineOutput, s.db.graderMachineOutputs, id, func(o models.GraderMachineOutputs) string { return o.Type })
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// GradingCategoryStore implements store.GradingCategoryStore
+type GradingCategoryStore struct {
+	db *database
+}
+
+// List returns a page of grading categories matching opts
+func (s *GradingCategoryStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.GradingCategory], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityGradingCategory, s.db.gradingCategories), store.GradingCategoryList, opts)
+}
+
+// Get returns a single grading category
+func (s *GradingCategoryStore) Get(ctx context.Context, id int64) (models.GradingCategory, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	category, ok := s.db.gradingCategories[id]
+	if !ok || s.db.trashed(models.EntityGradingCategory, id) {
+		return models.GradingCategory{}, store.ErrNotFound
+	}
+	return category, nil
+}
+
+// Create inserts a grading category, assigning the next ID when none is set
+func (s *GradingCategoryStore) Create(ctx context.Context, category *models.GradingCategory) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if category.CategoryID == 0 {
+		category.CategoryID = nextID(s.db.gradingCategories)
+	}
+	if _, ok := s.db.gradingCategories[category.CategoryID]; ok {
+		return duplicateKey(category.CategoryID)
+	}
+	s.db.gradingCategories[category.CategoryID] = *category
+	return nil
+}
+
+// Update overwrites an existing grading category
+func (s *GradingCategoryStore) Update(ctx context.Context, id int64, category models.GradingCategory) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.gradingCategories[id]
+	if !ok || s.db.trashed(models.EntityGradingCategory, id) {
+		return store.ErrNotFound
+	}
+	existing.CategoryCode = category.CategoryCode
+	existing.Description = category.Description
+	s.db.gradingCategories[id] = existing
+	return nil
+}
+
+// Delete moves a grading category to the trash
+func (s *GradingCategoryStore) Delete(ctx context.Context, id int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityGradingCategory, s.db.gradingCategories, id, func(c models.GradingCategory) string { return c.CategoryCode })
+}
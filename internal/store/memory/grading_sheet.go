@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// GradingSheetStore implements store.GradingSheetStore
+type GradingSheetStore struct {
+	db *database
+}
+
+// List returns a page of grading sheets matching opts
+func (s *GradingSheetStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.GradingSheet], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityGradingSheet, s.db.gradingSheets), store.GradingSheetList, opts)
+}
+
+// Get returns a single grading sheet
+func (s *GradingSheetStore) Get(ctx context.Context, id int64) (models.GradingSheet, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	sheet, ok := s.db.gradingSheets[id]
+	if !ok || s.db.trashed(models.EntityGradingSheet, id) {
+		return models.GradingSheet{}, store.ErrNotFound
+	}
+	return sheet, nil
+}
+
+// Create inserts a grading sheet, assigning the next ID when none is set
+func (s *GradingSheetStore) Create(ctx context.Context, sheet *models.GradingSheet) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if sheet.ID == 0 {
+		sheet.ID = nextID(s.db.gradingSheets)
+	}
+	if _, ok := s.db.gradingSheets[sheet.ID]; ok {
+		return duplicateKey(sheet.ID)
+	}
+	if err := s.checkUnique(sheet.ID, *sheet); err != nil {
+		return err
+	}
+	now := time.Now()
+	sheet.CreatedAt, sheet.UpdatedAt = now, now
+	s.db.gradingSheets[sheet.ID] = *sheet
+	return nil
+}
+
+// Update overwrites an existing grading sheet
+func (s *GradingSheetStore) Update(ctx context.Context, id int64, sheet models.GradingSheet) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.gradingSheets[id]
+	if !ok || s.db.trashed(models.EntityGradingSheet, id) {
+		return store.ErrNotFound
+	}
+	if err := s.checkUnique(id, sheet); err != nil {
+		return err
+	}
+	existing.StockID = sheet.StockID
+	existing.WorkerID = sheet.WorkerID
+	existing.SizeVariationsID = sheet.SizeVariationsID
+	existing.Grades = sheet.Grades
+	existing.UpdatedAt = time.Now()
+	s.db.gradingSheets[id] = existing
+	return nil
+}
+
+// Delete moves a grading sheet to the trash
+func (s *GradingSheetStore) Delete(ctx context.Context, id int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityGradingSheet, s.db.gradingSheets, id, func(sheet models.GradingSheet) string { return sheet.StockID })
+}
+
+// checkUnique mirrors the one-sheet-per-worker-and-size unique key
+func (s *GradingSheetStore) checkUnique(id int64, sheet models.GradingSheet) error {
+	for otherID, other := range s.db.gradingSheets {
+		if otherID != id && other.StockID == sheet.StockID && other.WorkerID == sheet.WorkerID &&
+			other.SizeVariationsID == sheet.SizeVariationsID {
+			value := fmt.Sprintf("%s-%s-%d", sheet.StockID, sheet.WorkerID, sheet.SizeVariationsID)
+			return duplicateUnique(value, "uq_grading_sheets_worker_size")
+		}
+	}
+	return nil
+}
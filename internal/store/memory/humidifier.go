@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// HumidifierStore implements store.HumidifierStore
+type HumidifierStore struct {
+	db *database
+}
+
+// List returns a page of humidifier records matching opts
+func (s *HumidifierStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Humidifier], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityHumidifier, s.db.humidifiers), store.HumidifierList, opts)
+}
+
+// Get returns a single humidifier record
+func (s *HumidifierStore) Get(ctx context.Context, id string) (models.Humidifier, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	humidifier, ok := s.db.humidifiers[id]
+	if !ok || s.db.trashed(models.EntityHumidifier, id) {
+		return models.Humidifier{}, store.ErrNotFound
+	}
+	return humidifier, nil
+}
+
+// Create inserts an original humidifier record
+func (s *HumidifierStore) Create(ctx context.Context, humidifier *models.Humidifier) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.humidifiers[humidifier.ID]; ok {
+		return duplicateKey(humidifier.ID)
+	}
+	humidifier.EntryType, humidifier.CorrectsID, humidifier.Reason = models.EntryOriginal, nil, ""
+	humidifier.CreatedAt = time.Now()
+	humidifier.UpdatedAt = humidifier.CreatedAt
+	s.db.humidifiers[humidifier.ID] = *humidifier
+	return nil
+}
+
+// CreateMany inserts original humidifier records
+func (s *HumidifierStore) CreateMany(ctx context.Context, humidifiers []*models.Humidifier, atomic bool) ([]error, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return insertMany(s.db.humidifiers, humidifiers, atomic, func(humidifier *models.Humidifier, now time.Time) string {
+		humidifier.EntryType, humidifier.CorrectsID, humidifier.Reason = models.EntryOriginal, nil, ""
+		humidifier.CreatedAt, humidifier.UpdatedAt = now, now
+		return humidifier.ID
+	}), nil
+}
+
+// Correct stores the reversal of a humidifier record and its replacement, if
+// any
+func (s *HumidifierStore) Correct(ctx context.Context, reversal, replacement *models.Humidifier) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	err := correctable(s.db, models.EntityHumidifier, s.db.humidifiers, *reversal.CorrectsID, func(h models.Humidifier) (models.EntryType, *string) {
+		return h.EntryType, h.CorrectsID
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := s.db.humidifiers[reversal.ID]; ok {
+		return duplicateKey(reversal.ID)
+	}
+	if replacement != nil {
+		if _, ok := s.db.humidifiers[replacement.ID]; ok || replacement.ID == reversal.ID {
+			return duplicateKey(replacement.ID)
+		}
+	}
+
+	reversal.CreatedAt = time.Now()
+	reversal.UpdatedAt = reversal.CreatedAt
+	s.db.humidifiers[reversal.ID] = *reversal
+	if replacement != nil {
+		replacement.CreatedAt, replacement.UpdatedAt = reversal.CreatedAt, reversal.CreatedAt
+		s.db.humidifiers[replacement.ID] = *replacement
+	}
+	return nil
+}
+
+// Delete moves a humidifier record to the trash
+func (s *HumidifierStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveEntryToTrash(ctx, s.db, models.EntityHumidifier, s.db.humidifiers, id, func(h models.Humidifier) (models.EntryType, *string) {
+		return h.EntryType, h.CorrectsID
+	}, func(h models.Humidifier) string { return h.StockID })
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// idempotencyKey identifies a stored Idempotency-Key
+type idempotencyKey struct {
+	caller string
+	key    string
+}
+
+// IdempotencyStore implements store.IdempotencyStore
+type IdempotencyStore struct {
+	db *database
+}
+
+// Claim stores a key as in progress unless it is already held
+func (s *IdempotencyStore) Claim(ctx context.Context, key models.IdempotencyKey, expired, abandoned time.Time) (models.IdempotencyKey, bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	id := idempotencyKey{key.Caller, key.Key}
+	if existing, ok := s.db.idempotencyKeys[id]; ok && held(existing, expired, abandoned) {
+		return existing, false, nil
+	}
+	key.StatusCode, key.Response = 0, nil
+	key.CreatedAt = time.Now()
+	s.db.idempotencyKeys[id] = key
+	return key, true, nil
+}
+
+// held reports whether a stored key still keeps others from claiming it
+func held(key models.IdempotencyKey, expired, abandoned time.Time) bool {
+	if key.Completed() {
+		return key.CreatedAt.After(expired)
+	}
+	return key.CreatedAt.After(abandoned)
+}
+
+// claimed reports whether claim is still the stored, unfinished claim
+func (s *IdempotencyStore) claimed(claim models.IdempotencyKey) bool {
+	stored, ok := s.db.idempotencyKeys[idempotencyKey{claim.Caller, claim.Key}]
+	return ok && !stored.Completed() && stored.CreatedAt.Equal(claim.CreatedAt)
+}
+
+// Complete stores the response to a claimed key's request
+func (s *IdempotencyStore) Complete(ctx context.Context, claim models.IdempotencyKey, statusCode int, response []byte) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if !s.claimed(claim) {
+		return store.ErrNotFound
+	}
+	claim.StatusCode, claim.Response = statusCode, response
+	s.db.idempotencyKeys[idempotencyKey{claim.Caller, claim.Key}] = claim
+	return nil
+}
+
+// Release forgets a claimed key
+func (s *IdempotencyStore) Release(ctx context.Context, claim models.IdempotencyKey) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if s.claimed(claim) {
+		delete(s.db.idempotencyKeys, idempotencyKey{claim.Caller, claim.Key})
+	}
+	return nil
+}
+
+// Sweep forgets the keys claimed before expired
+func (s *IdempotencyStore) Sweep(ctx context.Context, expired time.Time) (int64, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	var swept int64
+	for id, key := range s.db.idempotencyKeys {
+		if !key.CreatedAt.After(expired) {
+			delete(s.db.idempotencyKeys, id)
+			swept++
+		}
+	}
+	return swept, nil
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// correctable mirrors the checks the MySQL stores make before storing a
+// correction of the record id: it must exist outside the trash, not be a
+// reversal and not have been reversed already
+func correctable[K comparable, T any](db *database, entity string, rows map[K]T, id K, entry func(T) (models.EntryType, *K)) error {
+	record, ok := rows[id]
+	if !ok || db.trashed(entity, id) {
+		return store.ErrNotFound
+	}
+	if entryType, _ := entry(record); !entryType.Correctable() {
+		return store.ErrConflict
+	}
+	for _, other := range rows {
+		entryType, corrects := entry(other)
+		if entryType == models.EntryReversal && corrects != nil && *corrects == id {
+			return store.ErrConflict
+		}
+	}
+	return nil
+}
+
+// moveEntryToTrash mirrors the MySQL stage stores' Delete: only an original
+// record nothing corrects goes to the trash
+func moveEntryToTrash[K comparable, T any](ctx context.Context, db *database, entity string, rows map[K]T, id K, entry func(T) (models.EntryType, *K), label func(T) string) error {
+	record, ok := rows[id]
+	if !ok || db.trashed(entity, id) {
+		return store.ErrNotFound
+	}
+	if entryType, _ := entry(record); entryType != models.EntryOriginal {
+		return store.ErrConflict
+	}
+	for _, other := range rows {
+		if _, corrects := entry(other); corrects != nil && *corrects == id {
+			return store.ErrConflict
+		}
+	}
+	return moveToTrash(ctx, db, entity, rows, id, label)
+}
+
+// insertMany mirrors a batch insert into a stage table: records whose key
+// is taken, by an existing row or an earlier record in the batch, fail with
+// a duplicate key error. When atomic is set nothing is inserted if any
+// record fails. prepare stamps a record as an original created at now and
+// returns its key
+func insertMany[K comparable, T any](rows map[K]T, records []*T, atomic bool, prepare func(record *T, now time.Time) K) []error {
+	now := time.Now()
+	errs := make([]error, len(records))
+	batch := map[K]bool{}
+	failed := false
+	for i, record := range records {
+		key := prepare(record, now)
+		if _, ok := rows[key]; ok || batch[key] {
+			errs[i], failed = duplicateKey(key), true
+			continue
+		}
+		batch[key] = true
+	}
+	if atomic && failed {
+		return errs
+	}
+
+	for i, record := range records {
+		if errs[i] == nil {
+			rows[prepare(record, now)] = *record
+		}
+	}
+	return errs
+}
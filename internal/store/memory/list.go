@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"fmt"
+	"healing_photons/internal/store"
+	"sort"
+	"strings"
+	"time"
+)
+
+// page applies opts to records the same way the MySQL stores do
+func page[T any](records []T, spec store.ListSpec[T], opts store.ListOptions) (store.Page[T], error) {
+	var result store.Page[T]
+
+	for _, filter := range opts.Filters {
+		field, ok := spec.Fields[filter.Field]
+		if !ok {
+			return result, fmt.Errorf("unknown field %q", filter.Field)
+		}
+		records = where(records, func(record T) bool {
+			value := field.Value(record)
+			if filter.Value == nil {
+				return value == nil
+			}
+			return value != nil && compare(value, filter.Value) == 0
+		})
+	}
+	if !opts.From.IsZero() || !opts.To.IsZero() {
+		if spec.TimeField == "" {
+			return result, fmt.Errorf("records cannot be filtered by time")
+		}
+		timeField := spec.Fields[spec.TimeField]
+		records = where(records, func(record T) bool {
+			at := timeField.Value(record).(time.Time)
+			return (opts.From.IsZero() || !at.Before(opts.From)) &&
+				(opts.To.IsZero() || at.Before(opts.To))
+		})
+	}
+	result.Total = len(records)
+
+	order := spec.SortOrDefault(opts)
+	sortField, ok := spec.Fields[order.Field]
+	if !ok {
+		return result, fmt.Errorf("unknown field %q", order.Field)
+	}
+	keyField := spec.Fields[spec.Key]
+
+	// position orders a record against a sort value and key, honouring the
+	// sort direction
+	position := func(record T, value, key any) int {
+		c := compare(sortField.Value(record), value)
+		if c == 0 {
+			c = compare(keyField.Value(record), key)
+		}
+		if order.Desc {
+			return -c
+		}
+		return c
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		other := records[j]
+		return position(records[i], sortField.Value(other), keyField.Value(other)) < 0
+	})
+
+	if after := opts.After; after != nil {
+		if after.Sort != order {
+			return result, fmt.Errorf("cursor was issued for sort %s", after.Sort)
+		}
+		records = where(records, func(record T) bool {
+			return position(record, after.Value, after.Key) > 0
+		})
+	}
+
+	if opts.Limit > 0 && len(records) > opts.Limit {
+		records = records[:opts.Limit]
+		result.Next = spec.CursorAfter(records[opts.Limit-1], order)
+	}
+	result.Items = records
+	return result, nil
+}
+
+// compare orders two field values of the same kind, with NULL first
+func compare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+
+	switch x := a.(type) {
+	case string:
+		return strings.Compare(x, b.(string))
+	case float64:
+		y := b.(float64)
+		if x < y {
+			return -1
+		} else if x > y {
+			return 1
+		}
+		return 0
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	}
+	return 0
+}
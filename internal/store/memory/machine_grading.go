@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// MachineGradingStore implements store.MachineGradingStore
+type MachineGradingStore struct {
+	db *database
+}
+
+// List returns a page of machine grading records matching opts
+func (s *MachineGradingStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.MachineGrading], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityMachineGrading, s.db.machineGradings), store.MachineGradingList, opts)
+}
+
+// Get returns a single machine grading record
+func (s *MachineGradingStore) Get(ctx context.Context, id string) (models.MachineGrading, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	grading, ok := s.db.machineGradings[id]
+	if !ok || s.db.trashed(models.EntityMachineGrading, id) {
+		return models.MachineGrading{}, store.ErrNotFound
+	}
+	return grading, nil
+}
+
+// Create inserts an original machine grading record
+func (s *MachineGradingStore) Create(ctx context.Context, grading *models.MachineGrading) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.machineGradings[grading.ID]; ok {
+		return duplicateKey(grading.ID)
+	}
+	grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+	grading.CreatedAt = time.Now()
+	grading.UpdatedAt = grading.CreatedAt
+	s.db.machineGradings[grading.ID] = *grading
+	return nil
+}
+
+// CreateMany inserts original machine grading records
+func (s *MachineGradingStore) CreateMany(ctx context.Context, gradings []*models.MachineGrading, atomic bool) ([]error, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return insertMany(s.db.machineGradings, gradings, atomic, func(grading *models.MachineGrading, now time.Time) string {
+		grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+		grading.CreatedAt, grading.UpdatedAt = now, now
+		return grading.ID
+	}), nil
+}
+
+// Correct stores the reversal of a machine grading record and its replacement, if
+// any
+func (s *MachineGradingStore) Correct(ctx context.Context, reversal, replacement *models.MachineGrading) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	err := correctable(s.db, models.EntityMachineGrading, s.db.machineGradings, *reversal.CorrectsID, func(g models.MachineGrading) (models.EntryType, *string) {
+		return g.EntryType, g.CorrectsID
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := s.db.machineGradings[reversal.ID]; ok {
+		return duplicateKey(reversal.ID)
+	}
+	if replacement != nil {
+		if _, ok := s.db.machineGradings[replacement.ID]; ok || replacement.ID == reversal.ID {
+			return duplicateKey(replacement.ID)
+		}
+	}
+
+	reversal.CreatedAt = time.Now()
+	reversal.UpdatedAt = reversal.CreatedAt
+	s.db.machineGradings[reversal.ID] = *reversal
+	if replacement != nil {
+		replacement.CreatedAt, replacement.UpdatedAt = reversal.CreatedAt, reversal.CreatedAt
+		s.db.machineGradings[replacement.ID] = *replacement
+	}
+	return nil
+}
+
+// Delete moves a machine grading record to the trash
+func (s *MachineGradingStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveEntryToTrash(ctx, s.db, models.EntityMachineGrading, s.db.machineGradings, id, func(g models.MachineGrading) (models.EntryType, *string) {
+		return g.EntryType, g.CorrectsID
+	}, func(g models.MachineGrading) string { return g.StockID })
+}
+
+// WeightSummary totals the graded weight for a stock
+func (s *MachineGradingStore) WeightSummary(ctx context.Context, stockID string) (models.MachineGradingSummary, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	summary := models.MachineGradingSummary{StockID: stockID}
+	for _, grading := range live(s.db, models.EntityMachineGrading, s.db.machineGradings) {
+		if grading.StockID == stockID {
+			summary.TotalWeight += grading.Weight
+			summary.RecordCount += grading.EntryType.Sign()
+		}
+	}
+	return summary, nil
+}
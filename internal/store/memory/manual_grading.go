@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// ManualGradingStore implements store.ManualGradingStore
+type ManualGradingStore struct {
+	db *database
+}
+
+// List returns a page of manual grading records matching opts
+func (s *ManualGradingStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.ManualGrading], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityManualGrading, s.db.manualGradings), store.ManualGradingList, opts)
+}
+
+// Get returns a single manual grading record
+func (s *ManualGradingStore) Get(ctx context.Context, id string) (models.ManualGrading, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	grading, ok := s.db.manualGradings[id]
+	if !ok || s.db.trashed(models.EntityManualGrading, id) {
+		return models.ManualGrading{}, store.ErrNotFound
+	}
+	return grading, nil
+}
+
+// Create inserts an original manual grading record
+func (s *ManualGradingStore) Create(ctx context.Context, grading *models.ManualGrading) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.manualGradings[grading.ID]; ok {
+		return duplicateKey(grading.ID)
+	}
+	grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+	grading.CreatedAt = time.Now()
+	grading.UpdatedAt = grading.CreatedAt
+	s.db.manualGradings[grading.ID] = *grading
+	return nil
+}
+
+// CreateMany inserts original manual grading records
+func (s *ManualGradingStore) CreateMany(ctx context.Context, gradings []*models.ManualGrading, atomic bool) ([]error, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return insertMany(s.db.manualGradings, gradings, atomic, func(grading *models.ManualGrading, now time.Time) string {
+		grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+		grading.CreatedAt, grading.UpdatedAt = now, now
+		return grading.ID
+	}), nil
+}
+
+// Correct stores the reversal of a manual grading record and its replacement, if
+// any
+func (s *ManualGradingStore) Correct(ctx context.Context, reversal, replacement *models.ManualGrading) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	err := correctable(s.db, models.EntityManualGrading, s.db.manualGradings, *reversal.CorrectsID, func(g models.ManualGrading) (models.EntryType, *string) {
+		return g.EntryType, g.CorrectsID
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := s.db.manualGradings[reversal.ID]; ok {
+		return duplicateKey(reversal.ID)
+	}
+	if replacement != nil {
+		if _, ok := s.db.manualGradings[replacement.ID]; ok || replacement.ID == reversal.ID {
+			return duplicateKey(replacement.ID)
+		}
+	}
+
+	reversal.CreatedAt = time.Now()
+	reversal.UpdatedAt = reversal.CreatedAt
+	s.db.manualGradings[reversal.ID] = *reversal
+	if replacement != nil {
+		replacement.CreatedAt, replacement.UpdatedAt = reversal.CreatedAt, reversal.CreatedAt
+		s.db.manualGradings[replacement.ID] = *replacement
+	}
+	return nil
+}
+
+// Delete moves a manual grading record to the trash
+func (s *ManualGradingStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveEntryToTrash(ctx, s.db, models.EntityManualGrading, s.db.manualGradings, id, func(g models.ManualGrading) (models.EntryType, *string) {
+		return g.EntryType, g.CorrectsID
+	}, func(g models.ManualGrading) string { return g.StockID })
+}
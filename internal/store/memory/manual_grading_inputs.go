@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// ManualGradingInputStore implements store.ManualGradingInputStore
+type ManualGradingInputStore struct {
+	db *database
+}
+
+// List returns a page of manual grading input records matching opts
+func (s *ManualGradingInputStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.ManualGradingInput], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityManualGradingInput, s.db.manualGradingInputs), store.ManualGradingInputList, opts)
+}
+
+// Get returns a single manual grading input record
+func (s *ManualGradingInputStore) Get(ctx context.Context, id int) (models.ManualGradingInput, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	input, ok := s.db.manualGradingInputs[id]
+	if !ok || s.db.trashed(models.EntityManualGradingInput, id) {
+		return models.ManualGradingInput{}, store.ErrNotFound
+	}
+	return input, nil
+}
+
+// Create inserts an original manual grading input record, assigning the
+// next ID when none is set
+func (s *ManualGradingInputStore) Create(ctx context.Context, input *models.ManualGradingInput) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if input.ID == 0 {
+		input.ID = nextID(s.db.manualGradingInputs)
+	}
+	if _, ok := s.db.manualGradingInputs[input.ID]; ok {
+		return duplicateKey(input.ID)
+	}
+	input.EntryType, input.CorrectsID, input.Reason = models.EntryOriginal, nil, ""
+	input.CreatedAt = time.Now()
+	input.UpdatedAt = input.CreatedAt
+	s.db.manualGradingInputs[input.ID] = *input
+	return nil
+}
+
+// CreateMany inserts original manual grading input records, numbering
+// those without an ID after the highest one stored or sent
+func (s *ManualGradingInputStore) CreateMany(ctx context.Context, inputs []*models.ManualGradingInput, atomic bool) ([]error, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	next := nextID(s.db.manualGradingInputs)
+	for _, input := range inputs {
+		next = max(next, input.ID+1)
+	}
+	for _, input := range inputs {
+		if input.ID == 0 {
+			input.ID, next = next, next+1
+		}
+	}
+	return insertMany(s.db.manualGradingInputs, inputs, atomic, func(input *models.ManualGradingInput, now time.Time) int {
+		input.EntryType, input.CorrectsID, input.Reason = models.EntryOriginal, nil, ""
+		input.CreatedAt, input.UpdatedAt = now, now
+		return input.ID
+	}), nil
+}
+
+// Correct stores the reversal of a manual grading input record and its
+// replacement, if any
+func (s *ManualGradingInputStore) Correct(ctx context.Context, reversal, replacement *models.ManualGradingInput) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	err := correctable(s.db, models.EntityManualGradingInput, s.db.manualGradingInputs, *reversal.CorrectsID, func(i models.ManualGradingInput) (models.EntryType, *int) {
+		return i.EntryType, i.CorrectsID
+	})
+	if err != nil {
+		return err
+	}
+	reversal.ID = nextID(s.db.manualGradingInputs)
+	if replacement != nil {
+		replacement.ID = reversal.ID + 1
+	}
+
+	reversal.CreatedAt = time.Now()
+	reversal.UpdatedAt = reversal.CreatedAt
+	s.db.manualGradingInputs[reversal.ID] = *reversal
+	if replacement != nil {
+		replacement.CreatedAt, replacement.UpdatedAt = reversal.CreatedAt, reversal.CreatedAt
+		s.db.manualGradingInputs[replacement.ID] = *replacement
+	}
+	return nil
+}
+
+// Delete moves a manual grading input record to the trash
+func (s *ManualGradingInputStore) Delete(ctx context.Context, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveEntryToTrash(ctx, s.db, models.EntityManualGradingInput, s.db.manualGradingInputs, id, func(i models.ManualGradingInput) (models.EntryType, *int) {
+		return i.EntryType, i.CorrectsID
+	}, func(i models.ManualGradingInput) string { return i.StockID })
+}
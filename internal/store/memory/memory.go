@@ -0,0 +1,173 @@
+// Package memory implements the storage interfaces in process memory. It is
+// meant for tests and local development, not for production data.
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// database holds every table behind a single lock so stores can read across
+// entities consistently
+type database struct {
+	mu sync.RWMutex
+	tables
+}
+
+// tables are the rows of every entity, kept apart from the lock so a unit
+// of work can take a copy of them and put it back
+type tables struct {
+	stocks               map[string]models.Stock
+	stockTransitions     map[int64]models.StockTransition
+	humidifiers          map[string]models.Humidifier
+	peelingMachines      map[string]models.PeelingMachine
+	colorSorts           map[string]models.ColorSort
+	machineGradings      map[string]models.MachineGrading
+	manualGradings       map[string]models.ManualGrading
+	manualGradingInputs  map[int]models.ManualGradingInput
+	gradingSheets        map[int64]models.GradingSheet
+	graderMachineOutputs map[string]models.GraderMachineOutputs
+	gradingCategories    map[int64]models.GradingCategory
+	pieces               map[int]models.Pieces
+	sizeVariations       map[int]models.SizeVariations
+	weightTypes          map[string]models.WeightTypes
+	workforce            map[string]models.Workforce
+	users                map[int64]models.User
+	sessions             map[int64]models.Session
+	sellers              map[int64]models.Seller
+	devices              map[int64]models.Device
+	auditLog             map[int64]models.AuditEntry
+	anomalies            map[int64]models.Anomaly
+	trash                map[trashKey]models.TrashItem
+	sequences            map[string]int64
+	idempotencyKeys      map[idempotencyKey]models.IdempotencyKey
+}
+
+// NewStores returns empty in-memory stores for every entity
+func NewStores() *store.Stores {
+	db := &database{tables: tables{
+		stocks:               map[string]models.Stock{},
+		stockTransitions:     map[int64]models.StockTransition{},
+		humidifiers:          map[string]models.Humidifier{},
+		peelingMachines:      map[string]models.PeelingMachine{},
+		colorSorts:           map[string]models.ColorSort{},
+		machineGradings:      map[string]models.MachineGrading{},
+		manualGradings:       map[string]models.ManualGrading{},
+		manualGradingInputs:  map[int]models.ManualGradingInput{},
+		gradingSheets:        map[int64]models.GradingSheet{},
+		graderMachineOutputs: map[string]models.GraderMachineOutputs{},
+		gradingCategories:    map[int64]models.GradingCategory{},
+		pieces:               map[int]models.Pieces{},
+		sizeVariations:       map[int]models.SizeVariations{},
+		weightTypes:          map[string]models.WeightTypes{},
+		workforce:            map[string]models.Workforce{},
+		users:                map[int64]models.User{},
+		sessions:             map[int64]models.Session{},
+		sellers:              map[int64]models.Seller{},
+		devices:              map[int64]models.Device{},
+		auditLog:             map[int64]models.AuditEntry{},
+		anomalies:            map[int64]models.Anomaly{},
+		trash:                map[trashKey]models.TrashItem{},
+		sequences:            map[string]int64{},
+		idempotencyKeys:      map[idempotencyKey]models.IdempotencyKey{},
+	}}
+
+	stores := &store.Stores{
+		Stocks:               &StockStore{db: db},
+		Humidifiers:          &HumidifierStore{db: db},
+		PeelingMachines:      &PeelingMachineStore{db: db},
+		ColorSorts:           &ColorSortStore{db: db},
+		MachineGradings:      &MachineGradingStore{db: db},
+		ManualGradings:       &ManualGradingStore{db: db},
+		ManualGradingInputs:  &ManualGradingInputStore{db: db},
+		GradingSheets:        &GradingSheetStore{db: db},
+		GraderMachineOutputs: &GraderMachineOutputStore{db: db},
+		GradingCategories:    &GradingCategoryStore{db: db},
+		Pieces:               &PieceStore{db: db},
+		SizeVariations:       &SizeVariationStore{db: db},
+		WeightTypes:          &WeightTypeStore{db: db},
+		Workforce:            &WorkforceStore{db: db},
+		Users:                &UserStore{db: db},
+		Sessions:             &SessionStore{db: db},
+		Sellers:              &SellerStore{db: db},
+		Devices:              &DeviceStore{db: db},
+		Audit:                &AuditStore{db: db},
+		Anomalies:            &AnomalyStore{db: db},
+		Trash:                &TrashStore{db: db},
+		Sequences:            &SequenceStore{db: db},
+		Idempotency:          &IdempotencyStore{db: db},
+		Reports:              &ReportStore{db: db},
+	}
+	stores.UnitOfWork = &UnitOfWork{db: db, stores: stores}
+	return stores
+}
+
+// duplicateKey mirrors the error MySQL reports for a primary key clash
+func duplicateKey(id any) error {
+	return &store.ConstraintError{Constraint: store.ConstraintUnique, Key: "PRIMARY", Value: fmt.Sprint(id)}
+}
+
+// duplicateUnique mirrors the error MySQL reports for a unique key clash
+func duplicateUnique(value any, key string) error {
+	return &store.ConstraintError{Constraint: store.ConstraintUnique, Key: key, Value: fmt.Sprint(value)}
+}
+
+// values returns the rows of a table ordered by primary key, matching the
+// clustered index order MySQL returns without an ORDER BY
+func values[K cmp.Ordered, T any](rows map[K]T) []T {
+	sorted := keys(rows)
+	records := make([]T, 0, len(sorted))
+	for _, key := range sorted {
+		records = append(records, rows[key])
+	}
+	return records
+}
+
+// keys returns the primary keys of a table in order
+func keys[K cmp.Ordered, T any](rows map[K]T) []K {
+	sorted := make([]K, 0, len(rows))
+	for key := range rows {
+		sorted = append(sorted, key)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+// where returns the records for which keep is true, never nil
+func where[T any](records []T, keep func(T) bool) []T {
+	matched := []T{}
+	for _, record := range records {
+		if keep(record) {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+// nextID returns one more than the largest key in use
+func nextID[K int | int64, T any](rows map[K]T) K {
+	var highest K
+	for key := range rows {
+		if key > highest {
+			highest = key
+		}
+	}
+	return highest + 1
+}
+
+// nextNumericID returns one more than the largest numeric string key in use,
+// emulating an auto-increment column exposed as a string
+func nextNumericID[T any](rows map[string]T) string {
+	var highest int64
+	for key := range rows {
+		if n, err := strconv.ParseInt(key, 10, 64); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return strconv.FormatInt(highest+1, 10)
+}
@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// PeelingMachineStore implements store.PeelingMachineStore
+type PeelingMachineStore struct {
+	db *database
+}
+
+// List returns a page of peeling machine records matching opts
+func (s *PeelingMachineStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.PeelingMachine], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityPeelingMachine, s.db.peelingMachines), store.PeelingMachineList, opts)
+}
+
+// Get returns a single peeling machine record
+func (s *PeelingMachineStore) Get(ctx context.Context, id string) (models.PeelingMachine, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	machine, ok := s.db.peelingMachines[id]
+	if !ok || s.db.trashed(models.EntityPeelingMachine, id) {
+		return models.PeelingMachine{}, store.ErrNotFound
+	}
+	return machine, nil
+}
+
+// Create inserts an original peeling machine record
+func (s *PeelingMachineStore) Create(ctx context.Context, machine *models.PeelingMachine) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.peelingMachines[machine.ID]; ok {
+		return duplicateKey(machine.ID)
+	}
+	machine.EntryType, machine.CorrectsID, machine.Reason = models.EntryOriginal, nil, ""
+	machine.CreatedAt = time.Now()
+	machine.UpdatedAt = machine.CreatedAt
+	s.db.peelingMachines[machine.ID] = *machine
+	return nil
+}
+
+// CreateMany inserts original peeling machine records
+func (s *PeelingMachineStore) CreateMany(ctx context.Context, machines []*models.PeelingMachine, atomic bool) ([]error, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return insertMany(s.db.peelingMachines, machines, atomic, func(machine *models.PeelingMachine, now time.Time) string {
+		machine.EntryType, machine.CorrectsID, machine.Reason = models.EntryOriginal, nil, ""
+		machine.CreatedAt, machine.UpdatedAt = now, now
+		return machine.ID
+	}), nil
+}
+
+// Correct stores the reversal of a peeling machine record and its replacement, if
+// any
+func (s *PeelingMachineStore) Correct(ctx context.Context, reversal, replacement *models.PeelingMachine) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	err := correctable(s.db, models.EntityPeelingMachine, s.db.peelingMachines, *reversal.CorrectsID, func(m models.PeelingMachine) (models.EntryType, *string) {
+		return m.EntryType, m.CorrectsID
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := s.db.peelingMachines[reversal.ID]; ok {
+		return duplicateKey(reversal.ID)
+	}
+	if replacement != nil {
+		if _, ok := s.db.peelingMachines[replacement.ID]; ok || replacement.ID == reversal.ID {
+			return duplicateKey(replacement.ID)
+		}
+	}
+
+	reversal.CreatedAt = time.Now()
+	reversal.UpdatedAt = reversal.CreatedAt
+	s.db.peelingMachines[reversal.ID] = *reversal
+	if replacement != nil {
+		replacement.CreatedAt, replacement.UpdatedAt = reversal.CreatedAt, reversal.CreatedAt
+		s.db.peelingMachines[replacement.ID] = *replacement
+	}
+	return nil
+}
+
+// Delete moves a peeling machine record to the trash
+func (s *PeelingMachineStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveEntryToTrash(ctx, s.db, models.EntityPeelingMachine, s.db.peelingMachines, id, func(m models.PeelingMachine) (models.EntryType, *string) {
+		return m.EntryType, m.CorrectsID
+	}, func(m models.PeelingMachine) string {
+		if m.StockID == nil {
+			return ""
+		}
+		return *m.StockID
+	})
+}
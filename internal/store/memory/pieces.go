@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// PieceStore implements store.PieceStore
+type PieceStore struct {
+	db *database
+}
+
+// List returns a page of pieces matching opts
+func (s *PieceStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Pieces], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityPiece, s.db.pieces), store.PieceList, opts)
+}
+
+// Get returns a single piece
+func (s *PieceStore) Get(ctx context.Context, id int) (models.Pieces, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	piece, ok := s.db.pieces[id]
+	if !ok || s.db.trashed(models.EntityPiece, id) {
+		return models.Pieces{}, store.ErrNotFound
+	}
+	return piece, nil
+}
+
+// Create inserts a piece, assigning the next ID when none is set
+func (s *PieceStore) Create(ctx context.Context, piece *models.Pieces) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if piece.PieceID == 0 {
+		piece.PieceID = nextID(s.db.pieces)
+	}
+	if _, ok := s.db.pieces[piece.PieceID]; ok {
+		return duplicateKey(piece.PieceID)
+	}
+	s.db.pieces[piece.PieceID] = *piece
+	return nil
+}
+
+// Update overwrites an existing piece
+func (s *PieceStore) Update(ctx context.Context, id int, piece models.Pieces) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.pieces[id]
+	if !ok || s.db.trashed(models.EntityPiece, id) {
+		return store.ErrNotFound
+	}
+	existing.PieceCode = piece.PieceCode
+	existing.Description = piece.Description
+	s.db.pieces[id] = existing
+	return nil
+}
+
+// Delete moves a piece to the trash
+func (s *PieceStore) Delete(ctx context.Context, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityPiece, s.db.pieces, id, func(p models.Pieces) string { return p.PieceCode })
+}
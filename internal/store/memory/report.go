@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"sort"
+)
+
+// ReportStore implements store.ReportStore
+type ReportStore struct {
+	db *database
+}
+
+// StageTotals sums the records of every processing stage for a lot.
+// Reversals count as minus one record
+func (s *ReportStore) StageTotals(ctx context.Context, stockID string) (models.StockStageTotals, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	totals := models.StockStageTotals{StockID: stockID}
+	add := func(total *models.StageTotal, weight float64, entryType models.EntryType) {
+		total.Weight += weight
+		total.Records += entryType.Sign()
+	}
+	for _, record := range live(s.db, models.EntityHumidifier, s.db.humidifiers) {
+		if record.StockID == stockID {
+			add(&totals.Humidifier, float64(record.Weight), record.EntryType)
+		}
+	}
+	for _, record := range live(s.db, models.EntityPeelingMachine, s.db.peelingMachines) {
+		if record.StockID != nil && *record.StockID == stockID {
+			add(&totals.PeelingMachine, record.Weight, record.EntryType)
+		}
+	}
+	for _, record := range live(s.db, models.EntityColorSort, s.db.colorSorts) {
+		if record.StockID != nil && *record.StockID == stockID {
+			add(&totals.ColorSort, record.AcceptedWeight, record.EntryType)
+		}
+	}
+	for _, record := range live(s.db, models.EntityMachineGrading, s.db.machineGradings) {
+		if record.StockID == stockID {
+			add(&totals.MachineGrading, record.Weight, record.EntryType)
+		}
+	}
+	for _, record := range live(s.db, models.EntityManualGradingInput, s.db.manualGradingInputs) {
+		if record.StockID == stockID {
+			add(&totals.MachineGradingInputs, record.Weight, record.EntryType)
+		}
+	}
+	for _, record := range live(s.db, models.EntityManualGrading, s.db.manualGradings) {
+		if record.StockID == stockID {
+			add(&totals.ManualGrading, float64(record.Weight), record.EntryType)
+		}
+	}
+	return totals, nil
+}
+
+// GradeWeights sums the manual grading of each lot by grading category
+func (s *ReportStore) GradeWeights(ctx context.Context, stockIDs []string) ([]models.GradeWeight, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(stockIDs))
+	for _, id := range stockIDs {
+		wanted[id] = true
+	}
+	type group struct{ stockID, code string }
+	totals := make(map[group]models.GradeWeight)
+	for _, record := range live(s.db, models.EntityManualGrading, s.db.manualGradings) {
+		if !wanted[record.StockID] {
+			continue
+		}
+		key := group{stockID: record.StockID}
+		if record.CategoryID.Valid {
+			key.code = s.db.gradingCategories[record.CategoryID.Int64].CategoryCode
+		}
+		total := totals[key]
+		total.StockID, total.CategoryCode = key.stockID, key.code
+		total.Weight += float64(record.Weight)
+		total.Records += record.EntryType.Sign()
+		totals[key] = total
+	}
+
+	grades := make([]models.GradeWeight, 0, len(totals))
+	for _, total := range totals {
+		grades = append(grades, total)
+	}
+	sort.Slice(grades, func(i, j int) bool {
+		if grades[i].StockID != grades[j].StockID {
+			return grades[i].StockID < grades[j].StockID
+		}
+		return grades[i].CategoryCode < grades[j].CategoryCode
+	})
+	return grades, nil
+}
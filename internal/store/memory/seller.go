@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// SellerStore implements store.SellerStore
+type SellerStore struct {
+	db *database
+}
+
+// List returns a page of sellers matching opts
+func (s *SellerStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Seller], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntitySeller, s.db.sellers), store.SellerList, opts)
+}
+
+// Get returns a single seller
+func (s *SellerStore) Get(ctx context.Context, id int64) (models.Seller, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	seller, ok := s.db.sellers[id]
+	if !ok || s.db.trashed(models.EntitySeller, id) {
+		return models.Seller{}, store.ErrNotFound
+	}
+	return seller, nil
+}
+
+// GetByName returns the seller whose name matches ignoring case and spacing
+func (s *SellerStore) GetByName(ctx context.Context, name string) (models.Seller, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	key := models.SellerNameKey(name)
+	for _, seller := range s.db.sellers {
+		if models.SellerNameKey(seller.Name) == key && !s.db.trashed(models.EntitySeller, seller.ID) {
+			return seller, nil
+		}
+	}
+	return models.Seller{}, store.ErrNotFound
+}
+
+// Create inserts a seller, assigning the next ID when none is set
+func (s *SellerStore) Create(ctx context.Context, seller *models.Seller) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if seller.ID == 0 {
+		seller.ID = nextID(s.db.sellers)
+	}
+	if _, ok := s.db.sellers[seller.ID]; ok {
+		return duplicateKey(seller.ID)
+	}
+	if err := s.checkUnique(seller.ID, *seller); err != nil {
+		return err
+	}
+	now := time.Now()
+	seller.CreatedAt, seller.UpdatedAt = now, now
+	s.db.sellers[seller.ID] = *seller
+	return nil
+}
+
+// Update overwrites a seller and renames the stock lots linked to it
+func (s *SellerStore) Update(ctx context.Context, id int64, seller models.Seller) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.sellers[id]
+	if !ok || s.db.trashed(models.EntitySeller, id) {
+		return store.ErrNotFound
+	}
+	if err := s.checkUnique(id, seller); err != nil {
+		return err
+	}
+	existing.Name = seller.Name
+	existing.ContactName = seller.ContactName
+	existing.Phone = seller.Phone
+	existing.Email = seller.Email
+	existing.GSTIN = seller.GSTIN
+	existing.Country = seller.Country
+	existing.UpdatedAt = time.Now()
+	s.db.sellers[id] = existing
+
+	for stockID, stock := range s.db.stocks {
+		if stock.SellerID != nil && *stock.SellerID == id {
+			stock.SellerName = seller.Name
+			s.db.stocks[stockID] = stock
+		}
+	}
+	return nil
+}
+
+// Delete moves a seller to the trash
+func (s *SellerStore) Delete(ctx context.Context, id int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntitySeller, s.db.sellers, id, func(seller models.Seller) string { return seller.Name })
+}
+
+// checkUnique mirrors the unique keys on name and GSTIN
+func (s *SellerStore) checkUnique(id int64, seller models.Seller) error {
+	key := models.SellerNameKey(seller.Name)
+	for otherID, other := range s.db.sellers {
+		if otherID == id {
+			continue
+		}
+		if models.SellerNameKey(other.Name) == key {
+			return duplicateUnique(key, "uq_sellers_name_key")
+		}
+		if seller.GSTIN != nil && other.GSTIN != nil && *seller.GSTIN == *other.GSTIN {
+			return duplicateUnique(*seller.GSTIN, "uq_sellers_gstin")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package memory
+
+import "context"
+
+// SequenceStore implements store.SequenceStore
+type SequenceStore struct {
+	db *database
+}
+
+// Next increments a sequence
+func (s *SequenceStore) Next(ctx context.Context, name string) (int64, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.sequences[name]++
+	return s.db.sequences[name], nil
+}
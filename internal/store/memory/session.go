@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// SessionStore implements store.SessionStore
+type SessionStore struct {
+	db *database
+}
+
+// Create inserts a session
+func (s *SessionStore) Create(ctx context.Context, session *models.Session) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	for _, existing := range s.db.sessions {
+		if existing.TokenHash == session.TokenHash {
+			return duplicateUnique(session.TokenHash, "uq_sessions_token_hash")
+		}
+	}
+	session.ID = nextID(s.db.sessions)
+	session.CreatedAt = time.Now()
+	session.RevokedAt = nil
+	s.db.sessions[session.ID] = *session
+	return nil
+}
+
+// GetByTokenHash returns the session a refresh token belongs to
+func (s *SessionStore) GetByTokenHash(ctx context.Context, tokenHash string) (models.Session, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	for _, session := range s.db.sessions {
+		if session.TokenHash == tokenHash {
+			return session, nil
+		}
+	}
+	return models.Session{}, store.ErrNotFound
+}
+
+// Revoke ends a session unless it has already been revoked
+func (s *SessionStore) Revoke(ctx context.Context, id int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	session, ok := s.db.sessions[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	if session.RevokedAt != nil {
+		return store.ErrConflict
+	}
+	now := time.Now()
+	session.RevokedAt = &now
+	s.db.sessions[id] = session
+	return nil
+}
+
+// RevokeUser ends every active session of a user
+func (s *SessionStore) RevokeUser(ctx context.Context, userID int64) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	now := time.Now()
+	for id, session := range s.db.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			s.db.sessions[id] = session
+		}
+	}
+	return nil
+}
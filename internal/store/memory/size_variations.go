@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strconv"
+)
+
+// SizeVariationStore implements store.SizeVariationStore
+type SizeVariationStore struct {
+	db *database
+}
+
+// List returns a page of size variations matching opts
+func (s *SizeVariationStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.SizeVariations], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntitySizeVariation, s.db.sizeVariations), store.SizeVariationList, opts)
+}
+
+// Get returns a single size variation
+func (s *SizeVariationStore) Get(ctx context.Context, id int) (models.SizeVariations, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	variation, ok := s.db.sizeVariations[id]
+	if !ok || s.db.trashed(models.EntitySizeVariation, id) {
+		return models.SizeVariations{}, store.ErrNotFound
+	}
+	return variation, nil
+}
+
+// Create inserts a size variation, assigning the next ID when none is set
+func (s *SizeVariationStore) Create(ctx context.Context, variation *models.SizeVariations) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if variation.SizeID == 0 {
+		variation.SizeID = nextID(s.db.sizeVariations)
+	}
+	if _, ok := s.db.sizeVariations[variation.SizeID]; ok {
+		return duplicateKey(variation.SizeID)
+	}
+	s.db.sizeVariations[variation.SizeID] = *variation
+	return nil
+}
+
+// Update overwrites an existing size variation
+func (s *SizeVariationStore) Update(ctx context.Context, id int, variation models.SizeVariations) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.sizeVariations[id]
+	if !ok || s.db.trashed(models.EntitySizeVariation, id) {
+		return store.ErrNotFound
+	}
+	existing.SizeValue = variation.SizeValue
+	s.db.sizeVariations[id] = existing
+	return nil
+}
+
+// Delete moves a size variation to the trash
+func (s *SizeVariationStore) Delete(ctx context.Context, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntitySizeVariation, s.db.sizeVariations, id, func(v models.SizeVariations) string { return strconv.Itoa(v.SizeValue) })
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// StockStore implements store.StockStore
+type StockStore struct {
+	db *database
+}
+
+// List returns a page of stock lots matching opts
+func (s *StockStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Stock], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityStock, s.db.stocks), store.StockList, opts)
+}
+
+// Get returns a single stock
+func (s *StockStore) Get(ctx context.Context, id string) (models.Stock, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	stock, ok := s.db.stocks[id]
+	if !ok || s.db.trashed(models.EntityStock, id) {
+		return models.Stock{}, store.ErrNotFound
+	}
+	return stock, nil
+}
+
+// Create inserts the stock, refusing an ID already taken by a live lot or
+// one in the trash
+func (s *StockStore) Create(ctx context.Context, stock *models.Stock) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if _, ok := s.db.stocks[stock.StockID]; ok {
+		return duplicateKey(stock.StockID)
+	}
+	now := time.Now()
+	stock.CreatedAt, stock.UpdatedAt = now, now
+	stock.Status = models.StatusReceived
+	s.db.stocks[stock.StockID] = *stock
+	return nil
+}
+
+// Update overwrites an existing stock
+func (s *StockStore) Update(ctx context.Context, id string, stock models.Stock) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.stocks[id]
+	if !ok || s.db.trashed(models.EntityStock, id) {
+		return store.ErrNotFound
+	}
+	existing.SellerID = stock.SellerID
+	existing.SellerName = stock.SellerName
+	existing.OriginCountry = stock.OriginCountry
+	existing.Weight = stock.Weight
+	existing.PromisedOutturn = stock.PromisedOutturn
+	existing.Date = stock.Date
+	existing.UpdatedAt = time.Now()
+	s.db.stocks[id] = existing
+	return nil
+}
+
+// Delete moves a stock to the trash
+func (s *StockStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityStock, s.db.stocks, id, func(stock models.Stock) string { return stock.SellerName })
+}
+
+// Transition moves a lot to a new status if it is still in from, and
+// records the change
+func (s *StockStore) Transition(ctx context.Context, id string, from, to models.StockStatus, note string) (models.StockTransition, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	stock, ok := s.db.stocks[id]
+	if !ok || s.db.trashed(models.EntityStock, id) {
+		return models.StockTransition{}, store.ErrNotFound
+	}
+	if stock.Status != from {
+		return models.StockTransition{}, store.ErrConflict
+	}
+
+	now := time.Now()
+	stock.Status = to
+	stock.UpdatedAt = now
+	s.db.stocks[id] = stock
+
+	transition := models.StockTransition{
+		ID:         nextID(s.db.stockTransitions),
+		StockID:    id,
+		FromStatus: from,
+		ToStatus:   to,
+		Note:       note,
+		CreatedAt:  now,
+	}
+	s.db.stockTransitions[transition.ID] = transition
+	return transition, nil
+}
+
+// ListTransitions returns a page of recorded status changes matching opts
+func (s *StockStore) ListTransitions(ctx context.Context, opts store.ListOptions) (store.Page[models.StockTransition], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(values(s.db.stockTransitions), store.StockTransitionList, opts)
+}
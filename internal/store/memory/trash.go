@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"time"
+)
+
+// trashKey identifies a deleted row. Trashed rows stay in their table, as
+// they do in MySQL, so their keys still count as taken
+type trashKey struct {
+	entity string
+	id     string
+}
+
+// trashed reports whether the row id of entity is in the trash
+func (db *database) trashed(entity string, id any) bool {
+	_, ok := db.trash[trashKey{entity, fmt.Sprint(id)}]
+	return ok
+}
+
+// live returns the rows of a table that aren't in the trash, ordered by
+// primary key like values
+func live[K cmp.Ordered, T any](db *database, entity string, rows map[K]T) []T {
+	records := []T{}
+	for _, key := range keys(rows) {
+		if !db.trashed(entity, key) {
+			records = append(records, rows[key])
+		}
+	}
+	return records
+}
+
+// moveToTrash marks the row id of a table as deleted by the actor in ctx
+func moveToTrash[K comparable, T any](ctx context.Context, db *database, entity string, rows map[K]T, id K, label func(T) string) error {
+	row, ok := rows[id]
+	if !ok || db.trashed(entity, id) {
+		return store.ErrNotFound
+	}
+	item := models.TrashItem{
+		Entity:    entity,
+		ID:        fmt.Sprint(id),
+		Label:     label(row),
+		DeletedAt: time.Now(),
+		DeletedBy: store.DeletedBy(ctx),
+	}
+	db.trash[trashKey{item.Entity, item.ID}] = item
+	return nil
+}
+
+// deleteRow removes the row whose key prints as id
+func deleteRow[K comparable, T any](rows map[K]T, id string) {
+	for key := range rows {
+		if fmt.Sprint(key) == id {
+			delete(rows, key)
+		}
+	}
+}
+
+// TrashStore implements store.TrashStore
+type TrashStore struct {
+	db *database
+}
+
+// List returns a page of deleted records matching opts
+func (s *TrashStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.TrashItem], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	items := make([]models.TrashItem, 0, len(s.db.trash))
+	for _, item := range s.db.trash {
+		items = append(items, item)
+	}
+	return page(items, store.TrashList, opts)
+}
+
+// Get returns a single deleted record
+func (s *TrashStore) Get(ctx context.Context, entity, id string) (models.TrashItem, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	item, ok := s.db.trash[trashKey{entity, id}]
+	if !ok {
+		return models.TrashItem{}, store.ErrNotFound
+	}
+	return item, nil
+}
+
+// Restore takes a record out of the trash
+func (s *TrashStore) Restore(ctx context.Context, entity, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	key := trashKey{entity, id}
+	if _, ok := s.db.trash[key]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.db.trash, key)
+	return nil
+}
+
+// Purge removes a deleted record from its table. Foreign keys aren't
+// mirrored, so it never reports a conflict
+func (s *TrashStore) Purge(ctx context.Context, entity, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	key := trashKey{entity, id}
+	if _, ok := s.db.trash[key]; !ok {
+		return store.ErrNotFound
+	}
+	delete(s.db.trash, key)
+
+	switch entity {
+	case models.EntityStock:
+		delete(s.db.stocks, id)
+		for transitionID, transition := range s.db.stockTransitions {
+			if transition.StockID == id {
+				delete(s.db.stockTransitions, transitionID)
+			}
+		}
+		for anomalyID, anomaly := range s.db.anomalies {
+			if anomaly.StockID == id {
+				delete(s.db.anomalies, anomalyID)
+			}
+		}
+	case models.EntitySeller:
+		deleteRow(s.db.sellers, id)
+	case models.EntityGradingSheet:
+		deleteRow(s.db.gradingSheets, id)
+	case models.EntityPiece:
+		deleteRow(s.db.pieces, id)
+	case models.EntitySizeVariation:
+		deleteRow(s.db.sizeVariations, id)
+	case models.EntityGraderMachineOutput:
+		deleteRow(s.db.graderMachineOutputs, id)
+	case models.EntityWeightType:
+		deleteRow(s.db.weightTypes, id)
+	case models.EntityWorkforce:
+		deleteRow(s.db.workforce, id)
+	case models.EntityGradingCategory:
+		deleteRow(s.db.gradingCategories, id)
+	case models.EntityHumidifier:
+		deleteRow(s.db.humidifiers, id)
+	case models.EntityPeelingMachine:
+		deleteRow(s.db.peelingMachines, id)
+	case models.EntityColorSort:
+		deleteRow(s.db.colorSorts, id)
+	case models.EntityMachineGrading:
+		deleteRow(s.db.machineGradings, id)
+	case models.EntityManualGradingInput:
+		deleteRow(s.db.manualGradingInputs, id)
+	case models.EntityManualGrading:
+		deleteRow(s.db.manualGradings, id)
+	}
+	return nil
+}
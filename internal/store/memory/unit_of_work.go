@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/store"
+	"maps"
+)
+
+// clone copies every table. Rows are stored by value, so copying the maps
+// is enough to keep the copy apart from later writes
+func (t *tables) clone() tables {
+	return tables{
+		stocks:               maps.Clone(t.stocks),
+		stockTransitions:     maps.Clone(t.stockTransitions),
+		humidifiers:          maps.Clone(t.humidifiers),
+		peelingMachines:      maps.Clone(t.peelingMachines),
+		colorSorts:           maps.Clone(t.colorSorts),
+		machineGradings:      maps.Clone(t.machineGradings),
+		manualGradings:       maps.Clone(t.manualGradings),
+		manualGradingInputs:  maps.Clone(t.manualGradingInputs),
+		gradingSheets:        maps.Clone(t.gradingSheets),
+		graderMachineOutputs: maps.Clone(t.graderMachineOutputs),
+		gradingCategories:    maps.Clone(t.gradingCategories),
+		pieces:               maps.Clone(t.pieces),
+		sizeVariations:       maps.Clone(t.sizeVariations),
+		weightTypes:          maps.Clone(t.weightTypes),
+		workforce:            maps.Clone(t.workforce),
+		users:                maps.Clone(t.users),
+		sessions:             maps.Clone(t.sessions),
+		sellers:              maps.Clone(t.sellers),
+		devices:              maps.Clone(t.devices),
+		auditLog:             maps.Clone(t.auditLog),
+		anomalies:            maps.Clone(t.anomalies),
+		trash:                maps.Clone(t.trash),
+		sequences:            maps.Clone(t.sequences),
+		idempotencyKeys:      maps.Clone(t.idempotencyKeys),
+	}
+}
+
+// UnitOfWork runs work against the live tables and puts back a copy taken
+// beforehand when it fails. The stores lock per call rather than for the
+// whole unit, and reads marked with store.ForUpdate take no lock, so a
+// rollback also undoes writes other callers made while the unit ran and two
+// units can interleave; that is fine for tests and local development
+type UnitOfWork struct {
+	db     *database
+	stores *store.Stores
+}
+
+// Do runs work, undoing everything it wrote if it returns an error or panics
+func (u *UnitOfWork) Do(ctx context.Context, work func(stores *store.Stores) error) (err error) {
+	u.db.mu.RLock()
+	snapshot := u.db.clone()
+	u.db.mu.RUnlock()
+
+	defer func() {
+		if p := recover(); p != nil {
+			u.restore(snapshot)
+			panic(p)
+		}
+		if err != nil {
+			u.restore(snapshot)
+		}
+	}()
+	return work(u.stores)
+}
+
+// restore puts the tables back as they were in snapshot
+func (u *UnitOfWork) restore(snapshot tables) {
+	u.db.mu.Lock()
+	defer u.db.mu.Unlock()
+	u.db.tables = snapshot
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"sort"
+	"time"
+)
+
+// UserStore implements store.UserStore
+type UserStore struct {
+	db *database
+}
+
+// List returns every user ordered by username
+func (s *UserStore) List(ctx context.Context) ([]models.User, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	users := values(s.db.users)
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+// Get returns a single user
+func (s *UserStore) Get(ctx context.Context, id int64) (models.User, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	user, ok := s.db.users[id]
+	if !ok {
+		return models.User{}, store.ErrNotFound
+	}
+	return user, nil
+}
+
+// GetByUsername returns the user with the given username
+func (s *UserStore) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	for _, user := range s.db.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return models.User{}, store.ErrNotFound
+}
+
+// Create inserts a user, assigning the next ID when none is set
+func (s *UserStore) Create(ctx context.Context, user *models.User) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if user.ID == 0 {
+		user.ID = nextID(s.db.users)
+	}
+	if _, ok := s.db.users[user.ID]; ok {
+		return duplicateKey(user.ID)
+	}
+	for _, existing := range s.db.users {
+		if existing.Username == user.Username {
+			return duplicateUnique(user.Username, "uq_users_username")
+		}
+	}
+	now := time.Now()
+	user.CreatedAt, user.UpdatedAt = now, now
+	s.db.users[user.ID] = *user
+	return nil
+}
+
+// Update overwrites an existing user
+func (s *UserStore) Update(ctx context.Context, id int64, user models.User) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.users[id]
+	if !ok {
+		return store.ErrNotFound
+	}
+	for otherID, other := range s.db.users {
+		if otherID != id && other.Username == user.Username {
+			return duplicateUnique(user.Username, "uq_users_username")
+		}
+	}
+	existing.Username = user.Username
+	existing.PasswordHash = user.PasswordHash
+	existing.Role = user.Role
+	existing.UpdatedAt = time.Now()
+	s.db.users[id] = existing
+	return nil
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"sort"
+	"strconv"
+)
+
+// WeightTypeStore implements store.WeightTypeStore
+type WeightTypeStore struct {
+	db *database
+}
+
+// List returns a page of weight types matching opts
+func (s *WeightTypeStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.WeightTypes], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityWeightType, s.db.weightTypes), store.WeightTypeList, opts)
+}
+
+// Get returns a single weight type
+func (s *WeightTypeStore) Get(ctx context.Context, id string) (models.WeightTypes, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	weightType, ok := s.db.weightTypes[id]
+	if !ok || s.db.trashed(models.EntityWeightType, id) {
+		return models.WeightTypes{}, store.ErrNotFound
+	}
+	return weightType, nil
+}
+
+// Create inserts a weight type, assigning the next ID when none is set
+func (s *WeightTypeStore) Create(ctx context.Context, weightType *models.WeightTypes) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if weightType.ID == "" {
+		weightType.ID = nextNumericID(s.db.weightTypes)
+	}
+	if _, ok := s.db.weightTypes[weightType.ID]; ok {
+		return duplicateKey(weightType.ID)
+	}
+	s.db.weightTypes[weightType.ID] = *weightType
+	return nil
+}
+
+// Update overwrites an existing weight type
+func (s *WeightTypeStore) Update(ctx context.Context, id string, weightType models.WeightTypes) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.weightTypes[id]
+	if !ok || s.db.trashed(models.EntityWeightType, id) {
+		return store.ErrNotFound
+	}
+	existing.Type = weightType.Type
+	s.db.weightTypes[id] = existing
+	return nil
+}
+
+// Delete moves a weight type to the trash
+func (s *WeightTypeStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityWeightType, s.db.weightTypes, id, func(w models.WeightTypes) string { return w.Type })
+}
+
+// Usage returns every weight type with the number of peeling machine and
+// color sort records using it, most used first
+func (s *WeightTypeStore) Usage(ctx context.Context) ([]models.WeightTypeUsage, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	counts := map[string]int{}
+	for _, machine := range live(s.db, models.EntityPeelingMachine, s.db.peelingMachines) {
+		counts[strconv.Itoa(machine.WeightTypeID)]++
+	}
+	for _, colorSort := range live(s.db, models.EntityColorSort, s.db.colorSorts) {
+		counts[strconv.Itoa(colorSort.WeightTypeID)]++
+	}
+
+	usages := []models.WeightTypeUsage{}
+	for _, weightType := range live(s.db, models.EntityWeightType, s.db.weightTypes) {
+		usages = append(usages, models.WeightTypeUsage{
+			WeightTypes: weightType,
+			UsageCount:  counts[weightType.ID],
+		})
+	}
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].UsageCount > usages[j].UsageCount
+	})
+	return usages, nil
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// WorkforceStore implements store.WorkforceStore
+type WorkforceStore struct {
+	db *database
+}
+
+// List returns a page of workers matching opts
+func (s *WorkforceStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Workforce], error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	return page(live(s.db, models.EntityWorkforce, s.db.workforce), store.WorkforceList, opts)
+}
+
+// Get returns a single worker
+func (s *WorkforceStore) Get(ctx context.Context, id string) (models.Workforce, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+	workforce, ok := s.db.workforce[id]
+	if !ok || s.db.trashed(models.EntityWorkforce, id) {
+		return models.Workforce{}, store.ErrNotFound
+	}
+	return workforce, nil
+}
+
+// Create inserts a worker, assigning the next ID when none is set
+func (s *WorkforceStore) Create(ctx context.Context, workforce *models.Workforce) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	if workforce.ID == "" {
+		workforce.ID = nextNumericID(s.db.workforce)
+	}
+	if _, ok := s.db.workforce[workforce.ID]; ok {
+		return duplicateKey(workforce.ID)
+	}
+	s.db.workforce[workforce.ID] = *workforce
+	return nil
+}
+
+// Update overwrites an existing worker
+func (s *WorkforceStore) Update(ctx context.Context, id string, workforce models.Workforce) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	existing, ok := s.db.workforce[id]
+	if !ok || s.db.trashed(models.EntityWorkforce, id) {
+		return store.ErrNotFound
+	}
+	existing.Name = workforce.Name
+	existing.Aadhaar = workforce.Aadhaar
+	existing.Addresss = workforce.Addresss
+	s.db.workforce[id] = existing
+	return nil
+}
+
+// Delete moves a worker to the trash
+func (s *WorkforceStore) Delete(ctx context.Context, id string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	return moveToTrash(ctx, s.db, models.EntityWorkforce, s.db.workforce, id, func(w models.Workforce) string { return w.Name })
+}
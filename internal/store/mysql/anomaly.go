@@ -0,0 +1,70 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const anomalyColumns = `id, stock_id, stage, record_id, weight, upstream_stage,
+	upstream_weight, allowed_weight, created_at`
+
+// AnomalyStore implements store.AnomalyStore
+type AnomalyStore struct {
+	db conn
+}
+
+func scanAnomaly(row scanner) (models.Anomaly, error) {
+	var anomaly models.Anomaly
+	err := row.Scan(
+		&anomaly.ID,
+		&anomaly.StockID,
+		&anomaly.Stage,
+		&anomaly.RecordID,
+		&anomaly.Weight,
+		&anomaly.UpstreamStage,
+		&anomaly.UpstreamWeight,
+		&anomaly.AllowedWeight,
+		&anomaly.CreatedAt,
+	)
+	return anomaly, err
+}
+
+// Record appends an anomaly
+func (s *AnomalyStore) Record(ctx context.Context, anomaly *models.Anomaly) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO weight_anomalies (
+			stock_id, stage, record_id, weight, upstream_stage,
+			upstream_weight, allowed_weight
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		anomaly.StockID,
+		anomaly.Stage,
+		anomaly.RecordID,
+		anomaly.Weight,
+		anomaly.UpstreamStage,
+		anomaly.UpstreamWeight,
+		anomaly.AllowedWeight,
+	)
+	if err != nil {
+		return err
+	}
+	if anomaly.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	// Fetch the created record to get its timestamp
+	created, err := queryOne(ctx, s.db, scanAnomaly, `
+		SELECT `+anomalyColumns+`
+		FROM weight_anomalies WHERE id = ?`, anomaly.ID)
+	if err != nil {
+		return err
+	}
+	*anomaly = created
+	return nil
+}
+
+// List returns a page of anomalies matching opts
+func (s *AnomalyStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Anomaly], error) {
+	return listPage(ctx, s.db, scanAnomaly, store.AnomalyList, "weight_anomalies", anomalyColumns, opts)
+}
@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const auditColumns = `id, actor_type, actor_id, actor_name, entity, entity_id, action,
+	before_json, after_json, created_at`
+
+// AuditStore implements store.AuditStore
+type AuditStore struct {
+	db conn
+}
+
+func scanAuditEntry(row scanner) (models.AuditEntry, error) {
+	var entry models.AuditEntry
+	var before, after []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.Actor.Type,
+		&entry.Actor.ID,
+		&entry.Actor.Name,
+		&entry.Entity,
+		&entry.EntityID,
+		&entry.Action,
+		&before,
+		&after,
+		&entry.CreatedAt,
+	)
+	entry.Before, entry.After = before, after
+	return entry, err
+}
+
+// nullJSON returns the value to bind for a JSON column, NULL when empty
+func nullJSON(raw json.RawMessage) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
+
+// Record appends an audit entry
+func (s *AuditStore) Record(ctx context.Context, entry *models.AuditEntry) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO audit_log (
+			actor_type, actor_id, actor_name, entity, entity_id, action,
+			before_json, after_json
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Actor.Type,
+		entry.Actor.ID,
+		entry.Actor.Name,
+		entry.Entity,
+		entry.EntityID,
+		entry.Action,
+		nullJSON(entry.Before),
+		nullJSON(entry.After),
+	)
+	if err != nil {
+		return err
+	}
+	if entry.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	// Fetch the created record to get its timestamp
+	created, err := queryOne(ctx, s.db, scanAuditEntry, `
+		SELECT `+auditColumns+`
+		FROM audit_log WHERE id = ?`, entry.ID)
+	if err != nil {
+		return err
+	}
+	*entry = created
+	return nil
+}
+
+// List returns a page of audit entries matching opts
+func (s *AuditStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.AuditEntry], error) {
+	return listPage(ctx, s.db, scanAuditEntry, store.AuditList, "audit_log", auditColumns, opts)
+}
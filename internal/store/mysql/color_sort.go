@@ -0,0 +1,159 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const colorSortColumns = `id, peel_id, stock_id, weight_type_id, accepted_weight, sort_counter, device_id, entry_type, corrects_id, reason, created_at, updated_at`
+
+// ColorSortStore implements store.ColorSortStore
+type ColorSortStore struct {
+	db conn
+}
+
+func scanColorSort(row scanner) (models.ColorSort, error) {
+	var colorSort models.ColorSort
+	err := row.Scan(
+		&colorSort.ID,
+		&colorSort.PeelID,
+		&colorSort.StockID,
+		&colorSort.WeightTypeID,
+		&colorSort.AcceptedWeight,
+		&colorSort.SortCounter,
+		&colorSort.DeviceID,
+		&colorSort.EntryType,
+		&colorSort.CorrectsID,
+		&colorSort.Reason,
+		&colorSort.CreatedAt,
+		&colorSort.UpdatedAt,
+	)
+	return colorSort, err
+}
+
+// List returns a page of color sort records matching opts
+func (s *ColorSortStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.ColorSort], error) {
+	return listPage(ctx, s.db, scanColorSort, store.ColorSortList, "color_sort", colorSortColumns, opts)
+}
+
+// Get returns a single color sort record
+func (s *ColorSortStore) Get(ctx context.Context, id string) (models.ColorSort, error) {
+	return queryOne(ctx, s.db, scanColorSort, `
+		SELECT `+colorSortColumns+`
+		FROM color_sort WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts an original color sort record
+func (s *ColorSortStore) Create(ctx context.Context, colorSort *models.ColorSort) error {
+	colorSort.EntryType, colorSort.CorrectsID, colorSort.Reason = models.EntryOriginal, nil, ""
+	return inTx(ctx, s.db, func(tx conn) error {
+		if err := insertColorSort(ctx, tx, colorSort); err != nil {
+			return err
+		}
+
+		// Fetch the created record in the same transaction to get timestamps
+		created, err := (&ColorSortStore{db: tx}).Get(ctx, colorSort.ID)
+		if err != nil {
+			return err
+		}
+		*colorSort = created
+		return nil
+	})
+}
+
+// CreateMany inserts original color sort records in one transaction
+func (s *ColorSortStore) CreateMany(ctx context.Context, colorSorts []*models.ColorSort, atomic bool) ([]error, error) {
+	for _, colorSort := range colorSorts {
+		colorSort.EntryType, colorSort.CorrectsID, colorSort.Reason = models.EntryOriginal, nil, ""
+	}
+	errs, err := insertMany(ctx, s.db, colorSorts, atomic, insertColorSort)
+	if err != nil {
+		return errs, err
+	}
+	return errs, fetchCreated(colorSorts, errs, atomic, func(colorSort *models.ColorSort) (err error) {
+		*colorSort, err = s.Get(ctx, colorSort.ID)
+		return err
+	})
+}
+
+// Correct stores the reversal of a color sort record and its replacement,
+// if any, in one transaction
+func (s *ColorSortStore) Correct(ctx context.Context, reversal, replacement *models.ColorSort) error {
+	err := correct(ctx, s.db, "color_sort", *reversal.CorrectsID, func(tx conn) error {
+		if err := insertColorSort(ctx, tx, reversal); err != nil {
+			return err
+		}
+		if replacement == nil {
+			return nil
+		}
+		return insertColorSort(ctx, tx, replacement)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fetch the stored entries to get timestamps
+	if *reversal, err = s.Get(ctx, reversal.ID); err != nil {
+		return err
+	}
+	if replacement != nil {
+		*replacement, err = s.Get(ctx, replacement.ID)
+	}
+	return err
+}
+
+// Delete moves a color sort record to the trash
+func (s *ColorSortStore) Delete(ctx context.Context, id string) error {
+	return moveToTrash(ctx, s.db, "color_sort", id)
+}
+
+// insertColorSort writes one row of color_sort with db
+func insertColorSort(ctx context.Context, db execer, colorSort *models.ColorSort) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO color_sort (
+			id, peel_id, stock_id, weight_type_id, accepted_weight,
+			sort_counter, device_id, entry_type, corrects_id, reason,
+			created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		colorSort.ID,
+		colorSort.PeelID,
+		colorSort.StockID,
+		colorSort.WeightTypeID,
+		colorSort.AcceptedWeight,
+		colorSort.SortCounter,
+		colorSort.DeviceID,
+		colorSort.EntryType,
+		colorSort.CorrectsID,
+		colorSort.Reason,
+	)
+	return err
+}
+
+// AcceptedWeightSummary totals the accepted weight for a stock and sort counter
+func (s *ColorSortStore) AcceptedWeightSummary(ctx context.Context, stockID string, counter int) (models.ColorSortSummary, error) {
+	summary := models.ColorSortSummary{StockID: stockID, SortCounter: counter}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			stock_id,
+			sort_counter,
+			COALESCE(SUM(accepted_weight), 0) as total_accepted_weight,
+			`+entryCount+` as record_count
+		FROM color_sort
+		WHERE stock_id = ? AND sort_counter = ? AND deleted_at IS NULL
+		GROUP BY stock_id, sort_counter`,
+		stockID, counter,
+	).Scan(
+		&summary.StockID,
+		&summary.SortCounter,
+		&summary.TotalAccepted,
+		&summary.RecordCount,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return summary, nil
+	}
+	return summary, err
+}
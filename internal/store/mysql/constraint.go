@@ -0,0 +1,76 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"healing_photons/internal/store"
+	"regexp"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers for writes refused by a constraint, besides
+// errDuplicateEntry
+const (
+	errDataTooLong     = 1406
+	errNoReferencedRow = 1452
+)
+
+var (
+	duplicateEntryMessage = regexp.MustCompile(`Duplicate entry '(.*)' for key '(?:[^'.]*\.)?([^']*)'`)
+	foreignKeyMessage     = regexp.MustCompile("FOREIGN KEY \\(`([^`]*)`\\) REFERENCES `([^`]*)`")
+	dataTooLongMessage    = regexp.MustCompile(`column '([^']*)'`)
+)
+
+// constraintError returns err as a store.ConstraintError when MySQL refused
+// a write for breaking a constraint, and unchanged otherwise
+func constraintError(err error) error {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+	switch mysqlErr.Number {
+	case errDuplicateEntry:
+		violation := &store.ConstraintError{Constraint: store.ConstraintUnique, Err: err}
+		if match := duplicateEntryMessage.FindStringSubmatch(mysqlErr.Message); match != nil {
+			violation.Value, violation.Key = match[1], match[2]
+		}
+		return violation
+	case errNoReferencedRow:
+		violation := &store.ConstraintError{Constraint: store.ConstraintForeignKey, Err: err}
+		if match := foreignKeyMessage.FindStringSubmatch(mysqlErr.Message); match != nil {
+			violation.Column, violation.Parent = match[1], match[2]
+		}
+		return violation
+	case errDataTooLong:
+		violation := &store.ConstraintError{Constraint: store.ConstraintLength, Err: err}
+		if match := dataTooLongMessage.FindStringSubmatch(mysqlErr.Message); match != nil {
+			violation.Column = match[1]
+		}
+		return violation
+	}
+	return err
+}
+
+// database is the conn stores run on outside a unit of work. Writes it
+// refuses for breaking a constraint come back as store.ConstraintError
+type database struct {
+	*sql.DB
+}
+
+func (d database) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := d.DB.ExecContext(ctx, query, args...)
+	return result, constraintError(err)
+}
+
+// transaction is the conn stores run on inside a unit of work, reporting
+// constraint violations the same way database does
+type transaction struct {
+	*sql.Tx
+}
+
+func (t transaction) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := t.Tx.ExecContext(ctx, query, args...)
+	return result, constraintError(err)
+}
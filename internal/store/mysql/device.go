@@ -0,0 +1,174 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strings"
+	"time"
+)
+
+const deviceColumns = `id, name, stages, key_prefix, key_hash, previous_key_hash,
+	previous_key_expires_at, key_rotated_at, revoked_at, created_at, updated_at`
+
+// DeviceStore implements store.DeviceStore
+type DeviceStore struct {
+	db conn
+}
+
+func scanDevice(row scanner) (models.Device, error) {
+	var device models.Device
+	var stages string
+	err := row.Scan(
+		&device.ID,
+		&device.Name,
+		&stages,
+		&device.KeyPrefix,
+		&device.KeyHash,
+		&device.PreviousKeyHash,
+		&device.PreviousKeyExpiresAt,
+		&device.KeyRotatedAt,
+		&device.RevokedAt,
+		&device.CreatedAt,
+		&device.UpdatedAt,
+	)
+	device.Stages = splitStages(stages)
+	return device, err
+}
+
+// splitStages reads a SET column, which MySQL returns comma separated
+func splitStages(set string) []models.StockStatus {
+	stages := []models.StockStatus{}
+	for _, stage := range strings.Split(set, ",") {
+		if stage != "" {
+			stages = append(stages, models.StockStatus(stage))
+		}
+	}
+	return stages
+}
+
+func joinStages(stages []models.StockStatus) string {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = string(stage)
+	}
+	return strings.Join(names, ",")
+}
+
+// List returns a page of devices matching opts
+func (s *DeviceStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Device], error) {
+	return listPage(ctx, s.db, scanDevice, store.DeviceList, "devices", deviceColumns, opts)
+}
+
+// Get returns a single device
+func (s *DeviceStore) Get(ctx context.Context, id int64) (models.Device, error) {
+	return queryOne(ctx, s.db, scanDevice, `
+		SELECT `+deviceColumns+`
+		FROM devices WHERE id = ?`, id)
+}
+
+// GetByKeyHash returns the device a current or rotated out key belongs to
+func (s *DeviceStore) GetByKeyHash(ctx context.Context, keyHash string) (models.Device, error) {
+	return queryOne(ctx, s.db, scanDevice, `
+		SELECT `+deviceColumns+`
+		FROM devices
+		WHERE key_hash = ?
+			OR (previous_key_hash = ? AND previous_key_expires_at > NOW())
+		LIMIT 1`, keyHash, keyHash)
+}
+
+// Create inserts a device
+func (s *DeviceStore) Create(ctx context.Context, device *models.Device) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO devices (
+			name, stages, key_prefix, key_hash, key_rotated_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, NOW(), NOW(), NOW())`,
+		device.Name,
+		joinStages(device.Stages),
+		device.KeyPrefix,
+		device.KeyHash,
+	)
+	if err != nil {
+		return err
+	}
+	if device.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	// Fetch the created record to get timestamps
+	created, err := s.Get(ctx, device.ID)
+	if err != nil {
+		return err
+	}
+	*device = created
+	return nil
+}
+
+// Update changes a device's name and stages
+func (s *DeviceStore) Update(ctx context.Context, id int64, device models.Device) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE devices
+		SET name = ?,
+			stages = ?,
+			updated_at = NOW()
+		WHERE id = ?`,
+		device.Name,
+		joinStages(device.Stages),
+		id,
+	)
+}
+
+// RotateKey replaces the key of a device that hasn't been revoked
+func (s *DeviceStore) RotateKey(ctx context.Context, id int64, keyHash, keyPrefix string, grace time.Duration) error {
+	var expires *time.Time
+	if grace > 0 {
+		at := time.Now().Add(grace)
+		expires = &at
+	}
+	err := execAffecting(ctx, s.db, `
+		UPDATE devices
+		SET previous_key_hash = IF(? IS NULL, NULL, key_hash),
+			previous_key_expires_at = ?,
+			key_hash = ?,
+			key_prefix = ?,
+			key_rotated_at = NOW(),
+			updated_at = NOW()
+		WHERE id = ? AND revoked_at IS NULL`,
+		expires,
+		expires,
+		keyHash,
+		keyPrefix,
+		id,
+	)
+	return s.conflictIfExists(ctx, id, err)
+}
+
+// Revoke disables a device unless it has already been revoked
+func (s *DeviceStore) Revoke(ctx context.Context, id int64) error {
+	err := execAffecting(ctx, s.db, `
+		UPDATE devices
+		SET revoked_at = NOW(),
+			previous_key_hash = NULL,
+			previous_key_expires_at = NULL,
+			updated_at = NOW()
+		WHERE id = ? AND revoked_at IS NULL`, id)
+	return s.conflictIfExists(ctx, id, err)
+}
+
+// conflictIfExists turns the store.ErrNotFound of an update guarded on
+// revoked_at into store.ErrConflict when the device does exist
+func (s *DeviceStore) conflictIfExists(ctx context.Context, id int64, err error) error {
+	if !errors.Is(err, store.ErrNotFound) {
+		return err
+	}
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM devices WHERE id = ?)", id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return store.ErrConflict
+	}
+	return err
+}
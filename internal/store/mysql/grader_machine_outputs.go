@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// GraderMachineOutputStore implements store.GraderMachineOutputStore
+type GraderMachineOutputStore struct {
+	db conn
+}
+
+func scanGraderMachineOutput(row scanner) (models.GraderMachineOutputs, error) {
+	var output models.GraderMachineOutputs
+	err := row.Scan(
+		&output.ID,
+		&output.Type,
+	)
+	return output, err
+}
+
+// List returns a page of grader machine outputs matching opts
+func (s *GraderMachineOutputStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.GraderMachineOutputs], error) {
+	return listPage(ctx, s.db, scanGraderMachineOutput, store.GraderMachineOutputList, "grader_machine_outputs", `id, type`, opts)
+}
+
+// Get returns a single grader machine output
+func (s *GraderMachineOutputStore) Get(ctx context.Context, id string) (models.GraderMachineOutputs, error) {
+	return queryOne(ctx, s.db, scanGraderMachineOutput, `
+		SELECT id, type
+		FROM grader_machine_outputs WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a grader machine output
+func (s *GraderMachineOutputStore) Create(ctx context.Context, output *models.GraderMachineOutputs) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO grader_machine_outputs (
+			id, type
+		)
+		VALUES (?, ?)`,
+		output.ID,
+		output.Type,
+	)
+	return err
+}
+
+// Update overwrites an existing grader machine output
+func (s *GraderMachineOutputStore) Update(ctx context.Context, id string, output models.GraderMachineOutputs) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE grader_machine_outputs
+		SET type = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		output.Type,
+		id,
+	)
+}
+
+// Delete moves a grader machine output to the trash
+func (s *GraderMachineOutputStore) Delete(ctx context.Context, id string) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE grader_machine_outputs
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
@@ -0,0 +1,89 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// GradingCategoryStore implements store.GradingCategoryStore
+type GradingCategoryStore struct {
+	db conn
+}
+
+func scanGradingCategory(row scanner) (models.GradingCategory, error) {
+	var category models.GradingCategory
+	err := row.Scan(
+		&category.CategoryID,
+		&category.CategoryCode,
+		&category.Description,
+	)
+	return category, err
+}
+
+// List returns a page of grading categories matching opts
+func (s *GradingCategoryStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.GradingCategory], error) {
+	return listPage(ctx, s.db, scanGradingCategory, store.GradingCategoryList, "grading_categories", `category_id, category_code, description`, opts)
+}
+
+// Get returns a single grading category
+func (s *GradingCategoryStore) Get(ctx context.Context, id int64) (models.GradingCategory, error) {
+	return queryOne(ctx, s.db, scanGradingCategory, `
+		SELECT category_id, category_code, description
+		FROM grading_categories
+		WHERE category_id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a grading category
+func (s *GradingCategoryStore) Create(ctx context.Context, category *models.GradingCategory) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO grading_categories (
+			category_id, category_code, description
+		)
+		VALUES (?, ?, ?)`,
+		category.CategoryID,
+		category.CategoryCode,
+		category.Description,
+	)
+	if err != nil {
+		return err
+	}
+	if category.CategoryID == 0 {
+		if category.CategoryID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the created record to pick up column defaults
+	created, err := s.Get(ctx, category.CategoryID)
+	if err != nil {
+		return err
+	}
+	*category = created
+	return nil
+}
+
+// Update overwrites an existing grading category
+func (s *GradingCategoryStore) Update(ctx context.Context, id int64, category models.GradingCategory) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE grading_categories
+		SET category_code = ?,
+			description = ?
+		WHERE category_id = ? AND deleted_at IS NULL`,
+		category.CategoryCode,
+		category.Description,
+		id,
+	)
+}
+
+// Delete moves a grading category to the trash
+func (s *GradingCategoryStore) Delete(ctx context.Context, id int64) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE grading_categories
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE category_id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
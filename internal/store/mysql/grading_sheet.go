@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const gradingSheetColumns = `id, stock_id, worker_id, size_variations_id, whole, a, sw, ssw, tw, jb, kw, created_at, updated_at`
+
+// GradingSheetStore implements store.GradingSheetStore
+type GradingSheetStore struct {
+	db conn
+}
+
+func scanGradingSheet(row scanner) (models.GradingSheet, error) {
+	var sheet models.GradingSheet
+	err := row.Scan(
+		&sheet.ID,
+		&sheet.StockID,
+		&sheet.WorkerID,
+		&sheet.SizeVariationsID,
+		&sheet.Grades.Whole,
+		&sheet.Grades.A,
+		&sheet.Grades.SW,
+		&sheet.Grades.SSW,
+		&sheet.Grades.TW,
+		&sheet.Grades.JB,
+		&sheet.Grades.KW,
+		&sheet.CreatedAt,
+		&sheet.UpdatedAt,
+	)
+	return sheet, err
+}
+
+// List returns a page of grading sheets matching opts
+func (s *GradingSheetStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.GradingSheet], error) {
+	return listPage(ctx, s.db, scanGradingSheet, store.GradingSheetList, "grading_sheets", gradingSheetColumns, opts)
+}
+
+// Get returns a single grading sheet
+func (s *GradingSheetStore) Get(ctx context.Context, id int64) (models.GradingSheet, error) {
+	return queryOne(ctx, s.db, scanGradingSheet, `
+		SELECT `+gradingSheetColumns+`
+		FROM grading_sheets WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a grading sheet
+func (s *GradingSheetStore) Create(ctx context.Context, sheet *models.GradingSheet) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO grading_sheets (
+			id, stock_id, worker_id, size_variations_id, whole, a, sw, ssw, tw, jb, kw, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		sheet.ID,
+		sheet.StockID,
+		sheet.WorkerID,
+		sheet.SizeVariationsID,
+		sheet.Grades.Whole,
+		sheet.Grades.A,
+		sheet.Grades.SW,
+		sheet.Grades.SSW,
+		sheet.Grades.TW,
+		sheet.Grades.JB,
+		sheet.Grades.KW,
+	)
+	if err != nil {
+		return err
+	}
+	if sheet.ID == 0 {
+		if sheet.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the created record to get timestamps
+	created, err := s.Get(ctx, sheet.ID)
+	if err != nil {
+		return err
+	}
+	*sheet = created
+	return nil
+}
+
+// Update overwrites an existing grading sheet
+func (s *GradingSheetStore) Update(ctx context.Context, id int64, sheet models.GradingSheet) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE grading_sheets
+		SET stock_id = ?,
+			worker_id = ?,
+			size_variations_id = ?,
+			whole = ?,
+			a = ?,
+			sw = ?,
+			ssw = ?,
+			tw = ?,
+			jb = ?,
+			kw = ?,
+			updated_at = NOW()
+		WHERE id = ? AND deleted_at IS NULL`,
+		sheet.StockID,
+		sheet.WorkerID,
+		sheet.SizeVariationsID,
+		sheet.Grades.Whole,
+		sheet.Grades.A,
+		sheet.Grades.SW,
+		sheet.Grades.SSW,
+		sheet.Grades.TW,
+		sheet.Grades.JB,
+		sheet.Grades.KW,
+		id,
+	)
+}
+
+// Delete moves a grading sheet to the trash
+func (s *GradingSheetStore) Delete(ctx context.Context, id int64) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE grading_sheets
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
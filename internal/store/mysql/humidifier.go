@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const humidifierColumns = `id, stock_id, weight, device_id, entry_type, corrects_id, reason, created_at, updated_at`
+
+// HumidifierStore implements store.HumidifierStore
+type HumidifierStore struct {
+	db conn
+}
+
+func scanHumidifier(row scanner) (models.Humidifier, error) {
+	var humidifier models.Humidifier
+	err := row.Scan(
+		&humidifier.ID,
+		&humidifier.StockID,
+		&humidifier.Weight,
+		&humidifier.DeviceID,
+		&humidifier.EntryType,
+		&humidifier.CorrectsID,
+		&humidifier.Reason,
+		&humidifier.CreatedAt,
+		&humidifier.UpdatedAt,
+	)
+	return humidifier, err
+}
+
+// List returns a page of humidifier records matching opts
+func (s *HumidifierStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Humidifier], error) {
+	return listPage(ctx, s.db, scanHumidifier, store.HumidifierList, "humidifier", humidifierColumns, opts)
+}
+
+// Get returns a single humidifier record
+func (s *HumidifierStore) Get(ctx context.Context, id string) (models.Humidifier, error) {
+	return queryOne(ctx, s.db, scanHumidifier, `
+		SELECT `+humidifierColumns+`
+		FROM humidifier WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts an original humidifier record
+func (s *HumidifierStore) Create(ctx context.Context, humidifier *models.Humidifier) error {
+	humidifier.EntryType, humidifier.CorrectsID, humidifier.Reason = models.EntryOriginal, nil, ""
+	return inTx(ctx, s.db, func(tx conn) error {
+		if err := insertHumidifier(ctx, tx, humidifier); err != nil {
+			return err
+		}
+
+		// Fetch the created record in the same transaction to get timestamps
+		created, err := (&HumidifierStore{db: tx}).Get(ctx, humidifier.ID)
+		if err != nil {
+			return err
+		}
+		*humidifier = created
+		return nil
+	})
+}
+
+// CreateMany inserts original humidifier records in one transaction
+func (s *HumidifierStore) CreateMany(ctx context.Context, humidifiers []*models.Humidifier, atomic bool) ([]error, error) {
+	for _, humidifier := range humidifiers {
+		humidifier.EntryType, humidifier.CorrectsID, humidifier.Reason = models.EntryOriginal, nil, ""
+	}
+	errs, err := insertMany(ctx, s.db, humidifiers, atomic, insertHumidifier)
+	if err != nil {
+		return errs, err
+	}
+	return errs, fetchCreated(humidifiers, errs, atomic, func(humidifier *models.Humidifier) (err error) {
+		*humidifier, err = s.Get(ctx, humidifier.ID)
+		return err
+	})
+}
+
+// Correct stores the reversal of a humidifier record and its replacement,
+// if any, in one transaction
+func (s *HumidifierStore) Correct(ctx context.Context, reversal, replacement *models.Humidifier) error {
+	err := correct(ctx, s.db, "humidifier", *reversal.CorrectsID, func(tx conn) error {
+		if err := insertHumidifier(ctx, tx, reversal); err != nil {
+			return err
+		}
+		if replacement == nil {
+			return nil
+		}
+		return insertHumidifier(ctx, tx, replacement)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fetch the stored entries to get timestamps
+	if *reversal, err = s.Get(ctx, reversal.ID); err != nil {
+		return err
+	}
+	if replacement != nil {
+		*replacement, err = s.Get(ctx, replacement.ID)
+	}
+	return err
+}
+
+// Delete moves a humidifier record to the trash
+func (s *HumidifierStore) Delete(ctx context.Context, id string) error {
+	return moveToTrash(ctx, s.db, "humidifier", id)
+}
+
+// insertHumidifier writes one row of humidifier with db
+func insertHumidifier(ctx context.Context, db execer, humidifier *models.Humidifier) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO humidifier (
+			id, stock_id, weight, device_id, entry_type, corrects_id,
+			reason, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		humidifier.ID,
+		humidifier.StockID,
+		humidifier.Weight,
+		humidifier.DeviceID,
+		humidifier.EntryType,
+		humidifier.CorrectsID,
+		humidifier.Reason,
+	)
+	return err
+}
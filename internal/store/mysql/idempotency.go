@@ -0,0 +1,94 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+const idempotencyKeyColumns = `caller, idempotency_key, request_hash, status_code, response, created_at`
+
+// errDuplicateEntry is the MySQL error number for an insert that repeats a
+// unique key
+const errDuplicateEntry = 1062
+
+// IdempotencyStore implements store.IdempotencyStore
+type IdempotencyStore struct {
+	db conn
+}
+
+func scanIdempotencyKey(row scanner) (models.IdempotencyKey, error) {
+	var key models.IdempotencyKey
+	err := row.Scan(
+		&key.Caller,
+		&key.Key,
+		&key.RequestHash,
+		&key.StatusCode,
+		&key.Response,
+		&key.CreatedAt,
+	)
+	return key, err
+}
+
+// Claim inserts a key as in progress. The primary key makes the insert
+// fail for every request but one when the same key arrives concurrently
+func (s *IdempotencyStore) Claim(ctx context.Context, key models.IdempotencyKey, expired, abandoned time.Time) (models.IdempotencyKey, bool, error) {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE caller = ? AND idempotency_key = ?
+			AND (created_at <= ? OR (status_code = 0 AND created_at <= ?))`,
+		key.Caller, key.Key, expired, abandoned)
+	if err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys (caller, idempotency_key, request_hash, created_at)
+		VALUES (?, ?, ?, NOW())`,
+		key.Caller, key.Key, key.RequestHash)
+	var mysqlErr *mysqldriver.MySQLError
+	claimed := !errors.As(err, &mysqlErr) || mysqlErr.Number != errDuplicateEntry
+	if claimed && err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+
+	// Read the row back: a held key is returned as stored, and a new claim
+	// carries the created_at Complete and Release identify it by
+	stored, err := queryOne(ctx, s.db, scanIdempotencyKey, `
+		SELECT `+idempotencyKeyColumns+`
+		FROM idempotency_keys
+		WHERE caller = ? AND idempotency_key = ?`, key.Caller, key.Key)
+	return stored, claimed, err
+}
+
+// Complete stores the response to a claimed key's request
+func (s *IdempotencyStore) Complete(ctx context.Context, claim models.IdempotencyKey, statusCode int, response []byte) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE idempotency_keys
+		SET status_code = ?, response = ?
+		WHERE caller = ? AND idempotency_key = ? AND status_code = 0 AND created_at = ?`,
+		statusCode, response, claim.Caller, claim.Key, claim.CreatedAt)
+}
+
+// Release deletes a claimed key
+func (s *IdempotencyStore) Release(ctx context.Context, claim models.IdempotencyKey) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE caller = ? AND idempotency_key = ? AND status_code = 0 AND created_at = ?`,
+		claim.Caller, claim.Key, claim.CreatedAt)
+	return err
+}
+
+// Sweep deletes the keys claimed before expired
+func (s *IdempotencyStore) Sweep(ctx context.Context, expired time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE created_at <= ?`, expired)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"slices"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// entryCount counts the rows of a stage table, taking each reversal as
+// minus one so the count drops along with the weight it cancels
+const entryCount = `COALESCE(SUM(IF(entry_type = 'reversal', -1, 1)), 0)`
+
+// execer is implemented by both *sql.DB and *sql.Tx, and so by every conn
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// correct runs insert, which stores the entries correcting the record id of
+// a stage table, in a transaction that first locks the record and checks
+// it can still be corrected
+func correct(ctx context.Context, db conn, table string, id any, insert func(tx conn) error) error {
+	return inTx(ctx, db, func(tx conn) error {
+		var entryType models.EntryType
+		err := tx.QueryRowContext(ctx, `SELECT entry_type FROM `+table+` WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&entryType)
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var reversed bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM `+table+` WHERE corrects_id = ? AND entry_type = ?
+			)`, id, models.EntryReversal).Scan(&reversed)
+		if err != nil {
+			return err
+		}
+		if reversed || !entryType.Correctable() {
+			return store.ErrConflict
+		}
+
+		return insert(tx)
+	})
+}
+
+// moveToTrash marks the record id of a stage table as deleted by the actor
+// in ctx, in a transaction that first locks the record and checks it is an
+// original nothing corrects. Corrections only make sense next to the record
+// they correct, so a record with any stays out of the trash
+func moveToTrash(ctx context.Context, db conn, table string, id any) error {
+	return inTx(ctx, db, func(tx conn) error {
+		var entryType models.EntryType
+		err := tx.QueryRowContext(ctx, `SELECT entry_type FROM `+table+` WHERE id = ? AND deleted_at IS NULL FOR UPDATE`, id).Scan(&entryType)
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var corrected bool
+		err = tx.QueryRowContext(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM `+table+` WHERE corrects_id = ?
+			)`, id).Scan(&corrected)
+		if err != nil {
+			return err
+		}
+		if corrected || entryType != models.EntryOriginal {
+			return store.ErrConflict
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE `+table+`
+			SET deleted_at = NOW(),
+				deleted_by = ?
+			WHERE id = ?`, store.DeletedBy(ctx), id)
+		return err
+	})
+}
+
+// errDeadlock is the MySQL error number for a transaction rolled back to
+// break a deadlock
+const errDeadlock = 1213
+
+// insertMany inserts a batch of stage records in one transaction. A failed
+// INSERT only undoes itself in MySQL, so without atomic the records after
+// it still go in; with atomic the first failure rolls the batch back. errs
+// holds each record's failure at its index; err is set when the batch as a
+// whole couldn't be stored, such as when a deadlock ended the transaction
+func insertMany[T any](ctx context.Context, db conn, records []*T, atomic bool, insert func(context.Context, execer, *T) error) (errs []error, err error) {
+	errs = make([]error, len(records))
+	rolledBack := errors.New("batch rolled back")
+	err = inTx(ctx, db, func(tx conn) error {
+		for i, record := range records {
+			err := insert(ctx, tx, record)
+			var mysqlErr *mysqldriver.MySQLError
+			if err != nil && (!errors.As(err, &mysqlErr) || mysqlErr.Number == errDeadlock) {
+				return err
+			}
+			errs[i] = err
+			if err != nil && atomic {
+				return rolledBack
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, rolledBack) {
+		return errs, nil
+	}
+	return errs, err
+}
+
+// fetchCreated refetches the records a batch insert stored, to get their
+// timestamps
+func fetchCreated[T any](records []*T, errs []error, atomic bool, fetch func(*T) error) error {
+	if atomic && slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+		return nil
+	}
+	for i, record := range records {
+		if errs[i] != nil {
+			continue
+		}
+		if err := fetch(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"healing_photons/internal/store"
+	"strings"
+)
+
+// listPage selects a page of records from table following spec. Field
+// names double as column names, and only names declared by the spec are
+// ever written into the query
+func listPage[T any](ctx context.Context, db conn, scan func(scanner) (T, error), spec store.ListSpec[T], table, columns string, opts store.ListOptions) (store.Page[T], error) {
+	var page store.Page[T]
+	var conditions []string
+	var args []any
+
+	if spec.Trashable {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	for _, filter := range opts.Filters {
+		if _, ok := spec.Fields[filter.Field]; !ok {
+			return page, fmt.Errorf("unknown field %q", filter.Field)
+		}
+		if filter.Value == nil {
+			conditions = append(conditions, filter.Field+" IS NULL")
+			continue
+		}
+		conditions = append(conditions, filter.Field+" = ?")
+		args = append(args, filter.Value)
+	}
+	if !opts.From.IsZero() || !opts.To.IsZero() {
+		if spec.TimeField == "" {
+			return page, fmt.Errorf("%s cannot be filtered by time", table)
+		}
+		if !opts.From.IsZero() {
+			conditions = append(conditions, spec.TimeField+" >= ?")
+			args = append(args, opts.From)
+		}
+		if !opts.To.IsZero() {
+			conditions = append(conditions, spec.TimeField+" < ?")
+			args = append(args, opts.To)
+		}
+	}
+
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+table+whereClause(conditions), args...).Scan(&page.Total)
+	if err != nil {
+		return page, err
+	}
+
+	sort := spec.SortOrDefault(opts)
+	if _, ok := spec.Fields[sort.Field]; !ok {
+		return page, fmt.Errorf("unknown field %q", sort.Field)
+	}
+	direction, comparison := "ASC", ">"
+	if sort.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if after := opts.After; after != nil {
+		if after.Sort != sort {
+			return page, fmt.Errorf("cursor was issued for sort %s", after.Sort)
+		}
+		conditions = append(conditions, fmt.Sprintf("(%[1]s %[3]s ? OR (%[1]s = ? AND %[2]s %[3]s ?))", sort.Field, spec.Key, comparison))
+		args = append(args, after.Value, after.Value, after.Key)
+	}
+
+	query := `SELECT ` + columns + ` FROM ` + table + whereClause(conditions) +
+		fmt.Sprintf(` ORDER BY %s %s, %s %s`, sort.Field, direction, spec.Key, direction)
+	if opts.Limit > 0 {
+		// Fetch one extra row to learn whether another page follows
+		query += ` LIMIT ?`
+		args = append(args, opts.Limit+1)
+	}
+
+	page.Items, err = queryAll(ctx, db, scan, query, args...)
+	if err != nil {
+		return page, err
+	}
+	if opts.Limit > 0 && len(page.Items) > opts.Limit {
+		page.Items = page.Items[:opts.Limit]
+		page.Next = spec.CursorAfter(page.Items[opts.Limit-1], sort)
+	}
+	return page, nil
+}
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return ` WHERE ` + strings.Join(conditions, ` AND `)
+}
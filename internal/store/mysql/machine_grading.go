@@ -0,0 +1,157 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const machineGradingColumns = `id, color_sort_id, stock_id, size_variations_id, pieces_id, weight, device_id, entry_type, corrects_id, reason, created_at, updated_at`
+
+// MachineGradingStore implements store.MachineGradingStore
+type MachineGradingStore struct {
+	db conn
+}
+
+func scanMachineGrading(row scanner) (models.MachineGrading, error) {
+	var grading models.MachineGrading
+	err := row.Scan(
+		&grading.ID,
+		&grading.ColorSortID,
+		&grading.StockID,
+		&grading.SizeVariationsID,
+		&grading.PiecesID,
+		&grading.Weight,
+		&grading.DeviceID,
+		&grading.EntryType,
+		&grading.CorrectsID,
+		&grading.Reason,
+		&grading.CreatedAt,
+		&grading.UpdatedAt,
+	)
+	return grading, err
+}
+
+// List returns a page of machine grading records matching opts
+func (s *MachineGradingStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.MachineGrading], error) {
+	return listPage(ctx, s.db, scanMachineGrading, store.MachineGradingList, "machine_grading", machineGradingColumns, opts)
+}
+
+// Get returns a single machine grading record
+func (s *MachineGradingStore) Get(ctx context.Context, id string) (models.MachineGrading, error) {
+	return queryOne(ctx, s.db, scanMachineGrading, `
+		SELECT `+machineGradingColumns+`
+		FROM machine_grading WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts an original machine grading record
+func (s *MachineGradingStore) Create(ctx context.Context, grading *models.MachineGrading) error {
+	grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+	return inTx(ctx, s.db, func(tx conn) error {
+		if err := insertMachineGrading(ctx, tx, grading); err != nil {
+			return err
+		}
+
+		// Fetch the created record in the same transaction to get timestamps
+		created, err := (&MachineGradingStore{db: tx}).Get(ctx, grading.ID)
+		if err != nil {
+			return err
+		}
+		*grading = created
+		return nil
+	})
+}
+
+// CreateMany inserts original machine grading records in one transaction
+func (s *MachineGradingStore) CreateMany(ctx context.Context, gradings []*models.MachineGrading, atomic bool) ([]error, error) {
+	for _, grading := range gradings {
+		grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+	}
+	errs, err := insertMany(ctx, s.db, gradings, atomic, insertMachineGrading)
+	if err != nil {
+		return errs, err
+	}
+	return errs, fetchCreated(gradings, errs, atomic, func(grading *models.MachineGrading) (err error) {
+		*grading, err = s.Get(ctx, grading.ID)
+		return err
+	})
+}
+
+// Correct stores the reversal of a machine grading record and its
+// replacement, if any, in one transaction
+func (s *MachineGradingStore) Correct(ctx context.Context, reversal, replacement *models.MachineGrading) error {
+	err := correct(ctx, s.db, "machine_grading", *reversal.CorrectsID, func(tx conn) error {
+		if err := insertMachineGrading(ctx, tx, reversal); err != nil {
+			return err
+		}
+		if replacement == nil {
+			return nil
+		}
+		return insertMachineGrading(ctx, tx, replacement)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fetch the stored entries to get timestamps
+	if *reversal, err = s.Get(ctx, reversal.ID); err != nil {
+		return err
+	}
+	if replacement != nil {
+		*replacement, err = s.Get(ctx, replacement.ID)
+	}
+	return err
+}
+
+// Delete moves a machine grading record to the trash
+func (s *MachineGradingStore) Delete(ctx context.Context, id string) error {
+	return moveToTrash(ctx, s.db, "machine_grading", id)
+}
+
+// insertMachineGrading writes one row of machine_grading with db
+func insertMachineGrading(ctx context.Context, db execer, grading *models.MachineGrading) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO machine_grading (
+			id, color_sort_id, stock_id, size_variations_id, pieces_id,
+			weight, device_id, entry_type, corrects_id, reason,
+			created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		grading.ID,
+		grading.ColorSortID,
+		grading.StockID,
+		grading.SizeVariationsID,
+		grading.PiecesID,
+		grading.Weight,
+		grading.DeviceID,
+		grading.EntryType,
+		grading.CorrectsID,
+		grading.Reason,
+	)
+	return err
+}
+
+// WeightSummary totals the graded weight for a stock
+func (s *MachineGradingStore) WeightSummary(ctx context.Context, stockID string) (models.MachineGradingSummary, error) {
+	summary := models.MachineGradingSummary{StockID: stockID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			stock_id,
+			COALESCE(SUM(weight), 0) as total_weight,
+			`+entryCount+` as record_count
+		FROM machine_grading
+		WHERE stock_id = ? AND deleted_at IS NULL
+		GROUP BY stock_id`,
+		stockID,
+	).Scan(
+		&summary.StockID,
+		&summary.TotalWeight,
+		&summary.RecordCount,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return summary, nil
+	}
+	return summary, err
+}
@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const manualGradingColumns = `id, grader_machine_outputs_id, stock_id, category_id,
+			size_id, piece_id, weight, worker_id, entry_type, corrects_id, reason,
+			created_at, updated_at`
+
+// ManualGradingStore implements store.ManualGradingStore
+type ManualGradingStore struct {
+	db conn
+}
+
+func scanManualGrading(row scanner) (models.ManualGrading, error) {
+	var grading models.ManualGrading
+	err := row.Scan(
+		&grading.ID,
+		&grading.GraderMachineOutputsID,
+		&grading.StockID,
+		&grading.CategoryID,
+		&grading.SizeID,
+		&grading.PieceID,
+		&grading.Weight,
+		&grading.WorkerID,
+		&grading.EntryType,
+		&grading.CorrectsID,
+		&grading.Reason,
+		&grading.CreatedAt,
+		&grading.UpdatedAt,
+	)
+	return grading, err
+}
+
+// List returns a page of manual grading records matching opts
+func (s *ManualGradingStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.ManualGrading], error) {
+	return listPage(ctx, s.db, scanManualGrading, store.ManualGradingList, "manual_grading", manualGradingColumns, opts)
+}
+
+// Get returns a single manual grading record
+func (s *ManualGradingStore) Get(ctx context.Context, id string) (models.ManualGrading, error) {
+	return queryOne(ctx, s.db, scanManualGrading, `
+		SELECT `+manualGradingColumns+`
+		FROM manual_grading WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts an original manual grading record
+func (s *ManualGradingStore) Create(ctx context.Context, grading *models.ManualGrading) error {
+	grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+	return inTx(ctx, s.db, func(tx conn) error {
+		if err := insertManualGrading(ctx, tx, grading); err != nil {
+			return err
+		}
+
+		// Fetch the created record in the same transaction to get timestamps
+		created, err := (&ManualGradingStore{db: tx}).Get(ctx, grading.ID)
+		if err != nil {
+			return err
+		}
+		*grading = created
+		return nil
+	})
+}
+
+// CreateMany inserts original manual grading records in one transaction
+func (s *ManualGradingStore) CreateMany(ctx context.Context, gradings []*models.ManualGrading, atomic bool) ([]error, error) {
+	for _, grading := range gradings {
+		grading.EntryType, grading.CorrectsID, grading.Reason = models.EntryOriginal, nil, ""
+	}
+	errs, err := insertMany(ctx, s.db, gradings, atomic, insertManualGrading)
+	if err != nil {
+		return errs, err
+	}
+	return errs, fetchCreated(gradings, errs, atomic, func(grading *models.ManualGrading) (err error) {
+		*grading, err = s.Get(ctx, grading.ID)
+		return err
+	})
+}
+
+// Correct stores the reversal of a manual grading record and its replacement,
+// if any, in one transaction
+func (s *ManualGradingStore) Correct(ctx context.Context, reversal, replacement *models.ManualGrading) error {
+	err := correct(ctx, s.db, "manual_grading", *reversal.CorrectsID, func(tx conn) error {
+		if err := insertManualGrading(ctx, tx, reversal); err != nil {
+			return err
+		}
+		if replacement == nil {
+			return nil
+		}
+		return insertManualGrading(ctx, tx, replacement)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fetch the stored entries to get timestamps
+	if *reversal, err = s.Get(ctx, reversal.ID); err != nil {
+		return err
+	}
+	if replacement != nil {
+		*replacement, err = s.Get(ctx, replacement.ID)
+	}
+	return err
+}
+
+// Delete moves a manual grading record to the trash
+func (s *ManualGradingStore) Delete(ctx context.Context, id string) error {
+	return moveToTrash(ctx, s.db, "manual_grading", id)
+}
+
+// insertManualGrading writes one row of manual_grading with db
+func insertManualGrading(ctx context.Context, db execer, grading *models.ManualGrading) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO manual_grading (
+			id, grader_machine_outputs_id, stock_id, category_id,
+			size_id, piece_id, weight, worker_id, entry_type,
+			corrects_id, reason, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		grading.ID,
+		grading.GraderMachineOutputsID,
+		grading.StockID,
+		grading.CategoryID,
+		grading.SizeID,
+		grading.PieceID,
+		grading.Weight,
+		grading.WorkerID,
+		grading.EntryType,
+		grading.CorrectsID,
+		grading.Reason,
+	)
+	return err
+}
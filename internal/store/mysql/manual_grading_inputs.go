@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const manualGradingInputColumns = `id, stock_id, worker_id, size_variations_id, weight, entry_type, corrects_id, reason, created_at, updated_at`
+
+// ManualGradingInputStore implements store.ManualGradingInputStore
+type ManualGradingInputStore struct {
+	db conn
+}
+
+func scanManualGradingInput(row scanner) (models.ManualGradingInput, error) {
+	var input models.ManualGradingInput
+	err := row.Scan(
+		&input.ID,
+		&input.StockID,
+		&input.WorkerID,
+		&input.SizeVariationsID,
+		&input.Weight,
+		&input.EntryType,
+		&input.CorrectsID,
+		&input.Reason,
+		&input.CreatedAt,
+		&input.UpdatedAt,
+	)
+	return input, err
+}
+
+// List returns a page of manual grading input records matching opts
+func (s *ManualGradingInputStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.ManualGradingInput], error) {
+	return listPage(ctx, s.db, scanManualGradingInput, store.ManualGradingInputList, "machine_grading_inputs", manualGradingInputColumns, opts)
+}
+
+// Get returns a single manual grading input record
+func (s *ManualGradingInputStore) Get(ctx context.Context, id int) (models.ManualGradingInput, error) {
+	return queryOne(ctx, s.db, scanManualGradingInput, `
+		SELECT `+manualGradingInputColumns+`
+		FROM machine_grading_inputs WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts an original manual grading input record, letting the
+// database assign the ID when none is set
+func (s *ManualGradingInputStore) Create(ctx context.Context, input *models.ManualGradingInput) error {
+	input.EntryType, input.CorrectsID, input.Reason = models.EntryOriginal, nil, ""
+	return inTx(ctx, s.db, func(tx conn) error {
+		if err := insertManualGradingInput(ctx, tx, input); err != nil {
+			return err
+		}
+
+		// Fetch the created record in the same transaction to get timestamps
+		created, err := (&ManualGradingInputStore{db: tx}).Get(ctx, input.ID)
+		if err != nil {
+			return err
+		}
+		*input = created
+		return nil
+	})
+}
+
+// CreateMany inserts original manual grading input records in one
+// transaction, letting the database assign the IDs of those without one
+func (s *ManualGradingInputStore) CreateMany(ctx context.Context, inputs []*models.ManualGradingInput, atomic bool) ([]error, error) {
+	for _, input := range inputs {
+		input.EntryType, input.CorrectsID, input.Reason = models.EntryOriginal, nil, ""
+	}
+	errs, err := insertMany(ctx, s.db, inputs, atomic, insertManualGradingInput)
+	if err != nil {
+		return errs, err
+	}
+	return errs, fetchCreated(inputs, errs, atomic, func(input *models.ManualGradingInput) (err error) {
+		*input, err = s.Get(ctx, input.ID)
+		return err
+	})
+}
+
+// Correct stores the reversal of a manual grading input record and its
+// replacement, if any, in one transaction
+func (s *ManualGradingInputStore) Correct(ctx context.Context, reversal, replacement *models.ManualGradingInput) error {
+	err := correct(ctx, s.db, "machine_grading_inputs", *reversal.CorrectsID, func(tx conn) error {
+		if err := insertManualGradingInput(ctx, tx, reversal); err != nil {
+			return err
+		}
+		if replacement == nil {
+			return nil
+		}
+		return insertManualGradingInput(ctx, tx, replacement)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fetch the stored entries to get timestamps
+	if *reversal, err = s.Get(ctx, reversal.ID); err != nil {
+		return err
+	}
+	if replacement != nil {
+		*replacement, err = s.Get(ctx, replacement.ID)
+	}
+	return err
+}
+
+// Delete moves a manual grading input to the trash
+func (s *ManualGradingInputStore) Delete(ctx context.Context, id int) error {
+	return moveToTrash(ctx, s.db, "machine_grading_inputs", id)
+}
+
+// insertManualGradingInput writes one row of machine_grading_inputs with
+// db, letting the database assign the ID when none is set
+func insertManualGradingInput(ctx context.Context, db execer, input *models.ManualGradingInput) error {
+	result, err := db.ExecContext(ctx, `
+		INSERT INTO machine_grading_inputs (
+			id, stock_id, worker_id, size_variations_id, weight,
+			entry_type, corrects_id, reason, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		input.ID,
+		input.StockID,
+		input.WorkerID,
+		input.SizeVariationsID,
+		input.Weight,
+		input.EntryType,
+		input.CorrectsID,
+		input.Reason,
+	)
+	if err != nil {
+		return err
+	}
+
+	if input.ID == 0 {
+		lastID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		input.ID = int(lastID)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Package mysql implements the storage interfaces on top of MySQL
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"healing_photons/internal/store"
+	"strings"
+)
+
+// NewStores returns MySQL backed stores for every entity
+func NewStores(db *sql.DB) *store.Stores {
+	return newStores(database{db})
+}
+
+// newStores returns stores running their statements on db, which is the
+// transaction of a unit of work when they belong to one
+func newStores(db conn) *store.Stores {
+	return &store.Stores{
+		Stocks:               &StockStore{db: db},
+		Humidifiers:          &HumidifierStore{db: db},
+		PeelingMachines:      &PeelingMachineStore{db: db},
+		ColorSorts:           &ColorSortStore{db: db},
+		MachineGradings:      &MachineGradingStore{db: db},
+		ManualGradings:       &ManualGradingStore{db: db},
+		ManualGradingInputs:  &ManualGradingInputStore{db: db},
+		GradingSheets:        &GradingSheetStore{db: db},
+		GraderMachineOutputs: &GraderMachineOutputStore{db: db},
+		GradingCategories:    &GradingCategoryStore{db: db},
+		Pieces:               &PieceStore{db: db},
+		SizeVariations:       &SizeVariationStore{db: db},
+		WeightTypes:          &WeightTypeStore{db: db},
+		Workforce:            &WorkforceStore{db: db},
+		Users:                &UserStore{db: db},
+		Sessions:             &SessionStore{db: db},
+		Sellers:              &SellerStore{db: db},
+		Devices:              &DeviceStore{db: db},
+		Audit:                &AuditStore{db: db},
+		Anomalies:            &AnomalyStore{db: db},
+		Trash:                &TrashStore{db: db},
+		Sequences:            &SequenceStore{db: db},
+		Idempotency:          &IdempotencyStore{db: db},
+		Reports:              &ReportStore{db: db},
+		UnitOfWork:           &UnitOfWork{db: db},
+	}
+}
+
+// scanner is implemented by both *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// queryAll runs the query and scans every row, returning an empty slice
+// rather than nil when nothing matches
+func queryAll[T any](ctx context.Context, db conn, scan func(scanner) (T, error), query string, args ...any) ([]T, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []T{}
+	for rows.Next() {
+		record, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// queryOne runs the query and scans the first row, translating a missing
+// row into store.ErrNotFound. Inside a unit of work, a read marked with
+// store.ForUpdate locks the row until the unit ends
+func queryOne[T any](ctx context.Context, db conn, scan func(scanner) (T, error), query string, args ...any) (T, error) {
+	if _, ok := db.(transaction); ok && store.Locking(ctx) {
+		query += " FOR UPDATE"
+	}
+	record, err := scan(db.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return record, store.ErrNotFound
+	}
+	return record, err
+}
+
+// execAffecting runs the statement and returns store.ErrNotFound when it
+// did not match any row. The connection sets clientFoundRows, so an UPDATE
+// that leaves a row as it was still counts it
+func execAffecting(ctx context.Context, db conn, query string, args ...any) error {
+	result, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+// placeholders returns n comma separated bind markers for an IN list
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
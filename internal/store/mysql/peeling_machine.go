@@ -0,0 +1,130 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const peelingMachineColumns = `id, humidifier_id, stock_id, weight_type_id, weight, device_id, entry_type, corrects_id, reason, created_at, updated_at`
+
+// PeelingMachineStore implements store.PeelingMachineStore
+type PeelingMachineStore struct {
+	db conn
+}
+
+func scanPeelingMachine(row scanner) (models.PeelingMachine, error) {
+	var machine models.PeelingMachine
+	err := row.Scan(
+		&machine.ID,
+		&machine.HumidifierID,
+		&machine.StockID,
+		&machine.WeightTypeID,
+		&machine.Weight,
+		&machine.DeviceID,
+		&machine.EntryType,
+		&machine.CorrectsID,
+		&machine.Reason,
+		&machine.CreatedAt,
+		&machine.UpdatedAt,
+	)
+	return machine, err
+}
+
+// List returns a page of peeling machine records matching opts
+func (s *PeelingMachineStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.PeelingMachine], error) {
+	return listPage(ctx, s.db, scanPeelingMachine, store.PeelingMachineList, "peeling_machine", peelingMachineColumns, opts)
+}
+
+// Get returns a single peeling machine record
+func (s *PeelingMachineStore) Get(ctx context.Context, id string) (models.PeelingMachine, error) {
+	return queryOne(ctx, s.db, scanPeelingMachine, `
+		SELECT `+peelingMachineColumns+`
+		FROM peeling_machine WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts an original peeling machine record
+func (s *PeelingMachineStore) Create(ctx context.Context, machine *models.PeelingMachine) error {
+	machine.EntryType, machine.CorrectsID, machine.Reason = models.EntryOriginal, nil, ""
+	return inTx(ctx, s.db, func(tx conn) error {
+		if err := insertPeelingMachine(ctx, tx, machine); err != nil {
+			return err
+		}
+
+		// Fetch the created record in the same transaction to get timestamps
+		created, err := (&PeelingMachineStore{db: tx}).Get(ctx, machine.ID)
+		if err != nil {
+			return err
+		}
+		*machine = created
+		return nil
+	})
+}
+
+// CreateMany inserts original peeling machine records in one transaction
+func (s *PeelingMachineStore) CreateMany(ctx context.Context, machines []*models.PeelingMachine, atomic bool) ([]error, error) {
+	for _, machine := range machines {
+		machine.EntryType, machine.CorrectsID, machine.Reason = models.EntryOriginal, nil, ""
+	}
+	errs, err := insertMany(ctx, s.db, machines, atomic, insertPeelingMachine)
+	if err != nil {
+		return errs, err
+	}
+	return errs, fetchCreated(machines, errs, atomic, func(machine *models.PeelingMachine) (err error) {
+		*machine, err = s.Get(ctx, machine.ID)
+		return err
+	})
+}
+
+// Correct stores the reversal of a peeling machine record and its
+// replacement, if any, in one transaction
+func (s *PeelingMachineStore) Correct(ctx context.Context, reversal, replacement *models.PeelingMachine) error {
+	err := correct(ctx, s.db, "peeling_machine", *reversal.CorrectsID, func(tx conn) error {
+		if err := insertPeelingMachine(ctx, tx, reversal); err != nil {
+			return err
+		}
+		if replacement == nil {
+			return nil
+		}
+		return insertPeelingMachine(ctx, tx, replacement)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Fetch the stored entries to get timestamps
+	if *reversal, err = s.Get(ctx, reversal.ID); err != nil {
+		return err
+	}
+	if replacement != nil {
+		*replacement, err = s.Get(ctx, replacement.ID)
+	}
+	return err
+}
+
+// Delete moves a peeling machine record to the trash
+func (s *PeelingMachineStore) Delete(ctx context.Context, id string) error {
+	return moveToTrash(ctx, s.db, "peeling_machine", id)
+}
+
+// insertPeelingMachine writes one row of peeling_machine with db
+func insertPeelingMachine(ctx context.Context, db execer, machine *models.PeelingMachine) error {
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO peeling_machine (
+			id, humidifier_id, stock_id, weight_type_id, weight,
+			device_id, entry_type, corrects_id, reason, created_at,
+			updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		machine.ID,
+		machine.HumidifierID,
+		machine.StockID,
+		machine.WeightTypeID,
+		machine.Weight,
+		machine.DeviceID,
+		machine.EntryType,
+		machine.CorrectsID,
+		machine.Reason,
+	)
+	return err
+}
@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// PieceStore implements store.PieceStore
+type PieceStore struct {
+	db conn
+}
+
+func scanPiece(row scanner) (models.Pieces, error) {
+	var piece models.Pieces
+	err := row.Scan(
+		&piece.PieceID,
+		&piece.PieceCode,
+		&piece.Description,
+	)
+	return piece, err
+}
+
+// List returns a page of pieces matching opts
+func (s *PieceStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Pieces], error) {
+	return listPage(ctx, s.db, scanPiece, store.PieceList, "pieces", `piece_id, piece_code, description`, opts)
+}
+
+// Get returns a single piece
+func (s *PieceStore) Get(ctx context.Context, id int) (models.Pieces, error) {
+	return queryOne(ctx, s.db, scanPiece, `
+		SELECT piece_id, piece_code, description
+		FROM pieces WHERE piece_id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a piece, letting the database assign the ID when none is set
+func (s *PieceStore) Create(ctx context.Context, piece *models.Pieces) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO pieces (
+			piece_id, piece_code, description
+		)
+		VALUES (?, ?, ?)`,
+		piece.PieceID,
+		piece.PieceCode,
+		piece.Description,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	piece.PieceID = int(lastID)
+	return nil
+}
+
+// Update overwrites an existing piece
+func (s *PieceStore) Update(ctx context.Context, id int, piece models.Pieces) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE pieces
+		SET piece_code = ?,
+			description = ?
+		WHERE piece_id = ? AND deleted_at IS NULL`,
+		piece.PieceCode,
+		piece.Description,
+		id,
+	)
+}
+
+// Delete moves a piece to the trash
+func (s *PieceStore) Delete(ctx context.Context, id int) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE pieces
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE piece_id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"healing_photons/internal/models"
+)
+
+// ReportStore implements store.ReportStore
+type ReportStore struct {
+	db conn
+}
+
+// StageTotals sums the records of every processing stage for a lot in a
+// single round trip. Reversals count as minus one record
+func (s *ReportStore) StageTotals(ctx context.Context, stockID string) (models.StockStageTotals, error) {
+	totals := models.StockStageTotals{StockID: stockID}
+	stages := map[string]*models.StageTotal{
+		"humidifier":             &totals.Humidifier,
+		"peeling_machine":        &totals.PeelingMachine,
+		"color_sort":             &totals.ColorSort,
+		"machine_grading":        &totals.MachineGrading,
+		"machine_grading_inputs": &totals.MachineGradingInputs,
+		"manual_grading":         &totals.ManualGrading,
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT 'humidifier', COALESCE(SUM(weight), 0), `+entryCount+` FROM humidifier WHERE stock_id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT 'peeling_machine', COALESCE(SUM(weight), 0), `+entryCount+` FROM peeling_machine WHERE stock_id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT 'color_sort', COALESCE(SUM(accepted_weight), 0), `+entryCount+` FROM color_sort WHERE stock_id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT 'machine_grading', COALESCE(SUM(weight), 0), `+entryCount+` FROM machine_grading WHERE stock_id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT 'machine_grading_inputs', COALESCE(SUM(weight), 0), `+entryCount+` FROM machine_grading_inputs WHERE stock_id = ? AND deleted_at IS NULL
+		UNION ALL
+		SELECT 'manual_grading', COALESCE(SUM(weight), 0), `+entryCount+` FROM manual_grading WHERE stock_id = ? AND deleted_at IS NULL`,
+		stockID, stockID, stockID, stockID, stockID, stockID,
+	)
+	if err != nil {
+		return totals, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stage string
+		var total models.StageTotal
+		if err := rows.Scan(&stage, &total.Weight, &total.Records); err != nil {
+			return totals, err
+		}
+		target, ok := stages[stage]
+		if !ok {
+			return totals, fmt.Errorf("unexpected stage %q", stage)
+		}
+		*target = total
+	}
+	return totals, rows.Err()
+}
+
+// GradeWeights sums the manual grading of each lot by grading category
+func (s *ReportStore) GradeWeights(ctx context.Context, stockIDs []string) ([]models.GradeWeight, error) {
+	if len(stockIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(stockIDs))
+	for i, id := range stockIDs {
+		args[i] = id
+	}
+	return queryAll(ctx, s.db, scanGradeWeight, `
+		SELECT mg.stock_id, COALESCE(gc.category_code, ''), SUM(mg.weight), SUM(IF(mg.entry_type = 'reversal', -1, 1))
+		FROM manual_grading mg
+		LEFT JOIN grading_categories gc ON gc.category_id = mg.category_id
+		WHERE mg.stock_id IN (`+placeholders(len(stockIDs))+`) AND mg.deleted_at IS NULL
+		GROUP BY mg.stock_id, gc.category_code
+		ORDER BY mg.stock_id, gc.category_code`, args...)
+}
+
+func scanGradeWeight(row scanner) (models.GradeWeight, error) {
+	var grade models.GradeWeight
+	err := row.Scan(&grade.StockID, &grade.CategoryCode, &grade.Weight, &grade.Records)
+	return grade, err
+}
@@ -0,0 +1,136 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const sellerColumns = `id, name, contact_name, phone, email, gstin, country, created_at, updated_at`
+
+// SellerStore implements store.SellerStore
+type SellerStore struct {
+	db conn
+}
+
+func scanSeller(row scanner) (models.Seller, error) {
+	var seller models.Seller
+	err := row.Scan(
+		&seller.ID,
+		&seller.Name,
+		&seller.ContactName,
+		&seller.Phone,
+		&seller.Email,
+		&seller.GSTIN,
+		&seller.Country,
+		&seller.CreatedAt,
+		&seller.UpdatedAt,
+	)
+	return seller, err
+}
+
+// List returns a page of sellers matching opts
+func (s *SellerStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Seller], error) {
+	return listPage(ctx, s.db, scanSeller, store.SellerList, "sellers", sellerColumns, opts)
+}
+
+// Get returns a single seller
+func (s *SellerStore) Get(ctx context.Context, id int64) (models.Seller, error) {
+	return queryOne(ctx, s.db, scanSeller, `
+		SELECT `+sellerColumns+`
+		FROM sellers WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// GetByName returns the seller whose name matches ignoring case and spacing
+func (s *SellerStore) GetByName(ctx context.Context, name string) (models.Seller, error) {
+	return queryOne(ctx, s.db, scanSeller, `
+		SELECT `+sellerColumns+`
+		FROM sellers WHERE name_key = ? AND deleted_at IS NULL`, models.SellerNameKey(name))
+}
+
+// Create inserts a seller
+func (s *SellerStore) Create(ctx context.Context, seller *models.Seller) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO sellers (
+			id, name, name_key, contact_name, phone, email, gstin, country, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+		seller.ID,
+		seller.Name,
+		models.SellerNameKey(seller.Name),
+		seller.ContactName,
+		seller.Phone,
+		seller.Email,
+		seller.GSTIN,
+		seller.Country,
+	)
+	if err != nil {
+		return err
+	}
+	if seller.ID == 0 {
+		if seller.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the created record to get timestamps
+	created, err := s.Get(ctx, seller.ID)
+	if err != nil {
+		return err
+	}
+	*seller = created
+	return nil
+}
+
+// Update overwrites a seller and copies its name onto its stock lots in the
+// same transaction
+func (s *SellerStore) Update(ctx context.Context, id int64, seller models.Seller) error {
+	return inTx(ctx, s.db, func(tx conn) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE sellers
+			SET name = ?,
+				name_key = ?,
+				contact_name = ?,
+				phone = ?,
+				email = ?,
+				gstin = ?,
+				country = ?,
+				updated_at = NOW()
+			WHERE id = ? AND deleted_at IS NULL`,
+			seller.Name,
+			models.SellerNameKey(seller.Name),
+			seller.ContactName,
+			seller.Phone,
+			seller.Email,
+			seller.GSTIN,
+			seller.Country,
+			id,
+		)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return store.ErrNotFound
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE stock SET seller_name = ? WHERE seller_id = ?", seller.Name, id)
+		return err
+	})
+}
+
+// Delete moves a seller to the trash
+func (s *SellerStore) Delete(ctx context.Context, id int64) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE sellers
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
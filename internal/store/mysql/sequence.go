@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"context"
+)
+
+// SequenceStore implements store.SequenceStore
+type SequenceStore struct {
+	db conn
+}
+
+// Next increments a sequence in a single statement. LAST_INSERT_ID(expr)
+// hands the new value back with the result, so no second query can see
+// another request's increment
+func (s *SequenceStore) Next(ctx context.Context, name string) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO id_sequences (name, value)
+		VALUES (?, LAST_INSERT_ID(1))
+		ON DUPLICATE KEY UPDATE value = LAST_INSERT_ID(value + 1)`, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const sessionColumns = `id, user_id, token_hash, expires_at, revoked_at, created_at`
+
+// SessionStore implements store.SessionStore
+type SessionStore struct {
+	db conn
+}
+
+func scanSession(row scanner) (models.Session, error) {
+	var session models.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.TokenHash,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+		&session.CreatedAt,
+	)
+	return session, err
+}
+
+// Create inserts a session
+func (s *SessionStore) Create(ctx context.Context, session *models.Session) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO sessions (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, NOW())`,
+		session.UserID,
+		session.TokenHash,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+	if session.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+
+	// Fetch the created record to get timestamps
+	created, err := queryOne(ctx, s.db, scanSession, `
+		SELECT `+sessionColumns+`
+		FROM sessions WHERE id = ?`, session.ID)
+	if err != nil {
+		return err
+	}
+	*session = created
+	return nil
+}
+
+// GetByTokenHash returns the session a refresh token belongs to
+func (s *SessionStore) GetByTokenHash(ctx context.Context, tokenHash string) (models.Session, error) {
+	return queryOne(ctx, s.db, scanSession, `
+		SELECT `+sessionColumns+`
+		FROM sessions WHERE token_hash = ?`, tokenHash)
+}
+
+// Revoke ends a session unless it has already been revoked
+func (s *SessionStore) Revoke(ctx context.Context, id int64) error {
+	err := execAffecting(ctx, s.db, `
+		UPDATE sessions SET revoked_at = NOW()
+		WHERE id = ? AND revoked_at IS NULL`, id)
+	if errors.Is(err, store.ErrNotFound) {
+		var exists bool
+		if err := s.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sessions WHERE id = ?)", id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return store.ErrConflict
+		}
+	}
+	return err
+}
+
+// RevokeUser ends every active session of a user
+func (s *SessionStore) RevokeUser(ctx context.Context, userID int64) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE sessions SET revoked_at = NOW()
+		WHERE user_id = ? AND revoked_at IS NULL`, userID)
+	return err
+}
@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+// SizeVariationStore implements store.SizeVariationStore
+type SizeVariationStore struct {
+	db conn
+}
+
+func scanSizeVariation(row scanner) (models.SizeVariations, error) {
+	var variation models.SizeVariations
+	err := row.Scan(
+		&variation.SizeID,
+		&variation.SizeValue,
+	)
+	return variation, err
+}
+
+// List returns a page of size variations matching opts
+func (s *SizeVariationStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.SizeVariations], error) {
+	return listPage(ctx, s.db, scanSizeVariation, store.SizeVariationList, "size_variations", `size_id, size_value`, opts)
+}
+
+// Get returns a single size variation
+func (s *SizeVariationStore) Get(ctx context.Context, id int) (models.SizeVariations, error) {
+	return queryOne(ctx, s.db, scanSizeVariation, `
+		SELECT size_id, size_value
+		FROM size_variations WHERE size_id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a size variation, letting the database assign the ID when
+// none is set
+func (s *SizeVariationStore) Create(ctx context.Context, variation *models.SizeVariations) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO size_variations (
+			size_id, size_value
+		)
+		VALUES (?, ?)`,
+		variation.SizeID,
+		variation.SizeValue,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	variation.SizeID = int(lastID)
+	return nil
+}
+
+// Update overwrites an existing size variation
+func (s *SizeVariationStore) Update(ctx context.Context, id int, variation models.SizeVariations) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE size_variations
+		SET size_value = ?
+		WHERE size_id = ? AND deleted_at IS NULL`,
+		variation.SizeValue,
+		id,
+	)
+}
+
+// Delete moves a size variation to the trash
+func (s *SizeVariationStore) Delete(ctx context.Context, id int) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE size_variations
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE size_id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
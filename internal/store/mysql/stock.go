@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+)
+
+const stockColumns = `stock_id, seller_id, seller_name, origin_country, weight, promised_outturn, date, status, created_at, updated_at`
+
+const stockTransitionColumns = `id, stock_id, from_status, to_status, note, created_at`
+
+// StockStore implements store.StockStore
+type StockStore struct {
+	db conn
+}
+
+func scanStock(row scanner) (models.Stock, error) {
+	var stock models.Stock
+	err := row.Scan(
+		&stock.StockID,
+		&stock.SellerID,
+		&stock.SellerName,
+		&stock.OriginCountry,
+		&stock.Weight,
+		&stock.PromisedOutturn,
+		&stock.Date,
+		&stock.Status,
+		&stock.CreatedAt,
+		&stock.UpdatedAt,
+	)
+	return stock, err
+}
+
+// List returns a page of stock lots matching opts
+func (s *StockStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Stock], error) {
+	return listPage(ctx, s.db, scanStock, store.StockList, "stock", stockColumns, opts)
+}
+
+// Get returns a single stock
+func (s *StockStore) Get(ctx context.Context, id string) (models.Stock, error) {
+	return queryOne(ctx, s.db, scanStock, `
+		SELECT `+stockColumns+`
+		FROM stock WHERE stock_id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts the stock. An ID already taken, by a live lot or one in
+// the trash, is refused by the primary key
+func (s *StockStore) Create(ctx context.Context, stock *models.Stock) error {
+	return inTx(ctx, s.db, func(tx conn) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO stock (
+				stock_id, seller_id, seller_name, origin_country, weight, promised_outturn, date, status, created_at, updated_at
+			)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())`,
+			stock.StockID,
+			stock.SellerID,
+			stock.SellerName,
+			stock.OriginCountry,
+			stock.Weight,
+			stock.PromisedOutturn,
+			stock.Date,
+			models.StatusReceived,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Fetch the stored record in the same transaction to get timestamps
+		created, err := (&StockStore{db: tx}).Get(ctx, stock.StockID)
+		if err != nil {
+			return err
+		}
+		*stock = created
+		return nil
+	})
+}
+
+// Update overwrites an existing stock
+func (s *StockStore) Update(ctx context.Context, id string, stock models.Stock) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE stock
+		SET seller_id = ?,
+			seller_name = ?,
+			origin_country = ?,
+			weight = ?,
+			promised_outturn = ?,
+			date = ?,
+			updated_at = NOW()
+		WHERE stock_id = ? AND deleted_at IS NULL`,
+		stock.SellerID,
+		stock.SellerName,
+		stock.OriginCountry,
+		stock.Weight,
+		stock.PromisedOutturn,
+		stock.Date,
+		id,
+	)
+}
+
+// Delete moves a stock to the trash
+func (s *StockStore) Delete(ctx context.Context, id string) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE stock
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE stock_id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
+
+func scanStockTransition(row scanner) (models.StockTransition, error) {
+	var transition models.StockTransition
+	err := row.Scan(
+		&transition.ID,
+		&transition.StockID,
+		&transition.FromStatus,
+		&transition.ToStatus,
+		&transition.Note,
+		&transition.CreatedAt,
+	)
+	return transition, err
+}
+
+// Transition moves a lot to a new status if it is still in from, and
+// records the change in the same transaction
+func (s *StockStore) Transition(ctx context.Context, id string, from, to models.StockStatus, note string) (models.StockTransition, error) {
+	var transition models.StockTransition
+	err := inTx(ctx, s.db, func(tx conn) error {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE stock
+			SET status = ?,
+				updated_at = NOW()
+			WHERE stock_id = ? AND status = ? AND deleted_at IS NULL`,
+			to, id, from,
+		)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			var status string
+			err := tx.QueryRowContext(ctx, "SELECT status FROM stock WHERE stock_id = ? AND deleted_at IS NULL", id).Scan(&status)
+			if errors.Is(err, sql.ErrNoRows) {
+				return store.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+			return store.ErrConflict
+		}
+
+		result, err = tx.ExecContext(ctx, `
+			INSERT INTO stock_transitions (
+				stock_id, from_status, to_status, note, created_at
+			)
+			VALUES (?, ?, ?, ?, NOW())`,
+			id, from, to, note,
+		)
+		if err != nil {
+			return err
+		}
+		transitionID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		transition, err = scanStockTransition(tx.QueryRowContext(ctx, `
+			SELECT `+stockTransitionColumns+`
+			FROM stock_transitions WHERE id = ?`, transitionID))
+		return err
+	})
+	return transition, err
+}
+
+// ListTransitions returns a page of recorded status changes matching opts
+func (s *StockStore) ListTransitions(ctx context.Context, opts store.ListOptions) (store.Page[models.StockTransition], error) {
+	return listPage(ctx, s.db, scanStockTransition, store.StockTransitionList, "stock_transitions", stockTransitionColumns, opts)
+}
@@ -0,0 +1,126 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+const trashColumns = `entity, id, label, deleted_at, deleted_by`
+
+// errRowIsReferenced is the MySQL error number for a delete blocked by a
+// foreign key
+const errRowIsReferenced = 1451
+
+// trashTable is where the deleted records of an entity are kept
+type trashTable struct {
+	table string
+	key   string
+	// label is the column shown alongside the key in the trash
+	label string
+}
+
+var trashTables = map[string]trashTable{
+	models.EntityStock:               {"stock", "stock_id", "seller_name"},
+	models.EntitySeller:              {"sellers", "id", "name"},
+	models.EntityGradingSheet:        {"grading_sheets", "id", "stock_id"},
+	models.EntityPiece:               {"pieces", "piece_id", "piece_code"},
+	models.EntitySizeVariation:       {"size_variations", "size_id", "size_value"},
+	models.EntityGraderMachineOutput: {"grader_machine_outputs", "id", "type"},
+	models.EntityWeightType:          {"weight_types", "id", "type"},
+	models.EntityWorkforce:           {"workforce", "id", "name"},
+	models.EntityGradingCategory:     {"grading_categories", "category_id", "category_code"},
+	models.EntityHumidifier:          {"humidifier", "id", "stock_id"},
+	models.EntityPeelingMachine:      {"peeling_machine", "id", "stock_id"},
+	models.EntityColorSort:           {"color_sort", "id", "stock_id"},
+	models.EntityMachineGrading:      {"machine_grading", "id", "stock_id"},
+	models.EntityManualGradingInput:  {"machine_grading_inputs", "id", "stock_id"},
+	models.EntityManualGrading:       {"manual_grading", "id", "stock_id"},
+}
+
+// deletedRows selects the trashed records of entity in the shape of a
+// models.TrashItem, plus the ref column TrashList orders by
+func (t trashTable) deletedRows(entity string) string {
+	return `
+		SELECT '` + entity + `' AS entity,
+			CAST(` + t.key + ` AS CHAR) AS id,
+			COALESCE(CAST(` + t.label + ` AS CHAR), '') AS label,
+			deleted_at,
+			COALESCE(deleted_by, '') AS deleted_by,
+			CONCAT('` + entity + `/', ` + t.key + `) AS ref
+		FROM ` + t.table + `
+		WHERE deleted_at IS NOT NULL`
+}
+
+// TrashStore implements store.TrashStore
+type TrashStore struct {
+	db conn
+}
+
+func scanTrashItem(row scanner) (models.TrashItem, error) {
+	var item models.TrashItem
+	err := row.Scan(
+		&item.Entity,
+		&item.ID,
+		&item.Label,
+		&item.DeletedAt,
+		&item.DeletedBy,
+	)
+	return item, err
+}
+
+// List returns a page of deleted records across every trashable table
+func (s *TrashStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.TrashItem], error) {
+	selects := make([]string, 0, len(models.TrashEntities))
+	for _, entity := range models.TrashEntities {
+		selects = append(selects, trashTables[entity].deletedRows(entity))
+	}
+	table := `(` + strings.Join(selects, ` UNION ALL `) + `) AS trash`
+	return listPage(ctx, s.db, scanTrashItem, store.TrashList, table, trashColumns, opts)
+}
+
+// Get returns a single deleted record. The key is matched inside the
+// derived table so the lookup, and any lock taken with it, uses the key's
+// index rather than scanning the trash
+func (s *TrashStore) Get(ctx context.Context, entity, id string) (models.TrashItem, error) {
+	t, ok := trashTables[entity]
+	if !ok {
+		return models.TrashItem{}, store.ErrNotFound
+	}
+	return queryOne(ctx, s.db, scanTrashItem, `
+		SELECT `+trashColumns+`
+		FROM (`+t.deletedRows(entity)+` AND `+t.key+` = ?) AS trash`, id)
+}
+
+// Restore clears the deletion marks of a record in the trash
+func (s *TrashStore) Restore(ctx context.Context, entity, id string) error {
+	t, ok := trashTables[entity]
+	if !ok {
+		return store.ErrNotFound
+	}
+	return execAffecting(ctx, s.db, `
+		UPDATE `+t.table+`
+		SET deleted_at = NULL,
+			deleted_by = NULL
+		WHERE `+t.key+` = ? AND deleted_at IS NOT NULL`, id)
+}
+
+// Purge deletes a record in the trash for good
+func (s *TrashStore) Purge(ctx context.Context, entity, id string) error {
+	t, ok := trashTables[entity]
+	if !ok {
+		return store.ErrNotFound
+	}
+	err := execAffecting(ctx, s.db, `
+		DELETE FROM `+t.table+`
+		WHERE `+t.key+` = ? AND deleted_at IS NOT NULL`, id)
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errRowIsReferenced {
+		return store.ErrConflict
+	}
+	return err
+}
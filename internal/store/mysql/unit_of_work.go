@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"healing_photons/internal/store"
+	"sync/atomic"
+)
+
+// conn is what stores run their statements on: the database itself, or the
+// transaction of a unit of work
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// savepoints numbers savepoints so nested ones never share a name
+var savepoints atomic.Int64
+
+// inTx runs fn in a transaction, committed when fn returns nil and rolled
+// back otherwise. When db already is a transaction fn runs inside it, behind
+// a savepoint, so a failure only undoes fn's own statements and the outer
+// transaction decides whether the rest is kept
+func inTx(ctx context.Context, db conn, fn func(tx conn) error) (err error) {
+	if d, ok := db.(database); ok {
+		tx, err := d.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if err := fn(transaction{tx}); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	savepoint := fmt.Sprintf("sp_%d", savepoints.Add(1))
+	if _, err := db.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		}
+	}()
+	if err := fn(db); err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	return err
+}
+
+// UnitOfWork implements store.UnitOfWork with a database transaction
+type UnitOfWork struct {
+	db conn
+}
+
+// Do runs work with stores bound to one transaction
+func (u *UnitOfWork) Do(ctx context.Context, work func(stores *store.Stores) error) error {
+	return inTx(ctx, u.db, func(tx conn) error {
+		return work(newStores(tx))
+	})
+}
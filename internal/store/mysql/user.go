@@ -0,0 +1,91 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+const userColumns = `id, username, password_hash, role, created_at, updated_at`
+
+// UserStore implements store.UserStore
+type UserStore struct {
+	db conn
+}
+
+func scanUser(row scanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Role,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	return user, err
+}
+
+// List returns every user ordered by username
+func (s *UserStore) List(ctx context.Context) ([]models.User, error) {
+	return queryAll(ctx, s.db, scanUser, `SELECT `+userColumns+` FROM users ORDER BY username`)
+}
+
+// Get returns a single user
+func (s *UserStore) Get(ctx context.Context, id int64) (models.User, error) {
+	return queryOne(ctx, s.db, scanUser, `
+		SELECT `+userColumns+`
+		FROM users WHERE id = ?`, id)
+}
+
+// GetByUsername returns the user with the given username
+func (s *UserStore) GetByUsername(ctx context.Context, username string) (models.User, error) {
+	return queryOne(ctx, s.db, scanUser, `
+		SELECT `+userColumns+`
+		FROM users WHERE username = ?`, username)
+}
+
+// Create inserts a user
+func (s *UserStore) Create(ctx context.Context, user *models.User) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO users (
+			id, username, password_hash, role, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, NOW(), NOW())`,
+		user.ID,
+		user.Username,
+		user.PasswordHash,
+		user.Role,
+	)
+	if err != nil {
+		return err
+	}
+	if user.ID == 0 {
+		if user.ID, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	}
+
+	// Fetch the created record to get timestamps
+	created, err := s.Get(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	*user = created
+	return nil
+}
+
+// Update overwrites an existing user
+func (s *UserStore) Update(ctx context.Context, id int64, user models.User) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE users
+		SET username = ?,
+			password_hash = ?,
+			role = ?,
+			updated_at = NOW()
+		WHERE id = ?`,
+		user.Username,
+		user.PasswordHash,
+		user.Role,
+		id,
+	)
+}
@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strconv"
+)
+
+// WeightTypeStore implements store.WeightTypeStore
+type WeightTypeStore struct {
+	db conn
+}
+
+func scanWeightType(row scanner) (models.WeightTypes, error) {
+	var weightType models.WeightTypes
+	err := row.Scan(
+		&weightType.ID,
+		&weightType.Type,
+	)
+	return weightType, err
+}
+
+// List returns a page of weight types matching opts
+func (s *WeightTypeStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.WeightTypes], error) {
+	return listPage(ctx, s.db, scanWeightType, store.WeightTypeList, "weight_types", `id, type`, opts)
+}
+
+// Get returns a single weight type
+func (s *WeightTypeStore) Get(ctx context.Context, id string) (models.WeightTypes, error) {
+	return queryOne(ctx, s.db, scanWeightType, `
+		SELECT id, type
+		FROM weight_types WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a weight type, picking up the auto-increment ID if the
+// database assigned one
+func (s *WeightTypeStore) Create(ctx context.Context, weightType *models.WeightTypes) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO weight_types (id, type)
+		VALUES (?, ?)`,
+		weightType.ID,
+		weightType.Type,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if lastID != 0 {
+		weightType.ID = strconv.FormatInt(lastID, 10)
+	}
+	return nil
+}
+
+// Update overwrites an existing weight type
+func (s *WeightTypeStore) Update(ctx context.Context, id string, weightType models.WeightTypes) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE weight_types
+		SET type = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		weightType.Type,
+		id,
+	)
+}
+
+// Delete moves a weight type to the trash
+func (s *WeightTypeStore) Delete(ctx context.Context, id string) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE weight_types
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
+
+// Usage returns every weight type with the number of peeling machine and
+// color sort records using it, most used first
+func (s *WeightTypeStore) Usage(ctx context.Context) ([]models.WeightTypeUsage, error) {
+	return queryAll(ctx, s.db, func(row scanner) (models.WeightTypeUsage, error) {
+		var usage models.WeightTypeUsage
+		err := row.Scan(
+			&usage.ID,
+			&usage.Type,
+			&usage.UsageCount,
+		)
+		return usage, err
+	}, `
+		SELECT wt.id, wt.type,
+			(SELECT COUNT(*) FROM peeling_machine pm WHERE pm.weight_type_id = wt.id AND pm.deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM color_sort cs WHERE cs.weight_type_id = wt.id AND cs.deleted_at IS NULL) as usage_count
+		FROM weight_types wt
+		WHERE wt.deleted_at IS NULL
+		ORDER BY usage_count DESC`)
+}
@@ -0,0 +1,90 @@
+package mysql
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"healing_photons/internal/store"
+	"strconv"
+)
+
+// WorkforceStore implements store.WorkforceStore
+type WorkforceStore struct {
+	db conn
+}
+
+func scanWorkforce(row scanner) (models.Workforce, error) {
+	var workforce models.Workforce
+	err := row.Scan(
+		&workforce.ID,
+		&workforce.Name,
+		&workforce.Aadhaar,
+		&workforce.Addresss,
+	)
+	return workforce, err
+}
+
+// List returns a page of workers matching opts
+func (s *WorkforceStore) List(ctx context.Context, opts store.ListOptions) (store.Page[models.Workforce], error) {
+	return listPage(ctx, s.db, scanWorkforce, store.WorkforceList, "workforce", `id, name, aadhaar, address`, opts)
+}
+
+// Get returns a single worker
+func (s *WorkforceStore) Get(ctx context.Context, id string) (models.Workforce, error) {
+	return queryOne(ctx, s.db, scanWorkforce, `
+		SELECT id, name, aadhaar, address
+		FROM workforce WHERE id = ? AND deleted_at IS NULL`, id)
+}
+
+// Create inserts a worker, picking up the auto-increment ID if the database
+// assigned one
+func (s *WorkforceStore) Create(ctx context.Context, workforce *models.Workforce) error {
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO workforce (
+			id, name, aadhaar, address
+		)
+		VALUES (?, ?, ?, ?)`,
+		workforce.ID,
+		workforce.Name,
+		workforce.Aadhaar,
+		workforce.Addresss,
+	)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if lastID != 0 {
+		workforce.ID = strconv.FormatInt(lastID, 10)
+	}
+	return nil
+}
+
+// Update overwrites an existing worker
+func (s *WorkforceStore) Update(ctx context.Context, id string, workforce models.Workforce) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE workforce
+		SET name = ?,
+			aadhaar = ?,
+			address = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		workforce.Name,
+		workforce.Aadhaar,
+		workforce.Addresss,
+		id,
+	)
+}
+
+// Delete moves a worker to the trash
+func (s *WorkforceStore) Delete(ctx context.Context, id string) error {
+	return execAffecting(ctx, s.db, `
+		UPDATE workforce
+		SET deleted_at = NOW(),
+			deleted_by = ?
+		WHERE id = ? AND deleted_at IS NULL`,
+		store.DeletedBy(ctx),
+		id,
+	)
+}
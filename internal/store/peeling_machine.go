@@ -0,0 +1,50 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// PeelingMachineStore persists peeling machine weighings
+type PeelingMachineStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.PeelingMachine], error)
+	Get(ctx context.Context, id string) (models.PeelingMachine, error)
+	Create(ctx context.Context, machine *models.PeelingMachine) error
+	// CreateMany inserts original records in one transaction. errs holds,
+	// at each record's index, why it couldn't be inserted. When atomic is
+	// set, any such failure rolls the whole batch back
+	CreateMany(ctx context.Context, machines []*models.PeelingMachine, atomic bool) (errs []error, err error)
+	// Correct stores the reversal of a record and, unless the record is
+	// only being voided, its replacement. It returns ErrNotFound when the
+	// reversed record doesn't exist and ErrConflict when it is a reversal or
+	// has already been corrected
+	Correct(ctx context.Context, reversal, replacement *models.PeelingMachine) error
+	// Delete moves an original record to the trash, see TrashStore. It
+	// returns ErrConflict when the record is a reversal or replacement, or
+	// has been corrected; those stay on the ledger
+	Delete(ctx context.Context, id string) error
+}
+
+// PeelingMachineList describes how peeling machine records can be listed
+var PeelingMachineList = ListSpec[models.PeelingMachine]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "weight", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "humidifier_id", "weight_type_id", "device_id", "entry_type", "corrects_id"},
+	Fields: map[string]Field[models.PeelingMachine]{
+		"id":             Text(func(m models.PeelingMachine) string { return m.ID }),
+		"humidifier_id":  Text(func(m models.PeelingMachine) string { return m.HumidifierID }),
+		"stock_id":       OptionalText(func(m models.PeelingMachine) *string { return m.StockID }),
+		"weight_type_id": Number(func(m models.PeelingMachine) int { return m.WeightTypeID }),
+		"weight":         Number(func(m models.PeelingMachine) float64 { return m.Weight }),
+		"device_id":      OptionalInt64(func(m models.PeelingMachine) *int64 { return m.DeviceID }),
+		"entry_type":     Text(func(m models.PeelingMachine) string { return string(m.EntryType) }),
+		"corrects_id":    OptionalText(func(m models.PeelingMachine) *string { return m.CorrectsID }),
+		"created_at":     Timestamp(func(m models.PeelingMachine) time.Time { return m.CreatedAt }),
+		"updated_at":     Timestamp(func(m models.PeelingMachine) time.Time { return m.UpdatedAt }),
+	},
+}
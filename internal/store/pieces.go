@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// PieceStore persists the kernel piece types
+type PieceStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Pieces], error)
+	Get(ctx context.Context, id int) (models.Pieces, error)
+	// Create inserts the piece, assigning an ID when none is set
+	Create(ctx context.Context, piece *models.Pieces) error
+	Update(ctx context.Context, id int, piece models.Pieces) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id int) error
+}
+
+// PieceList describes how pieces can be listed
+var PieceList = ListSpec[models.Pieces]{
+	Key:         "piece_id",
+	DefaultSort: Sort{Field: "piece_id"},
+	Trashable:   true,
+	Sortable:    []string{"piece_id", "piece_code"},
+	Filterable:  []string{"piece_code"},
+	Fields: map[string]Field[models.Pieces]{
+		"piece_id":   Number(func(p models.Pieces) int { return p.PieceID }),
+		"piece_code": Text(func(p models.Pieces) string { return p.PieceCode }),
+	},
+}
@@ -0,0 +1,14 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// ReportStore computes aggregates that span several tables
+type ReportStore interface {
+	// StageTotals sums the records of every processing stage for a lot
+	StageTotals(ctx context.Context, stockID string) (models.StockStageTotals, error)
+	// GradeWeights sums the manual grading of each lot by grading category
+	GradeWeights(ctx context.Context, stockIDs []string) ([]models.GradeWeight, error)
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// SellerStore persists the suppliers stock lots are bought from
+type SellerStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Seller], error)
+	Get(ctx context.Context, id int64) (models.Seller, error)
+	// GetByName returns the seller whose name matches ignoring case and
+	// spacing
+	GetByName(ctx context.Context, name string) (models.Seller, error)
+	// Create inserts the seller, assigning an ID when none is set
+	Create(ctx context.Context, seller *models.Seller) error
+	// Update overwrites a seller and renames the stock lots linked to it
+	Update(ctx context.Context, id int64, seller models.Seller) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id int64) error
+}
+
+// SellerList describes how sellers can be listed
+var SellerList = ListSpec[models.Seller]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "name"},
+	TimeField:   "created_at",
+	Trashable:   true,
+	Sortable:    []string{"id", "name", "country", "created_at", "updated_at"},
+	Filterable:  []string{"name", "gstin", "country"},
+	Fields: map[string]Field[models.Seller]{
+		"id":         Number(func(s models.Seller) int64 { return s.ID }),
+		"name":       Text(func(s models.Seller) string { return s.Name }),
+		"gstin":      OptionalText(func(s models.Seller) *string { return s.GSTIN }),
+		"country":    Text(func(s models.Seller) string { return s.Country }),
+		"created_at": Timestamp(func(s models.Seller) time.Time { return s.CreatedAt }),
+		"updated_at": Timestamp(func(s models.Seller) time.Time { return s.UpdatedAt }),
+	},
+}
@@ -0,0 +1,10 @@
+package store
+
+import "context"
+
+// SequenceStore hands out the numbers in server-minted record IDs
+type SequenceStore interface {
+	// Next increments the named sequence and returns its new value,
+	// starting at 1. Concurrent callers always get different values
+	Next(ctx context.Context, name string) (int64, error)
+}
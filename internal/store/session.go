@@ -0,0 +1,19 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// SessionStore persists login sessions and their refresh tokens
+type SessionStore interface {
+	// Create inserts the session, assigning an ID
+	Create(ctx context.Context, session *models.Session) error
+	// GetByTokenHash returns the session a refresh token belongs to
+	GetByTokenHash(ctx context.Context, tokenHash string) (models.Session, error)
+	// Revoke ends a session. It returns ErrConflict when the session had
+	// already been revoked, so a refresh token can only be used once
+	Revoke(ctx context.Context, id int64) error
+	// RevokeUser ends every active session of a user
+	RevokeUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// SizeVariationStore persists the kernel size variations
+type SizeVariationStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.SizeVariations], error)
+	Get(ctx context.Context, id int) (models.SizeVariations, error)
+	// Create inserts the size variation, assigning an ID when none is set
+	Create(ctx context.Context, variation *models.SizeVariations) error
+	Update(ctx context.Context, id int, variation models.SizeVariations) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id int) error
+}
+
+// SizeVariationList describes how size variations can be listed
+var SizeVariationList = ListSpec[models.SizeVariations]{
+	Key:         "size_id",
+	DefaultSort: Sort{Field: "size_id"},
+	Trashable:   true,
+	Sortable:    []string{"size_id", "size_value"},
+	Filterable:  []string{"size_value"},
+	Fields: map[string]Field[models.SizeVariations]{
+		"size_id":    Number(func(v models.SizeVariations) int { return v.SizeID }),
+		"size_value": Number(func(v models.SizeVariations) int { return v.SizeValue }),
+	},
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// StockStore persists raw nut stock lots
+type StockStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Stock], error)
+	Get(ctx context.Context, id string) (models.Stock, error)
+	// Create inserts the stock. It returns a ConstraintError when the ID is
+	// taken, even by a lot in the trash; those are brought back with
+	// TrashStore.Restore
+	Create(ctx context.Context, stock *models.Stock) error
+	Update(ctx context.Context, id string, stock models.Stock) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id string) error
+	// Transition moves a lot from one status to another and records the
+	// change. It returns ErrConflict when the lot is no longer in from
+	Transition(ctx context.Context, id string, from, to models.StockStatus, note string) (models.StockTransition, error)
+	// ListTransitions returns a page of recorded status changes
+	ListTransitions(ctx context.Context, opts ListOptions) (Page[models.StockTransition], error)
+}
+
+// StockList describes how stock lots can be listed
+var StockList = ListSpec[models.Stock]{
+	Key:         "stock_id",
+	DefaultSort: Sort{Field: "created_at", Desc: true},
+	TimeField:   "date",
+	Trashable:   true,
+	Sortable:    []string{"stock_id", "seller_name", "origin_country", "weight", "date", "created_at", "updated_at"},
+	Filterable:  []string{"stock_id", "seller_id", "seller_name", "origin_country", "status"},
+	Fields: map[string]Field[models.Stock]{
+		"stock_id":       Text(func(s models.Stock) string { return s.StockID }),
+		"seller_id":      OptionalInt64(func(s models.Stock) *int64 { return s.SellerID }),
+		"seller_name":    Text(func(s models.Stock) string { return s.SellerName }),
+		"origin_country": Text(func(s models.Stock) string { return s.OriginCountry }),
+		"weight":         Float32(func(s models.Stock) float32 { return s.Weight }),
+		"date":           Timestamp(func(s models.Stock) time.Time { return s.Date }),
+		"status":         Text(func(s models.Stock) string { return string(s.Status) }),
+		"created_at":     Timestamp(func(s models.Stock) time.Time { return s.CreatedAt }),
+		"updated_at":     Timestamp(func(s models.Stock) time.Time { return s.UpdatedAt }),
+	},
+}
+
+// StockTransitionList describes how the status history can be listed
+var StockTransitionList = ListSpec[models.StockTransition]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "created_at"},
+	TimeField:   "created_at",
+	Sortable:    []string{"id", "created_at"},
+	Filterable:  []string{"stock_id", "to_status"},
+	Fields: map[string]Field[models.StockTransition]{
+		"id":         Number(func(t models.StockTransition) int64 { return t.ID }),
+		"stock_id":   Text(func(t models.StockTransition) string { return t.StockID }),
+		"to_status":  Text(func(t models.StockTransition) string { return string(t.ToStatus) }),
+		"created_at": Timestamp(func(t models.StockTransition) time.Time { return t.CreatedAt }),
+	},
+}
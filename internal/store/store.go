@@ -0,0 +1,40 @@
+package store
+
+import "errors"
+
+// ErrNotFound is returned when the requested record does not exist
+var ErrNotFound = errors.New("record not found")
+
+// ErrConflict is returned when a record changed underneath a conditional
+// write
+var ErrConflict = errors.New("record was changed by another request")
+
+// Stores bundles the storage backends for every entity so routes can be
+// wired from a single value
+type Stores struct {
+	Stocks               StockStore
+	Humidifiers          HumidifierStore
+	PeelingMachines      PeelingMachineStore
+	ColorSorts           ColorSortStore
+	MachineGradings      MachineGradingStore
+	ManualGradings       ManualGradingStore
+	ManualGradingInputs  ManualGradingInputStore
+	GradingSheets        GradingSheetStore
+	GraderMachineOutputs GraderMachineOutputStore
+	GradingCategories    GradingCategoryStore
+	Pieces               PieceStore
+	SizeVariations       SizeVariationStore
+	WeightTypes          WeightTypeStore
+	Workforce            WorkforceStore
+	Users                UserStore
+	Sessions             SessionStore
+	Sellers              SellerStore
+	Devices              DeviceStore
+	Audit                AuditStore
+	Anomalies            AnomalyStore
+	Trash                TrashStore
+	Sequences            SequenceStore
+	Idempotency          IdempotencyStore
+	Reports              ReportStore
+	UnitOfWork           UnitOfWork
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+	"time"
+)
+
+// TrashStore reaches the records deleted from every trashable entity. IDs
+// are given as strings whatever the entity's key type
+type TrashStore interface {
+	// List returns a page of deleted records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.TrashItem], error)
+	// Get returns a deleted record. It returns ErrNotFound when the record
+	// doesn't exist or isn't in the trash
+	Get(ctx context.Context, entity, id string) (models.TrashItem, error)
+	// Restore takes a record out of the trash
+	Restore(ctx context.Context, entity, id string) error
+	// Purge removes a deleted record for good. It returns ErrConflict when
+	// other records still point at it
+	Purge(ctx context.Context, entity, id string) error
+}
+
+// TrashList describes how the trash can be listed, most recently deleted
+// first
+var TrashList = ListSpec[models.TrashItem]{
+	Key:         "ref",
+	DefaultSort: Sort{Field: "deleted_at", Desc: true},
+	TimeField:   "deleted_at",
+	Sortable:    []string{"entity", "deleted_at"},
+	Filterable:  []string{"entity", "deleted_by"},
+	Fields: map[string]Field[models.TrashItem]{
+		// ref tells apart records of different entities sharing an ID
+		"ref":        Text(func(t models.TrashItem) string { return t.Entity + "/" + t.ID }),
+		"entity":     Text(func(t models.TrashItem) string { return t.Entity }),
+		"deleted_by": Text(func(t models.TrashItem) string { return t.DeletedBy }),
+		"deleted_at": Timestamp(func(t models.TrashItem) time.Time { return t.DeletedAt }),
+	},
+}
+
+// DeletedBy names the actor in ctx for a record's deleted_by column
+func DeletedBy(ctx context.Context) string {
+	actor, ok := ActorFrom(ctx)
+	if !ok {
+		return models.ActorSystem
+	}
+	if actor.Name == "" {
+		return actor.Type
+	}
+	return actor.Name
+}
@@ -0,0 +1,28 @@
+package store
+
+import "context"
+
+// UnitOfWork runs several store calls as one operation, so either all of
+// their changes are kept or none are
+type UnitOfWork interface {
+	// Do calls work with stores whose changes are committed together when
+	// it returns nil, and rolled back when it returns an error. Reads
+	// through the stores see the unit's own writes. Units can be nested;
+	// an inner unit that fails only undoes its own changes
+	Do(ctx context.Context, work func(stores *Stores) error) error
+}
+
+type forUpdateKey struct{}
+
+// ForUpdate marks reads made with the returned context as ones a write in
+// the same unit of work depends on. The rows they return stay locked
+// against other units until the unit ends
+func ForUpdate(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forUpdateKey{}, true)
+}
+
+// Locking reports whether ctx was marked by ForUpdate
+func Locking(ctx context.Context) bool {
+	locking, _ := ctx.Value(forUpdateKey{}).(bool)
+	return locking
+}
@@ -0,0 +1,16 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// UserStore persists the accounts allowed to sign in
+type UserStore interface {
+	List(ctx context.Context) ([]models.User, error)
+	Get(ctx context.Context, id int64) (models.User, error)
+	GetByUsername(ctx context.Context, username string) (models.User, error)
+	// Create inserts the user, assigning an ID when none is set
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, id int64, user models.User) error
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// WeightTypeStore persists the weight types
+type WeightTypeStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.WeightTypes], error)
+	Get(ctx context.Context, id string) (models.WeightTypes, error)
+	// Create inserts the weight type, assigning an ID when none is set
+	Create(ctx context.Context, weightType *models.WeightTypes) error
+	Update(ctx context.Context, id string, weightType models.WeightTypes) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id string) error
+	// Usage returns every weight type with its usage count, most used first
+	Usage(ctx context.Context) ([]models.WeightTypeUsage, error)
+}
+
+// WeightTypeList describes how weight types can be listed
+var WeightTypeList = ListSpec[models.WeightTypes]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "id"},
+	Trashable:   true,
+	Sortable:    []string{"id", "type"},
+	Filterable:  []string{"type"},
+	Fields: map[string]Field[models.WeightTypes]{
+		"id":   Text(func(w models.WeightTypes) string { return w.ID }),
+		"type": Text(func(w models.WeightTypes) string { return w.Type }),
+	},
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"healing_photons/internal/models"
+)
+
+// WorkforceStore persists the plant workers
+type WorkforceStore interface {
+	// List returns a page of records matching opts
+	List(ctx context.Context, opts ListOptions) (Page[models.Workforce], error)
+	Get(ctx context.Context, id string) (models.Workforce, error)
+	// Create inserts the worker, assigning an ID when none is set
+	Create(ctx context.Context, workforce *models.Workforce) error
+	Update(ctx context.Context, id string, workforce models.Workforce) error
+	// Delete moves the record to the trash, see TrashStore
+	Delete(ctx context.Context, id string) error
+}
+
+// WorkforceList describes how workers can be listed
+var WorkforceList = ListSpec[models.Workforce]{
+	Key:         "id",
+	DefaultSort: Sort{Field: "id"},
+	Trashable:   true,
+	Sortable:    []string{"id", "name"},
+	Filterable:  []string{"name"},
+	Fields: map[string]Field[models.Workforce]{
+		"id":   Text(func(w models.Workforce) string { return w.ID }),
+		"name": Text(func(w models.Workforce) string { return w.Name }),
+	},
+}
@@ -1,22 +1,40 @@
 package main
 
 import (
+	"context"
+	"healing_photons/internal/auth"
+	"healing_photons/internal/cli"
 	"healing_photons/internal/config"
 	"healing_photons/internal/database"
 	"healing_photons/internal/handlers"
+	"healing_photons/internal/plant"
+	"healing_photons/internal/store/audited"
+	"healing_photons/internal/store/mysql"
 	"log"
+	"os"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Run an admin command instead of the server when one is given
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Access tokens are signed with a secret shared by every instance
+	tokens, err := auth.NewTokens(cfg.TokenSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	if err != nil {
+		log.Fatalf("Invalid TOKEN_SECRET: %v", err)
+	}
+
 	// Initialize database connection
 	db, err := database.InitializeDB(cfg)
 	if err != nil {
@@ -24,31 +42,85 @@ func main() {
 	}
 	defer db.Close()
 
+	// Apply pending schema migrations when enabled
+	if cfg.AutoMigrate {
+		applied, err := database.MigrateUp(context.Background(), db)
+		if err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		log.Printf("Applied %d migration(s)", len(applied))
+	}
+
 	// Setup Gin router
 	router := gin.Default()
 
-	// Add CORS middleware
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"}, // Allow all origins
-		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
+	// Add CORS middleware. Tokens travel in the Authorization header, so
+	// credentials are only allowed for explicitly configured origins
+	corsConfig := cors.Config{
+		AllowMethods:  []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "If-Match", "If-None-Match", "Idempotency-Key"},
+		ExposeHeaders: []string{"Content-Length", "ETag", "Idempotent-Replayed", "Retry-After"},
+	}
+	if len(cfg.CORSOrigins) > 0 {
+		corsConfig.AllowOrigins = cfg.CORSOrigins
+		corsConfig.AllowCredentials = true
+	} else {
+		corsConfig.AllowAllOrigins = true
+	}
+	router.Use(cors.New(corsConfig))
+
+	// Interpret dates in queries as the plant's working days
+	router.Use(handlers.UsePlantCalendar(plant.Calendar{
+		Location: cfg.PlantLocation,
+		DayStart: cfg.PlantDayStart,
 	}))
 
+	// Mint lot and stage record IDs with the plant's patterns
+	router.Use(handlers.UseIDScheme(cfg.IDScheme))
+
+	// Check stage weights against the stage before them
+	router.Use(handlers.UsePlausibility(cfg.Plausibility))
+
+	// Initialize storage. Every change is written to the audit log
+	stores := audited.NewStores(mysql.NewStores(db))
+
+	// Signing in is the only thing anonymous callers can do; every route
+	// registered after RequireAuth needs a valid access token, and a role
+	// the route policy allows. Shop-floor devices use an API key instead
+	handlers.SetupAuthRoutes(router, stores, tokens)
+	router.Use(handlers.AuthenticateDevice(stores.Devices))
+	router.Use(handlers.RequireAuth(tokens))
+	router.Use(handlers.Authorize(handlers.RoutePolicy))
+
+	// Retried creates with an Idempotency-Key get the original response
+	router.Use(handlers.Idempotent(stores.Idempotency, cfg.IdempotencyTTL, cfg.IdempotencyLease))
+	go handlers.SweepIdempotencyKeys(context.Background(), stores.Idempotency, cfg.IdempotencyTTL, cfg.IdempotencySweep)
+
 	// Initialize routes
-	handlers.SetupRoutes(router, db)
-	handlers.SetupWeightTypeRoutes(router, db)
-	handlers.SetupPeelingMachineRoutes(router, db)
-	handlers.SetupHumidifierRoutes(router, db)
-	handlers.SetupGraderMachineOutputRoutes(router, db)
-	handlers.SetupMachineGradingRoutes(router, db)
-	handlers.SetupColorSortRoutes(router, db)
-	handlers.SetupPiecesRoutes(router, db)
-	handlers.SetupSizeVariationsRoutes(router, db)
-	handlers.SetupManualGradingInputRoutes(router, db)
-	handlers.SetupWorkforceRoutes(router, db)
-	handlers.SetupGradingCategoryRoutes(router, db)
+	handlers.SetupRoutes(router, stores)
+	handlers.SetupWeightTypeRoutes(router, stores)
+	handlers.SetupPeelingMachineRoutes(router, stores)
+	handlers.SetupHumidifierRoutes(router, stores)
+	handlers.SetupGraderMachineOutputRoutes(router, stores)
+	handlers.SetupMachineGradingRoutes(router, stores)
+	handlers.SetupColorSortRoutes(router, stores)
+	handlers.SetupPiecesRoutes(router, stores)
+	handlers.SetupSizeVariationsRoutes(router, stores)
+	handlers.SetupManualGradingRoutes(router, stores)
+	handlers.SetupManualGradingInputRoutes(router, stores)
+	handlers.SetupWorkforceRoutes(router, stores)
+	handlers.SetupGradingCategoryRoutes(router, stores)
+	handlers.SetupOutturnRoutes(router, stores)
+	handlers.SetupSellerRoutes(router, stores)
+	handlers.SetupGradingSheetRoutes(router, stores)
+	handlers.SetupDeviceRoutes(router, stores)
+	handlers.SetupAuditRoutes(router, stores)
+	handlers.SetupTrashRoutes(router, stores)
+
+	// A route without a policy entry would refuse every caller
+	if missing := handlers.RoutePolicy.Missing(router.Routes()); len(missing) > 0 {
+		log.Fatalf("Routes missing from the role policy: %v", missing)
+	}
 
 	// Start server
 	port := cfg.Port